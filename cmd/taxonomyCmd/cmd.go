@@ -3,9 +3,12 @@ package taxonomyCmd
 import (
 	"flag"
 	"os"
+	"strings"
 
 	"github.com/kvql/bunsceal/pkg/config"
+	"github.com/kvql/bunsceal/pkg/domain"
 	"github.com/kvql/bunsceal/pkg/o11y"
+	"github.com/kvql/bunsceal/pkg/policy"
 	"github.com/kvql/bunsceal/pkg/taxonomy/application"
 	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
 	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
@@ -14,12 +17,36 @@ import (
 
 // Execute runs the taxonomy command with configured flags.
 func Execute() {
+	subcommands := map[string]func([]string) error{
+		"check-resources": checkResources,
+		"plan-check":      planCheck,
+		"diff":            diffTaxonomies,
+		"compat":          compatCheck,
+		"query":           queryLabels,
+	}
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				o11y.Log.Println(err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Define command line flags
 	localExport := flag.String("localExport", "", "Path for the taxonomy to be exported to a local JSON file")
+	groups := flag.String("group", "", "Comma separated group IDs to limit the -localExport to")
 	verify := flag.Bool("verify", false, "Validate the taxonomy")
 	graph := flag.Bool("graph", false, "Generate diagrams to visualise the taxonomy")
 	graphDir := flag.String("graphDir", ".tmp", "Directory for the graph visualisations")
 	configPath := flag.String("config", "", "Path to config.yaml (default: <taxDir>/config.yaml)")
+	ref := flag.String("ref", "", "Git ref, or a file name from -localExport, to load the taxonomy from instead of the working tree")
+	networkReport := flag.Bool("networkReport", false, "Report unallocated address space per L1 (requires network plugin)")
+	networkLookup := flag.String("networkLookup", "", "IP address to resolve to its owning segment (requires network plugin)")
+	cloudIndex := flag.String("cloudAccountIndex", "", "Path to write the cloud account to segment JSON index (requires cloud plugin)")
+	policyDir := flag.String("policyDir", "", "Directory to write Kubernetes, Cilium and AWS security group policies generated from flows")
+	reviewDue := flag.Bool("reviewReport", false, "Report attestations that are stale or expire soon (requires review plugin)")
 
 	// Parse command line flags
 	flag.Parse()
@@ -33,7 +60,19 @@ func Execute() {
 
 	// Validate and Load the taxonomy and validate it
 	// required for all actions
-	tax, err := application.LoadTaxonomy(cfg)
+	var tax domain.Taxonomy
+	exportFile := ""
+	if *ref != "" {
+		gitRef := *ref
+		if commit, ok := infrastructure.CommitFromExportFileName(gitRef); ok {
+			gitRef = commit
+		}
+		var commit string
+		tax, commit, err = application.LoadTaxonomyAtRef(cfg, ".", gitRef)
+		exportFile = infrastructure.ExportFileName(commit)
+	} else {
+		tax, err = application.LoadTaxonomy(cfg)
+	}
 
 	if err != nil {
 		o11y.Log.Println("Taxonomy content is not valid")
@@ -43,7 +82,19 @@ func Execute() {
 
 	// Generate local JSON file of the taxonomy
 	if *localExport != "" {
-		err = infrastructure.GenLocalTaxonomy(tax, *localExport)
+		exportTax := tax
+		if *groups != "" {
+			exportTax, err = application.FilterGroups(tax, strings.Split(*groups, ","))
+			if err != nil {
+				o11y.Log.Printf("Failed to filter taxonomy by group: %v", err)
+				os.Exit(1)
+			}
+		}
+		if exportFile != "" {
+			err = infrastructure.GenLocalTaxonomyFile(exportTax, *localExport, exportFile)
+		} else {
+			err = infrastructure.GenLocalTaxonomy(exportTax, *localExport)
+		}
 		if err != nil {
 			o11y.Log.Println("Failed to export taxonomy to local JSON file")
 			os.Exit(1)
@@ -56,6 +107,43 @@ func Execute() {
 		o11y.Log.Printf("error loading plugins: %s", err)
 		os.Exit(1)
 	}
+	// Network plugin reporting
+	if *networkReport || *networkLookup != "" {
+		if err = networkCommands(tax, pluginsList, *networkReport, *networkLookup); err != nil {
+			o11y.Log.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	// Cloud account reverse index
+	if *cloudIndex != "" {
+		cloudPlugin, ok := pluginsList["cloud"].(*plugins.CloudPlugin)
+		if !ok {
+			o11y.Log.Println("cloud plugin is not configured")
+			os.Exit(1)
+		}
+		if err = infrastructure.WriteJSONFile(cloudPlugin.AccountIndex(&tax), *cloudIndex); err != nil {
+			o11y.Log.Printf("Failed to write cloud account index: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Attestation expiry report
+	if *reviewDue {
+		if err = reviewReport(tax, pluginsList); err != nil {
+			o11y.Log.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	// Network policies generated from flows
+	if *policyDir != "" {
+		if err = policy.GeneratePolicies(tax, *policyDir, cfg.Policies); err != nil {
+			o11y.Log.Printf("Failed to generate policies: %v", err)
+			os.Exit(1)
+		}
+	}
+
 	// Validate the taxonomy
 	if *verify {
 		if !vis.ValidateImageVersions(pluginsList) {
@@ -0,0 +1,67 @@
+package taxonomyCmd
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/kvql/bunsceal/pkg/taxonomy/application"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
+)
+
+// compatCheck runs the compat subcommand, failing when the taxonomy removes anything
+// consumers of a published export rely on without deprecating it first.
+func compatCheck(args []string) error {
+	fs := flag.NewFlagSet("compat", flag.ContinueOnError)
+	published := fs.String("published", "", "Published taxonomy export (JSON) to check compatibility against")
+	source := fs.String("source", ".", "Current taxonomy: config file, directory with config.yaml, taxonomy directory or git ref")
+	configPath := fs.String("config", "", "Path to config.yaml for git refs and taxonomy directories without one (default: ./config.yaml)")
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *published == "" {
+		return errors.New("compat requires -published")
+	}
+
+	publishedTax, resolved, err := infrastructure.LoadTaxonomyExport(*published)
+	if err != nil {
+		return err
+	}
+	cfg, currentTax, err := loadTaxonomyAt(*source, *configPath)
+	if err != nil {
+		return err
+	}
+
+	// Older exports only carry declared labels, resolve inherited ones the same way as the current taxonomy
+	if !resolved {
+		pluginsList := make(plugins.Plugins)
+		if err = pluginsList.LoadPlugins(cfg.Plugins); err != nil {
+			return fmt.Errorf("error loading plugins: %w", err)
+		}
+		if err = application.ApplyInheritance(&publishedTax, pluginsList); err != nil {
+			return fmt.Errorf("cannot resolve published taxonomy %s: %w", *published, err)
+		}
+	}
+
+	report := application.CheckCompat(publishedTax, currentTax, cfg.Compat)
+	if *jsonOutput {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	} else {
+		for _, issue := range report.Issues {
+			fmt.Println(issue)
+		}
+		fmt.Printf("%d breaking change(s), %d addition(s); suggested bump: %s (apiVersion %s -> %s)\n",
+			len(report.Issues), report.Additions, report.Bump, report.PublishedVersion, report.SuggestedVersion)
+	}
+	if report.Failed() {
+		return errors.New("taxonomy removes published segments or labels without deprecating them")
+	}
+	return nil
+}
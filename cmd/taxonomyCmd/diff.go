@@ -0,0 +1,100 @@
+package taxonomyCmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kvql/bunsceal/pkg/config"
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+// loadTaxonomyAt loads the taxonomy at source: a config file, a directory containing
+// config.yaml, or a taxonomy directory loaded with the config at configPath.
+// A source that isn't a path is a git ref, loaded with the config at configPath.
+func loadTaxonomyAt(source, configPath string) (configdomain.Config, domain.Taxonomy, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		cfg, err := config.LoadConfig(configPath, "")
+		if err != nil {
+			return configdomain.Config{}, domain.Taxonomy{}, fmt.Errorf("failed to load configuration: %w", err)
+		}
+		tax, _, err := application.LoadTaxonomyAtRef(cfg, ".", source)
+		if err != nil {
+			return configdomain.Config{}, domain.Taxonomy{}, fmt.Errorf("cannot load taxonomy from %s: %w", source, err)
+		}
+		return cfg, tax, nil
+	}
+
+	taxonomyDir := ""
+	switch {
+	case !info.IsDir():
+		configPath = source
+	case fileExists(filepath.Join(source, "config.yaml")):
+		configPath = filepath.Join(source, "config.yaml")
+	default:
+		taxonomyDir = source
+	}
+
+	cfg, err := config.LoadConfig(configPath, "")
+	if err != nil {
+		return configdomain.Config{}, domain.Taxonomy{}, fmt.Errorf("failed to load configuration for %s: %w", source, err)
+	}
+	if taxonomyDir != "" {
+		cfg.FsRepository.TaxonomyDir = taxonomyDir
+	}
+	tax, err := application.LoadTaxonomy(cfg)
+	if err != nil {
+		return configdomain.Config{}, domain.Taxonomy{}, fmt.Errorf("taxonomy at %s is not valid", source)
+	}
+	return cfg, tax, nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// diffTaxonomies runs the diff subcommand, reporting semantic changes from base to head.
+func diffTaxonomies(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	base := fs.String("base", "", "Base taxonomy: config file, directory with config.yaml, taxonomy directory or git ref")
+	head := fs.String("head", "", "Head taxonomy: config file, directory with config.yaml, taxonomy directory or git ref")
+	configPath := fs.String("config", "", "Path to config.yaml for git refs and taxonomy directories without one (default: ./config.yaml)")
+	format := fs.String("format", application.DiffFormatText, "Output format: text, json or markdown")
+	output := fs.String("output", "", "File to write the diff to (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *base == "" || *head == "" {
+		return errors.New("diff requires -base and -head")
+	}
+
+	_, baseTax, err := loadTaxonomyAt(*base, *configPath)
+	if err != nil {
+		return err
+	}
+	headCfg, headTax, err := loadTaxonomyAt(*head, *configPath)
+	if err != nil {
+		return err
+	}
+	pluginsList := make(plugins.Plugins)
+	if err = pluginsList.LoadPlugins(headCfg.Plugins); err != nil {
+		return fmt.Errorf("error loading plugins: %w", err)
+	}
+
+	rendered, err := application.RenderDiff(application.DiffTaxonomies(baseTax, headTax, pluginsList), *format)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		return os.WriteFile(*output, []byte(rendered), 0600)
+	}
+	fmt.Print(rendered)
+	return nil
+}
@@ -0,0 +1,45 @@
+package taxonomyCmd
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+// networkCommands prints the free address space report and/or resolves an IP to its owning segment.
+func networkCommands(tax domain.Taxonomy, pluginsList plugins.Plugins, report bool, lookup string) error {
+	networkPlugin, ok := pluginsList["network"].(*plugins.NetworkPlugin)
+	if !ok {
+		return errors.New("network plugin is not configured")
+	}
+
+	if report {
+		free := networkPlugin.FreeSpace(&tax)
+		l1IDs := make([]string, 0, len(free))
+		for l1ID := range free {
+			l1IDs = append(l1IDs, l1ID)
+		}
+		sort.Strings(l1IDs)
+		for _, l1ID := range l1IDs {
+			o11y.Log.Printf("L1 %s free address space: %v", l1ID, free[l1ID])
+		}
+	}
+
+	if lookup != "" {
+		addr, err := netip.ParseAddr(lookup)
+		if err != nil {
+			return fmt.Errorf("invalid IP address %q: %w", lookup, err)
+		}
+		alloc, found := networkPlugin.Lookup(&tax, addr)
+		if !found {
+			return fmt.Errorf("no segment owns %s", addr)
+		}
+		o11y.Log.Printf("%s is owned by %s", addr, alloc)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package taxonomyCmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kvql/bunsceal/pkg/o11y"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application"
+	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
+)
+
+// planCheck runs the plan-check subcommand, a pipeline gate validating the resources
+// planned by Terraform against the taxonomy and printing a per-resource report.
+func planCheck(args []string) error {
+	fs := flag.NewFlagSet("plan-check", flag.ContinueOnError)
+	planPath := fs.String("plan", "", "Terraform plan JSON from terraform show -json <planfile>")
+	configPath := fs.String("config", "", "Path to config.yaml (default: <taxDir>/config.yaml)")
+	report := fs.String("report", "", "Path to write the per-resource report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *planPath == "" {
+		return errors.New("plan-check requires -plan")
+	}
+
+	cfg, tax, compliance, err := loadForResourceChecks(*configPath)
+	if err != nil {
+		return err
+	}
+	// #nosec G304 -- planPath comes from a CLI flag
+	data, err := os.ReadFile(*planPath)
+	if err != nil {
+		return fmt.Errorf("failed to read plan: %w", err)
+	}
+	resources, err := infrastructure.ParseTerraformPlan(data)
+	if err != nil {
+		return err
+	}
+
+	results := application.ResourceResults(resources, application.CheckResources(tax, resources, cfg.Resources, compliance), cfg.Resources)
+	if *report != "" {
+		if err = infrastructure.WriteJSONFile(results, *report); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Checked {
+			continue
+		}
+		if !result.Failed() {
+			fmt.Printf("PASS %s\n", result.ResourceID)
+		} else {
+			failed++
+			fmt.Printf("FAIL %s\n", result.ResourceID)
+		}
+		for _, finding := range result.Findings {
+			fmt.Printf("  - [%s] %s\n", finding.Kind, finding.Message)
+		}
+	}
+	o11y.Log.Printf("Plan check: %d planned resource(s), %d failed", len(resources), failed)
+	if failed > 0 {
+		return fmt.Errorf("%d planned resource(s) do not conform to the taxonomy", failed)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package taxonomyCmd
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// queryLabels runs the query subcommand, printing the segments whose effective labels match a selector.
+func queryLabels(args []string) error {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	selectorFlag := fs.String("l", "", "Label selector, e.g. 'bunsceal.plugin.compliance/pci-dss=in-scope,env in (prod,staging)'")
+	source := fs.String("source", ".", "Taxonomy: config file, directory with config.yaml, taxonomy directory or git ref")
+	configPath := fs.String("config", "", "Path to config.yaml for git refs and taxonomy directories without one (default: ./config.yaml)")
+	level := fs.Int("level", 0, "Only return segments of this level (default: all levels)")
+	format := fs.String("format", "text", "Output format: text, json or yaml")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *selectorFlag == "" {
+		return errors.New("query requires -l")
+	}
+	selector, err := domain.ParseLabelSelector(*selectorFlag)
+	if err != nil {
+		return err
+	}
+
+	_, txy, err := loadTaxonomyAt(*source, *configPath)
+	if err != nil {
+		return err
+	}
+	matches := []domain.SelectorMatch{}
+	for _, match := range txy.Select(selector) {
+		if *level == 0 || match.Level == *level {
+			matches = append(matches, match)
+		}
+	}
+
+	switch *format {
+	case "json":
+		out, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case "yaml":
+		out, err := yaml.Marshal(matches)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+	case "text":
+		for _, match := range matches {
+			fmt.Println(formatMatch(match))
+		}
+	default:
+		return fmt.Errorf("unknown format %q, expected text, json or yaml", *format)
+	}
+	return nil
+}
+
+// formatMatch renders a match as a single line: level, segment (prefixed by its parent below L1) and name.
+func formatMatch(match domain.SelectorMatch) string {
+	id := match.ID
+	if match.Parent != "" {
+		id = match.Parent + "/" + match.ID
+	}
+	return fmt.Sprintf("L%d\t%s\t%s", match.Level, id, match.Name)
+}
@@ -0,0 +1,73 @@
+package taxonomyCmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/kvql/bunsceal/pkg/config"
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
+)
+
+// loadForResourceChecks loads the config, the taxonomy and the compliance plugin, which is nil when not configured.
+func loadForResourceChecks(configPath string) (configdomain.Config, domain.Taxonomy, *plugins.CompliancePlugin, error) {
+	cfg, err := config.LoadConfig(configPath, "")
+	if err != nil {
+		return configdomain.Config{}, domain.Taxonomy{}, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	tax, err := application.LoadTaxonomy(cfg)
+	if err != nil {
+		return configdomain.Config{}, domain.Taxonomy{}, nil, errors.New("taxonomy content is not valid")
+	}
+	pluginsList := make(plugins.Plugins)
+	if err = pluginsList.LoadPlugins(cfg.Plugins); err != nil {
+		return configdomain.Config{}, domain.Taxonomy{}, nil, fmt.Errorf("error loading plugins: %w", err)
+	}
+	compliance, _ := pluginsList["compliance"].(*plugins.CompliancePlugin)
+	return cfg, tax, compliance, nil
+}
+
+// checkResources runs the check-resources subcommand, reporting inventory resources whose
+// segment or classification tags don't conform to the taxonomy.
+func checkResources(args []string) error {
+	fs := flag.NewFlagSet("check-resources", flag.ContinueOnError)
+	input := fs.String("input", "", "Inventory file: Terraform plan JSON, JSON resource export or CSV")
+	format := fs.String("format", "", "Inventory format, json or csv (default: from the file extension)")
+	configPath := fs.String("config", "", "Path to config.yaml (default: <taxDir>/config.yaml)")
+	report := fs.String("report", "", "Path to write the findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		return errors.New("check-resources requires -input")
+	}
+
+	cfg, tax, compliance, err := loadForResourceChecks(*configPath)
+	if err != nil {
+		return err
+	}
+	resources, err := infrastructure.LoadInventory(*input, *format)
+	if err != nil {
+		return err
+	}
+
+	findings := application.CheckResources(tax, resources, cfg.Resources, compliance)
+	if *report != "" {
+		if err = infrastructure.WriteJSONFile(findings, *report); err != nil {
+			return fmt.Errorf("failed to write findings: %w", err)
+		}
+	}
+	for _, finding := range findings {
+		o11y.Log.Printf("Non-conformant resource: %s", finding)
+	}
+	o11y.Log.Printf("Checked %d resource(s), %d finding(s)", len(resources), len(findings))
+	if len(application.BlockingFindings(findings)) > 0 {
+		return errors.New("resources do not conform to the taxonomy")
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package taxonomyCmd
+
+import (
+	"errors"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+// reviewReport prints attestations that have expired or are due to expire soon.
+func reviewReport(tax domain.Taxonomy, pluginsList plugins.Plugins) error {
+	reviewPlugin, ok := pluginsList["review"].(*plugins.ReviewPlugin)
+	if !ok {
+		return errors.New("review plugin is not configured")
+	}
+
+	upcoming := reviewPlugin.UpcomingExpiries(&tax)
+	if len(upcoming) == 0 {
+		o11y.Log.Println("No attestations expire within the report window")
+	}
+	for _, attestation := range upcoming {
+		o11y.Log.Printf("Attestation due: %s", attestation)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+// Package client reads a bunsceal taxonomy for use in other Go programs.
+//
+// A taxonomy is loaded from a directory with LoadDir, from an export file written by
+// `bunsceal -localExport` with LoadExport, from an export served over HTTP with LoadURL,
+// or from export bytes (e.g. embedded with go:embed) with LoadBytes.
+//
+// Compatibility: this package follows semantic versioning on its own, independent of the
+// rest of the module. The exported identifiers of this package and the JSON encoding of
+// Segment and Placement only change in backwards compatible ways within a major version.
+// Packages it wraps, such as pkg/domain and pkg/taxonomy, make no such promise, and none
+// of their types appear in this package's API.
+package client
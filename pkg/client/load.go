@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/kvql/bunsceal/pkg/config"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
+)
+
+// ErrUnresolvedExport is returned for exports that only carry declared labels when Options has no ConfigPath.
+var ErrUnresolvedExport = errors.New("export does not carry effective labels, set Options.ConfigPath to resolve inherited labels")
+
+// Options configure loading a taxonomy export.
+type Options struct {
+	// ConfigPath is the bunsceal config.yaml whose plugin settings resolve inherited labels.
+	// Exports carry the labels of each segment after inheritance, so it is only used for exports
+	// written before those were published, which only carry declared labels.
+	ConfigPath string
+	// HTTPClient fetches exports for LoadURL, http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// LoadDir loads and validates the taxonomy at path: a config file or a directory containing config.yaml.
+func LoadDir(path string) (*Taxonomy, error) {
+	configPath := path
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		configPath = filepath.Join(path, "config.yaml")
+	}
+	cfg, err := config.LoadConfig(configPath, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration %s: %w", configPath, err)
+	}
+	txy, err := application.LoadTaxonomy(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("taxonomy at %s is not valid: %w", path, err)
+	}
+	return newTaxonomy(txy), nil
+}
+
+// LoadExport loads a taxonomy export file.
+func LoadExport(path string, opts Options) (*Taxonomy, error) {
+	// #nosec G304 -- path is chosen by the caller
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export %s: %w", path, err)
+	}
+	return LoadBytes(data, opts)
+}
+
+// LoadURL loads a taxonomy export served over HTTP.
+func LoadURL(ctx context.Context, url string, opts Options) (*Taxonomy, error) {
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch export %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch export %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export %s: %w", url, err)
+	}
+	return LoadBytes(data, opts)
+}
+
+// LoadBytes loads a taxonomy export from its JSON.
+func LoadBytes(data []byte, opts Options) (*Taxonomy, error) {
+	txy, resolved, err := infrastructure.ParseTaxonomyExport(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid taxonomy export: %w", err)
+	}
+	if !resolved {
+		if opts.ConfigPath == "" {
+			return nil, ErrUnresolvedExport
+		}
+		if err = resolveInheritance(&txy, opts.ConfigPath); err != nil {
+			return nil, err
+		}
+	}
+	return newTaxonomy(txy), nil
+}
+
+// resolveInheritance applies inheritance to an export with the plugins configured at configPath.
+func resolveInheritance(txy *domain.Taxonomy, configPath string) error {
+	cfg, err := config.LoadConfig(configPath, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration %s: %w", configPath, err)
+	}
+	pluginsList := make(plugins.Plugins)
+	if err = pluginsList.LoadPlugins(cfg.Plugins); err != nil {
+		return fmt.Errorf("error loading plugins: %w", err)
+	}
+	if err = application.ApplyInheritance(txy, pluginsList); err != nil {
+		return fmt.Errorf("cannot resolve inherited labels: %w", err)
+	}
+	return nil
+}
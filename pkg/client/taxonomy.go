@@ -0,0 +1,201 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+// Segment is a segment of the taxonomy.
+type Segment struct {
+	Level       int                    `json:"level"`
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Aliases     []string               `json:"aliases,omitempty"`
+	Group       string                 `json:"group,omitempty"`   // L0 group, L1 only
+	Parents     []string               `json:"parents,omitempty"` // segments in the level above, none for L1
+	Labels      map[string]string      `json:"labels,omitempty"`  // declared and inherited labels, see LabelsIn for per-parent overrides
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Placement is a segment in one of its parents, Parent is empty for L1 segments.
+type Placement struct {
+	Level  int    `json:"level"`
+	ID     string `json:"id"`
+	Parent string `json:"parent,omitempty"`
+}
+
+// Taxonomy is a read-only view of a loaded taxonomy. Accessors accept segment aliases.
+type Taxonomy struct {
+	txy domain.Taxonomy
+}
+
+func newTaxonomy(txy domain.Taxonomy) *Taxonomy {
+	return &Taxonomy{txy: txy}
+}
+
+// APIVersion returns the apiVersion of the taxonomy.
+func (t *Taxonomy) APIVersion() string {
+	return t.txy.ApiVersion
+}
+
+// Depth returns the number of segment levels, at least 2.
+func (t *Taxonomy) Depth() int {
+	return t.txy.Depth()
+}
+
+// Segment returns the segment of the level with the ID or alias.
+func (t *Taxonomy) Segment(level int, id string) (Segment, bool) {
+	seg, _, ok := domain.ResolveSeg(t.txy.Segs(level), id)
+	if !ok {
+		return Segment{}, false
+	}
+	return newSegment(level, seg), true
+}
+
+// Segments returns the segments of the level sorted by ID, nil for levels the taxonomy doesn't have.
+func (t *Taxonomy) Segments(level int) []Segment {
+	return sortedSegments(level, t.txy.Segs(level), func(domain.Seg) bool { return true })
+}
+
+// ChildrenOf returns the segments of the level below with the segment of the level as a parent, sorted by ID.
+func (t *Taxonomy) ChildrenOf(level int, id string) []Segment {
+	parent, _, ok := domain.ResolveSeg(t.txy.Segs(level), id)
+	if !ok {
+		return nil
+	}
+	return sortedSegments(level+1, t.txy.Segs(level+1), func(seg domain.Seg) bool {
+		for _, p := range seg.L1Parents {
+			if p == parent.ID {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// LabelsIn returns the labels of the segment of the level in its parent, a segment of the level above.
+// L1 segments have no parent, pass an empty parent.
+func (t *Taxonomy) LabelsIn(level int, id, parent string) (map[string]string, error) {
+	seg, _, ok := domain.ResolveSeg(t.txy.Segs(level), id)
+	if !ok {
+		return nil, fmt.Errorf("unknown L%d segment %s", level, id)
+	}
+	if level == 1 {
+		return seg.EffectiveLabels(""), nil
+	}
+	parentSeg, _, ok := domain.ResolveSeg(t.txy.Segs(level-1), parent)
+	if !ok {
+		return nil, fmt.Errorf("unknown L%d segment %s", level-1, parent)
+	}
+	for _, p := range seg.L1Parents {
+		if p == parentSeg.ID {
+			return seg.EffectiveLabels(parentSeg.ID), nil
+		}
+	}
+	return nil, fmt.Errorf("L%d segment %s is not in L%d segment %s", level, seg.ID, level-1, parentSeg.ID)
+}
+
+// L1 returns the L1 segment with the ID or alias.
+func (t *Taxonomy) L1(id string) (Segment, bool) {
+	return t.Segment(1, id)
+}
+
+// L2 returns the L2 segment with the ID or alias.
+func (t *Taxonomy) L2(id string) (Segment, bool) {
+	return t.Segment(2, id)
+}
+
+// L1s returns the L1 segments sorted by ID.
+func (t *Taxonomy) L1s() []Segment {
+	return t.Segments(1)
+}
+
+// L2s returns the L2 segments sorted by ID.
+func (t *Taxonomy) L2s() []Segment {
+	return t.Segments(2)
+}
+
+// Children returns the L2 segments with the L1 as a parent, sorted by ID.
+func (t *Taxonomy) Children(l1 string) []Segment {
+	return t.ChildrenOf(1, l1)
+}
+
+// EffectiveLabels returns the labels of the L2 segment in the L1 parent.
+// An empty l2 returns the labels of the L1 segment.
+func (t *Taxonomy) EffectiveLabels(l2, l1 string) (map[string]string, error) {
+	if l2 == "" {
+		return t.LabelsIn(1, l1, "")
+	}
+	if _, _, ok := t.txy.ResolveL1(l1); !ok {
+		return nil, fmt.Errorf("unknown L1 segment %s", l1)
+	}
+	return t.LabelsIn(2, l2, l1)
+}
+
+// InScope returns the segments of every level, per parent below L1, that are in scope of the
+// compliance requirement, sorted by level, ID and parent.
+func (t *Taxonomy) InScope(requirement string) []Placement {
+	selector := domain.LabelSelector{Requirements: []domain.SelectorRequirement{{
+		Key:      plugins.NsPrefix + "compliance/" + requirement,
+		Operator: domain.SelectorEquals,
+		Values:   []string{plugins.ScopeInScope},
+	}}}
+	var placements []Placement
+	for _, match := range t.txy.Select(selector) {
+		placements = append(placements, Placement{Level: match.Level, ID: match.ID, Parent: match.Parent})
+	}
+	return placements
+}
+
+func newSegment(level int, seg domain.Seg) Segment {
+	result := Segment{
+		Level:       level,
+		ID:          seg.ID,
+		Name:        seg.Name,
+		Description: seg.Description,
+		Aliases:     append([]string(nil), seg.Aliases...),
+		Group:       seg.Group,
+		Parents:     append([]string(nil), seg.L1Parents...),
+		Labels:      seg.EffectiveLabels(""),
+	}
+	if len(seg.Metadata) > 0 {
+		result.Metadata = plainValue(seg.Metadata).(map[string]interface{})
+	}
+	return result
+}
+
+// plainValue copies metadata, replacing domain.Metadata maps so no internal types are returned.
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case domain.Metadata:
+		return plainValue(map[string]interface{}(v))
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			result[k] = plainValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = plainValue(item)
+		}
+		return result
+	}
+	return value
+}
+
+func sortedSegments(level int, segs map[string]domain.Seg, include func(domain.Seg) bool) []Segment {
+	var result []Segment
+	for _, seg := range segs {
+		if include(seg) {
+			result = append(result, newSegment(level, seg))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
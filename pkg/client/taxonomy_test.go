@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testExport = `{
+  "ApiVersion": "v1beta1",
+  "SegL1s": {
+    "prod": {
+      "id": "prod", "name": "Production", "description": "", "aliases": ["production"],
+      "labels": ["bunsceal.plugin.compliance/pci-dss:in-scope", "bunsceal.plugin.classifications/sensitivity:A", "team:platform"],
+      "effective_labels": {"bunsceal.plugin.compliance/pci-dss": "in-scope", "bunsceal.plugin.classifications/sensitivity": "A", "team": "platform"}
+    },
+    "staging": {
+      "id": "staging", "name": "Staging", "description": "",
+      "labels": ["bunsceal.plugin.classifications/sensitivity:C", "team:platform"],
+      "effective_labels": {"bunsceal.plugin.classifications/sensitivity": "C", "team": "platform"}
+    }
+  },
+  "SegsL2s": {
+    "app": {
+      "id": "app", "name": "App", "description": "",
+      "l1_parents": ["prod", "staging"],
+      "labels": ["team:payments"],
+      "effective_labels": {"bunsceal.plugin.classifications/sensitivity": "A", "team": "payments"},
+      "metadata": {"owner": {"email": "pay@example.com"}},
+      "l1_overrides": {
+        "prod": {"labels": ["bunsceal.plugin.compliance/pci-dss:in-scope"]},
+        "staging": {"labels": ["bunsceal.plugin.compliance/pci-dss:out-of-scope"]}
+      }
+    },
+    "web": {
+      "id": "web", "name": "Web", "description": "", "l1_parents": ["staging"],
+      "effective_labels": {"bunsceal.plugin.classifications/sensitivity": "C"}
+    }
+  },
+  "Levels": {
+    "3": {
+      "api": {
+        "id": "api", "name": "API", "description": "", "l1_parents": ["app"],
+        "l1_overrides": {"app": {"labels": ["bunsceal.plugin.compliance/pci-dss:in-scope"]}},
+        "effective_labels": {"bunsceal.plugin.classifications/sensitivity": "A"}
+      }
+    }
+  }
+}`
+
+func TestLoadBytes_Accessors(t *testing.T) {
+	txy, err := LoadBytes([]byte(testExport), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txy.APIVersion() != "v1beta1" {
+		t.Errorf("Expected apiVersion v1beta1, got %s", txy.APIVersion())
+	}
+
+	l1, ok := txy.L1("production")
+	if !ok || l1.ID != "prod" || l1.Labels["team"] != "platform" {
+		t.Errorf("Expected alias production to resolve to prod with labels, got %+v %v", l1, ok)
+	}
+	if _, ok := txy.L2("prod"); ok {
+		t.Error("Expected prod not to be an L2 segment")
+	}
+	l2, ok := txy.L2("app")
+	if !ok || len(l2.Parents) != 2 || l2.Metadata["owner"].(map[string]interface{})["email"] != "pay@example.com" {
+		t.Errorf("Expected app with parents and metadata, got %+v %v", l2, ok)
+	}
+
+	children := txy.Children("staging")
+	if len(children) != 2 || children[0].ID != "app" || children[1].ID != "web" {
+		t.Errorf("Expected staging children [app web], got %+v", children)
+	}
+	if children := txy.Children("missing"); children != nil {
+		t.Errorf("Expected no children for unknown L1, got %+v", children)
+	}
+
+	labels, err := txy.EffectiveLabels("app", "staging")
+	if err != nil || labels["bunsceal.plugin.compliance/pci-dss"] != "out-of-scope" || labels["team"] != "payments" {
+		t.Errorf("Expected app labels in staging, got %v %v", labels, err)
+	}
+	if _, err := txy.EffectiveLabels("web", "prod"); err == nil {
+		t.Error("Expected error for L2 not in L1")
+	}
+
+	web, _ := txy.L2("web")
+	if web.Labels["bunsceal.plugin.classifications/sensitivity"] != "C" {
+		t.Errorf("Expected web to carry its inherited sensitivity, got %v", web.Labels)
+	}
+}
+
+func TestLoadBytes_Levels(t *testing.T) {
+	txy, err := LoadBytes([]byte(testExport), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txy.Depth() != 3 || len(txy.Segments(3)) != 1 || txy.Segments(4) != nil {
+		t.Errorf("Expected one L3 segment and no L4, got depth %d", txy.Depth())
+	}
+	children := txy.ChildrenOf(2, "app")
+	if len(children) != 1 || children[0].ID != "api" || children[0].Level != 3 {
+		t.Errorf("Expected app children [api], got %+v", children)
+	}
+	labels, err := txy.LabelsIn(3, "api", "app")
+	if err != nil || labels["bunsceal.plugin.compliance/pci-dss"] != "in-scope" || labels["bunsceal.plugin.classifications/sensitivity"] != "A" {
+		t.Errorf("Expected api labels in app, got %v %v", labels, err)
+	}
+	if _, err := txy.LabelsIn(3, "api", "web"); err == nil {
+		t.Error("Expected error for L3 not in L2")
+	}
+}
+
+func TestLoadBytes_UnresolvedExport(t *testing.T) {
+	// Exports written before effective labels were published only carry declared labels
+	export := `{"ApiVersion": "v1beta1", "SegL1s": {"prod": {"id": "prod", "name": "Production", "description": "",
+		"labels": ["bunsceal.plugin.classifications/sensitivity:A"]}}, "SegsL2s": {}}`
+
+	_, err := LoadBytes([]byte(export), Options{})
+	if !errors.Is(err, ErrUnresolvedExport) {
+		t.Errorf("Expected ErrUnresolvedExport without a config, got %v", err)
+	}
+}
+
+func TestTaxonomy_InScope(t *testing.T) {
+	txy, err := LoadBytes([]byte(testExport), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	placements := txy.InScope("pci-dss")
+	expected := []Placement{{Level: 1, ID: "prod"}, {Level: 2, ID: "app", Parent: "prod"}, {Level: 3, ID: "api", Parent: "app"}}
+	if len(placements) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, placements)
+	}
+	for i := range expected {
+		if placements[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], placements[i])
+		}
+	}
+}
+
+func TestLoadExport_And_LoadURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+	if err := os.WriteFile(path, []byte(testExport), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if txy, err := LoadExport(path, Options{}); err != nil || len(txy.L1s()) != 2 {
+		t.Errorf("Expected export file to load, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/taxonomy.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testExport))
+	}))
+	defer server.Close()
+
+	if txy, err := LoadURL(context.Background(), server.URL+"/taxonomy.json", Options{}); err != nil || len(txy.L2s()) != 2 {
+		t.Errorf("Expected export to load over HTTP, got %v", err)
+	}
+	if _, err := LoadURL(context.Background(), server.URL+"/missing.json", Options{}); err == nil {
+		t.Error("Expected error for missing export")
+	}
+	if _, err := LoadBytes([]byte("{"), Options{}); err == nil {
+		t.Error("Expected error for invalid export")
+	}
+}
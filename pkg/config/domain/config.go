@@ -3,6 +3,7 @@ package domain
 
 import (
 	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/policy"
 	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
 	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
 	"github.com/kvql/bunsceal/pkg/visualise"
@@ -16,7 +17,11 @@ type Config struct {
 	Terminology  domain.TermConfig                  `yaml:"terminology"`
 	SchemaPath   string                             `yaml:"schema_path,omitempty"`
 	Visuals      visualise.VisualsDef               `yaml:"visuals,omitempty"`
+	Policies     policy.PoliciesDef                 `yaml:"policies,omitempty"`
+	Resources    ResourcesConfig                    `yaml:"resources,omitempty"`
+	Compat       CompatConfig                       `yaml:"compat,omitempty"`
 	Rules        LogicRulesConfig                   `yaml:"rules,omitempty"`
+	Repository   infrastructure.ConfigRepository    `yaml:"repository,omitempty"`
 	FsRepository infrastructure.ConfigFsReposistory `yaml:"fs_repository,omitempty"`
 	Plugins      plugins.ConfigPlugins              `yaml:"plugins"`
 }
@@ -36,12 +41,34 @@ func (c Config) Merge() Config {
 	if c.SchemaPath == "" {
 		result.SchemaPath = defaults.SchemaPath
 	}
+	if c.Repository.Type == "" {
+		result.Repository.Type = defaults.Repository.Type
+	}
 	if c.FsRepository.L1Dir == "" {
 		result.FsRepository.L1Dir = defaults.FsRepository.L1Dir
 	}
 	if c.FsRepository.L2Dir == "" {
 		result.FsRepository.L2Dir = defaults.FsRepository.L2Dir
 	}
+	if c.FsRepository.FlowsDir == "" {
+		result.FsRepository.FlowsDir = defaults.FsRepository.FlowsDir
+	}
+	if c.FsRepository.GroupsDir == "" {
+		result.FsRepository.GroupsDir = defaults.FsRepository.GroupsDir
+	}
+	if c.FsRepository.TemplatesDir == "" {
+		result.FsRepository.TemplatesDir = defaults.FsRepository.TemplatesDir
+	}
+	// Only an unset list takes the defaults, an explicit empty list reads every file
+	if c.FsRepository.FileGlobs == nil {
+		result.FsRepository.FileGlobs = defaults.FsRepository.FileGlobs
+	}
+	if c.Resources.L1TagKey == "" {
+		result.Resources.L1TagKey = defaults.Resources.L1TagKey
+	}
+	if c.Resources.L2TagKey == "" {
+		result.Resources.L2TagKey = defaults.Resources.L2TagKey
+	}
 	if c.FsRepository.TaxonomyDir == "" {
 		result.FsRepository.TaxonomyDir = defaults.FsRepository.TaxonomyDir
 	}
@@ -51,8 +78,11 @@ func (c Config) Merge() Config {
 
 // LogicRulesConfig holds configuration for business logic validation rules.
 type LogicRulesConfig struct {
-	SharedService GeneralBooleanConfig `yaml:"shared_service,omitempty"`
-	Uniqueness    UniquenessConfig     `yaml:"uniqueness,omitempty"`
+	SharedService      GeneralBooleanConfig     `yaml:"shared_service,omitempty"`
+	StrictestSegment   StrictestSegmentConfig   `yaml:"strictest_segment,omitempty"`
+	Uniqueness         UniquenessConfig         `yaml:"uniqueness,omitempty"`
+	FlowClassification FlowClassificationConfig `yaml:"flow_classification,omitempty"`
+	Custom             []CustomRuleConfig       `yaml:"custom,omitempty"`
 }
 
 // GeneralBooleanConfig provides a simple enabled/disabled configuration for rules.
@@ -60,10 +90,59 @@ type GeneralBooleanConfig struct {
 	Enabled bool `yaml:"enabled"`
 }
 
+// StrictestSegmentConfig lists segments that must hold the strictest classification values.
+// Keys are classification definition keys; empty checks every definition.
+type StrictestSegmentConfig struct {
+	Enabled              bool     `yaml:"enabled"`
+	L1IDs                []string `yaml:"l1_ids,omitempty"`
+	L2IDs                []string `yaml:"l2_ids,omitempty"`
+	Keys                 []string `yaml:"keys,omitempty"`
+	RequireAllCompliance bool     `yaml:"require_all_compliance,omitempty"`
+}
+
+// ResourcesConfig sets the tag keys used to check inventory resources against the taxonomy.
+// ClassificationTags maps classification keys to the tag key holding their value on resources.
+type ResourcesConfig struct {
+	L1TagKey           string            `yaml:"l1_tag_key,omitempty"`
+	L2TagKey           string            `yaml:"l2_tag_key,omitempty"`
+	ClassificationTags map[string]string `yaml:"classification_tags,omitempty"`
+}
+
+// CompatConfig lists removals announced to consumers, which the compat check allows.
+// Label keys are full namespaced keys, e.g. bunsceal.plugin.compliance/soc2.
+type CompatConfig struct {
+	DeprecatedSegments  []string `yaml:"deprecated_segments,omitempty"`
+	DeprecatedLabelKeys []string `yaml:"deprecated_label_keys,omitempty"`
+}
+
+// FlowClassificationConfig lists classification keys flows may not raise without an exception.
+// The rule is opt-in as it needs the classifications plugin; Keys defaults to sensitivity.
+type FlowClassificationConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Keys    []string `yaml:"keys,omitempty"`
+}
+
+// Uniqueness scopes: values must be unique across the whole taxonomy, or only within each L0 group.
+const (
+	UniquenessScopeTaxonomy = "taxonomy"
+	UniquenessScopeGroup    = "group"
+)
+
 // UniquenessConfig holds configuration for uniqueness validation rules.
 type UniquenessConfig struct {
 	Enabled   bool     `yaml:"enabled"`
 	CheckKeys []string `yaml:"check_keys,omitempty"`
+	Scope     string   `yaml:"scope,omitempty"`
+}
+
+// CustomRuleConfig defines an organisation specific rule as a boolean expression.
+// Selector limits which segments the rule applies to; empty selects every segment.
+type CustomRuleConfig struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description,omitempty"`
+	Severity    string `yaml:"severity,omitempty"`
+	Selector    string `yaml:"selector,omitempty"`
+	Expression  string `yaml:"expression"`
 }
 
 // DefaultConfig returns the default configuration.
@@ -87,10 +166,21 @@ func DefaultConfig() Config {
 				CheckKeys: []string{"name"},
 			},
 		},
+		Resources: ResourcesConfig{
+			L1TagKey: domain.SegmentL1LabelKey,
+			L2TagKey: domain.SegmentL2LabelKey,
+		},
+		Repository: infrastructure.ConfigRepository{
+			Type: infrastructure.RepositoryTypeFs,
+		},
 		FsRepository: infrastructure.ConfigFsReposistory{
-			TaxonomyDir: "taxonomy",
-			L1Dir:       "environments",
-			L2Dir:       "segments",
+			TaxonomyDir:  "taxonomy",
+			L1Dir:        "environments",
+			L2Dir:        "segments",
+			FlowsDir:     "flows",
+			GroupsDir:    "groups",
+			TemplatesDir: "templates",
+			FileGlobs:    []string{"*.yaml", "*.yml"},
 		},
 	}
 }
@@ -22,6 +22,24 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Terminology.L2.Plural == "" {
 		t.Error("Expected L2 plural to be set")
 	}
+	if len(cfg.FsRepository.FileGlobs) == 0 {
+		t.Error("Expected file globs to be set")
+	}
+}
+
+func TestConfig_Merge_FileGlobs(t *testing.T) {
+	var cfg Config
+	cfg.FsRepository.FileGlobs = []string{"*.yaml"}
+	if merged := cfg.Merge(); len(merged.FsRepository.FileGlobs) != 1 {
+		t.Errorf("Expected configured globs to be kept, got %v", merged.FsRepository.FileGlobs)
+	}
+	if merged := (Config{}).Merge(); len(merged.FsRepository.FileGlobs) != len(DefaultConfig().FsRepository.FileGlobs) {
+		t.Errorf("Expected default globs, got %v", merged.FsRepository.FileGlobs)
+	}
+	cfg.FsRepository.FileGlobs = []string{}
+	if merged := cfg.Merge(); merged.FsRepository.FileGlobs == nil || len(merged.FsRepository.FileGlobs) != 0 {
+		t.Errorf("Expected explicit empty globs to be kept, got %v", merged.FsRepository.FileGlobs)
+	}
 }
 
 func TestTermDef_DirName(t *testing.T) {
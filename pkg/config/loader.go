@@ -11,7 +11,9 @@ import (
 	"github.com/kvql/bunsceal/pkg/domain"
 	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
 	"github.com/kvql/bunsceal/pkg/o11y"
+	"github.com/kvql/bunsceal/pkg/policy"
 	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
 	"github.com/kvql/bunsceal/pkg/visualise"
 	"gopkg.in/yaml.v3"
 )
@@ -37,6 +39,7 @@ func LoadConfig(configPath, configSchemaPath string) (configDomain.Config, error
 	externalSchemas = append(externalSchemas,
 		schemaValidation.ExternalSchema{JSON: domain.TermsConfigSchema, ID: "https://github.com/kvql/bunsceal/pkg/config/schemas/terms.json"},
 		schemaValidation.ExternalSchema{JSON: visualise.VisualiseConfigSchema, ID: "https://github.com/kvql/bunsceal/pkg/config/schemas/visualise.json"},
+		schemaValidation.ExternalSchema{JSON: policy.PoliciesConfigSchema, ID: "https://github.com/kvql/bunsceal/pkg/config/schemas/policies.json"},
 	)
 	schemaValidator, err := schemaValidation.NewSchemaValidator(configSchemaPath, configSchemaBaseURL, externalSchemas...)
 	if err != nil {
@@ -77,6 +80,19 @@ func LoadConfig(configPath, configSchemaPath string) (configDomain.Config, error
 	if merged.FsRepository.TaxonomyDir != "" && (!strings.HasPrefix(merged.FsRepository.TaxonomyDir, "/") || !strings.HasPrefix(merged.FsRepository.TaxonomyDir, "\\")) {
 		merged.FsRepository.TaxonomyDir = filepath.Join(configDir, merged.FsRepository.TaxonomyDir)
 	}
+	// Update single file and source paths if relative, stdin is kept as is
+	merged.Repository.Path = resolvePath(configDir, merged.Repository.Path)
+	for i, source := range merged.Repository.Sources {
+		merged.Repository.Sources[i].Path = resolvePath(configDir, source.Path)
+	}
 
 	return merged, nil
 }
+
+// resolvePath returns path relative to configDir, keeping empty, absolute and stdin paths as they are.
+func resolvePath(configDir, path string) string {
+	if path == "" || path == infrastructure.StdinPath || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(configDir, path)
+}
@@ -319,6 +319,258 @@ func TestLoadConfig_WithPlugins(t *testing.T) {
 			t.Errorf("Expected RationaleLength 5, got %d", cfg.Plugins.Classifications.RationaleLength)
 		}
 	})
+	t.Run("Loads network plugin config", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `plugins:
+  network:
+    require_l1_supernets: true
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		cfg, err := LoadConfig(configPath, testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Plugins.Network == nil || !cfg.Plugins.Network.RequireL1Supernets {
+			t.Fatal("Expected Plugins.Network with require_l1_supernets=true")
+		}
+	})
+	t.Run("Loads per-key inheritance strategies", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `plugins:
+  classifications:
+    definitions:
+      sensitivity:
+        name: "Sensitivity"
+        values:
+          high: "High"
+          low: "Low"
+        order: ["high", "low"]
+        inheritance: strictest
+  compliance:
+    definitions:
+      pci-dss:
+        name: "PCI DSS"
+        inheritance: required
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		cfg, err := LoadConfig(configPath, testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Plugins.Classifications.Definitions["sensitivity"].Inheritance != "strictest" {
+			t.Error("Expected sensitivity inheritance=strictest")
+		}
+		if cfg.Plugins.Compliance.Definitions["pci-dss"].Inheritance != "required" {
+			t.Error("Expected pci-dss inheritance=required")
+		}
+	})
+	t.Run("Requires max_age_days for review plugin", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `plugins:
+  review:
+    mode: strict
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		if _, err := LoadConfig(configPath, testSchemaPath); err == nil {
+			t.Fatal("Expected review config without max_age_days to fail validation")
+		}
+	})
+	t.Run("Rejects unknown inheritance strategy", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `plugins:
+  compliance:
+    definitions:
+      pci-dss:
+        name: "PCI DSS"
+        inheritance: newest
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		if _, err := LoadConfig(configPath, testSchemaPath); err == nil {
+			t.Fatal("Expected unknown inheritance strategy to fail validation")
+		}
+	})
+	t.Run("Rejects union inheritance", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `plugins:
+  classifications:
+    definitions:
+      sensitivity:
+        name: "Sensitivity"
+        values:
+          high: "High"
+          low: "Low"
+        order: ["high", "low"]
+        inheritance: union
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		if _, err := LoadConfig(configPath, testSchemaPath); err == nil {
+			t.Fatal("Expected union inheritance to fail validation")
+		}
+	})
+}
+
+func TestLoadConfig_Repository(t *testing.T) {
+	writeConfig := func(t *testing.T, configYAML string) string {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		return configPath
+	}
+
+	t.Run("Defaults to the fs repository", func(t *testing.T) {
+		cfg, err := LoadConfig(writeConfig(t, "schema_path: schemas\n"), testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Repository.Type != "fs" {
+			t.Errorf("Expected repository type fs, got %q", cfg.Repository.Type)
+		}
+	})
+
+	t.Run("Resolves file path relative to the config", func(t *testing.T) {
+		configPath := writeConfig(t, "repository:\n  type: file\n  path: taxonomy.yaml\n")
+		cfg, err := LoadConfig(configPath, testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if expected := filepath.Join(filepath.Dir(configPath), "taxonomy.yaml"); cfg.Repository.Path != expected {
+			t.Errorf("Expected path %s, got %s", expected, cfg.Repository.Path)
+		}
+	})
+
+	t.Run("Keeps stdin path", func(t *testing.T) {
+		cfg, err := LoadConfig(writeConfig(t, "repository:\n  type: file\n  path: \"-\"\n"), testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Repository.Path != "-" {
+			t.Errorf("Expected stdin path, got %s", cfg.Repository.Path)
+		}
+	})
+
+	t.Run("Keeps an explicit empty file glob list", func(t *testing.T) {
+		cfg, err := LoadConfig(writeConfig(t, "fs_repository:\n  l1_dir: l1\n  l2_dir: l2\n  flows_dir: flows\n  file_globs: []\n"), testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.FsRepository.FileGlobs == nil || len(cfg.FsRepository.FileGlobs) != 0 {
+			t.Errorf("Expected empty globs reading every file, got %v", cfg.FsRepository.FileGlobs)
+		}
+	})
+
+	t.Run("Requires a path for the file repository", func(t *testing.T) {
+		if _, err := LoadConfig(writeConfig(t, "repository:\n  type: file\n"), testSchemaPath); err == nil {
+			t.Error("Expected schema validation error without path")
+		}
+	})
+}
+
+func TestLoadConfig_WithCustomRules(t *testing.T) {
+	t.Run("Loads custom rules", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `rules:
+  custom:
+    - id: prod-pci-criticality
+      severity: warning
+      selector: parent == "production"
+      expression: int(label("classifications", "criticality")) <= 2
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		cfg, err := LoadConfig(configPath, testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(cfg.Rules.Custom) != 1 || cfg.Rules.Custom[0].Severity != "warning" {
+			t.Fatalf("Expected 1 warning custom rule, got %+v", cfg.Rules.Custom)
+		}
+	})
+
+	t.Run("Loads strictest segment rule", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `rules:
+  shared_service:
+    enabled: false
+  strictest_segment:
+    enabled: true
+    l1_ids: [platform]
+    keys: [sensitivity]
+    require_all_compliance: true
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		cfg, err := LoadConfig(configPath, testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		rule := cfg.Rules.StrictestSegment
+		if !rule.Enabled || len(rule.L1IDs) != 1 || rule.L1IDs[0] != "platform" || !rule.RequireAllCompliance {
+			t.Errorf("Unexpected strictest segment config: %+v", rule)
+		}
+	})
+
+	t.Run("Loads policy generation settings", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `policies:
+  l2_label_key: example.com/segment
+  namespace: apps
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		cfg, err := LoadConfig(configPath, testSchemaPath)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Policies.L2LabelKey != "example.com/segment" || cfg.Policies.Namespace != "apps" {
+			t.Errorf("Unexpected policies config: %+v", cfg.Policies)
+		}
+	})
+
+	t.Run("Rejects custom rule without expression", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "config.yaml")
+
+		configYAML := `rules:
+  custom:
+    - id: incomplete
+`
+		if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		if _, err := LoadConfig(configPath, testSchemaPath); err == nil {
+			t.Fatal("Expected custom rule without expression to fail validation")
+		}
+	})
 }
 
 func TestLoadConfig_WithVisuals(t *testing.T) {
@@ -2,3 +2,10 @@ package domain
 
 // ApiVersion specifies the API version for compatibility testing in clients.
 const ApiVersion = "v1beta1"
+
+const (
+	// SegmentL1LabelKey is the default label/tag key identifying a resource's L1 segment.
+	SegmentL1LabelKey = "bunsceal.segment/l1"
+	// SegmentL2LabelKey is the default label/tag key identifying a resource's L2 segment.
+	SegmentL2LabelKey = "bunsceal.segment/l2"
+)
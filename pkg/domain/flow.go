@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	FlowProtocolTCP  = "tcp"
+	FlowProtocolUDP  = "udp"
+	FlowProtocolICMP = "icmp"
+	FlowProtocolAny  = "any"
+
+	// FlowOneWay allows connections initiated by the source only.
+	FlowOneWay = "one-way"
+	// FlowBidirectional allows connections initiated by either side.
+	FlowBidirectional = "bidirectional"
+)
+
+// FlowsDocument is the file format for flow definitions, each file may hold several flows.
+type FlowsDocument struct {
+	Version string `yaml:"version,omitempty"`
+	Flows   []Flow `yaml:"flows"`
+}
+
+// Flow declares an allowed communication between two segments of the same level.
+// Ports is empty for all ports; entries are single ports ("443") or ranges ("8000-8100").
+type Flow struct {
+	ID          string   `yaml:"id" json:"id"`
+	Level       int      `yaml:"level" json:"level"`
+	Source      string   `yaml:"source" json:"source"`
+	Destination string   `yaml:"destination" json:"destination"`
+	Protocol    string   `yaml:"protocol" json:"protocol"`
+	Ports       []string `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Direction   string   `yaml:"direction" json:"direction"`
+	Rationale   string   `yaml:"rationale" json:"rationale"`
+	Exception   string   `yaml:"exception,omitempty" json:"exception,omitempty"`
+}
+
+// PortRange is an inclusive port range; From == To for a single port.
+type PortRange struct {
+	From int
+	To   int
+}
+
+// ParsePortRange parses "443" or "8000-8100".
+func ParsePortRange(value string) (PortRange, error) {
+	from, to, isRange := strings.Cut(value, "-")
+	start, err := strconv.Atoi(from)
+	if err != nil {
+		return PortRange{}, fmt.Errorf("invalid port %q", value)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(to); err != nil {
+			return PortRange{}, fmt.Errorf("invalid port range %q", value)
+		}
+	}
+	if start < 1 || end > 65535 || start > end {
+		return PortRange{}, fmt.Errorf("port range %q out of bounds (1-65535, low-high)", value)
+	}
+	return PortRange{From: start, To: end}, nil
+}
+
+// PortRanges parses all ports of the flow.
+func (f Flow) PortRanges() ([]PortRange, error) {
+	ranges := make([]PortRange, 0, len(f.Ports))
+	for _, port := range f.Ports {
+		pr, err := ParsePortRange(port)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, pr)
+	}
+	return ranges, nil
+}
+
+// Validate checks the flow's fields independently of the taxonomy.
+func (f Flow) Validate() error {
+	if f.Level < 1 {
+		return fmt.Errorf("flow %s: unsupported level %d", f.ID, f.Level)
+	}
+	if f.Source == f.Destination {
+		return fmt.Errorf("flow %s: source and destination are both %s", f.ID, f.Source)
+	}
+	switch f.Protocol {
+	case FlowProtocolTCP, FlowProtocolUDP:
+	case FlowProtocolICMP, FlowProtocolAny:
+		if len(f.Ports) > 0 {
+			return fmt.Errorf("flow %s: ports cannot be set for protocol %s", f.ID, f.Protocol)
+		}
+	default:
+		return fmt.Errorf("flow %s: unsupported protocol %s", f.ID, f.Protocol)
+	}
+	if f.Direction != FlowOneWay && f.Direction != FlowBidirectional {
+		return fmt.Errorf("flow %s: unsupported direction %s", f.ID, f.Direction)
+	}
+	if _, err := f.PortRanges(); err != nil {
+		return fmt.Errorf("flow %s: %w", f.ID, err)
+	}
+	return nil
+}
+
+// FlowSegments returns the segments of the flow's level, nil if the taxonomy has no such level.
+func (t Taxonomy) FlowSegments(f Flow) map[string]Seg {
+	return t.Segs(f.Level)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "testing"
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected PortRange
+		wantErr  bool
+	}{
+		{"443", PortRange{From: 443, To: 443}, false},
+		{"8000-8100", PortRange{From: 8000, To: 8100}, false},
+		{"0", PortRange{}, true},
+		{"70000", PortRange{}, true},
+		{"9000-8000", PortRange{}, true},
+		{"http", PortRange{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			pr, err := ParsePortRange(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if pr != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, pr)
+			}
+		})
+	}
+}
+
+func TestFlow_Validate(t *testing.T) {
+	valid := Flow{ID: "f", Level: 2, Source: "a", Destination: "b", Protocol: FlowProtocolTCP, Ports: []string{"443"}, Direction: FlowOneWay}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Expected valid flow, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Flow)
+	}{
+		{"Unsupported level", func(f *Flow) { f.Level = 0 }},
+		{"Same source and destination", func(f *Flow) { f.Destination = f.Source }},
+		{"Ports with icmp", func(f *Flow) { f.Protocol = FlowProtocolICMP }},
+		{"Unsupported direction", func(f *Flow) { f.Direction = "both" }},
+		{"Invalid port", func(f *Flow) { f.Ports = []string{"0"} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := valid
+			tt.mutate(&f)
+			if err := f.Validate(); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "sort"
+
+// GroupsDocument is the file format for group definitions, each file may hold several groups.
+type GroupsDocument struct {
+	Version string  `yaml:"version,omitempty"`
+	Groups  []Group `yaml:"groups"`
+}
+
+// Group is an optional L0 grouping of L1 segments, such as a region or business unit.
+// L1s join a group with their group field. Groups organise reporting and diagrams only,
+// they take no part in label inheritance.
+type Group struct {
+	ID          string `yaml:"id" json:"id"`
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// GroupMembers returns the sorted IDs of the L1 segments in a group.
+func (t Taxonomy) GroupMembers(groupID string) []string {
+	var ids []string
+	for id, seg := range t.SegL1s {
+		if seg.Group == groupID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SegGroups returns the groups of a segment at level: its own group for an L1,
+// and the groups of its parents for lower levels.
+func (t Taxonomy) SegGroups(level int, seg Seg) []string {
+	if level <= 1 {
+		if seg.Group == "" {
+			return nil
+		}
+		return []string{seg.Group}
+	}
+	seen := map[string]bool{}
+	var groups []string
+	parents := t.Segs(level - 1)
+	for _, parentID := range seg.L1Parents {
+		for _, group := range t.SegGroups(level-1, parents[parentID]) {
+			if !seen[group] {
+				seen[group] = true
+				groups = append(groups, group)
+			}
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
@@ -2,8 +2,9 @@ package domain
 
 // Identifiers represents the identifying attributes of a taxonomy entity.
 type Identifiers struct {
-	Name string
-	ID   string
+	Name    string
+	ID      string
+	Aliases []string // previous IDs that still resolve to the entity
 }
 
 // UnqSegKeys defines the interface for entities with unique identifiers.
@@ -0,0 +1,6 @@
+package domain
+
+// Metadata holds structured values on a segment or override, keyed by namespace like labels
+// (e.g. "bunsceal.plugin.review" or "team"). Values under a plugin namespace are validated
+// against that plugin's metadata schema, other namespaces are free-form.
+type Metadata map[string]interface{}
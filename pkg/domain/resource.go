@@ -0,0 +1,8 @@
+package domain
+
+// Resource is a deployed resource from an inventory, identified by ID with its tags.
+type Resource struct {
+	ID   string            `json:"id"`
+	Type string            `json:"type,omitempty"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
@@ -139,6 +139,46 @@ func (sv *SchemaValidator) ValidateData(data []byte, schemaFile string) error {
 	return nil
 }
 
+// ValueSchema validates already decoded values against a single JSON schema,
+// for schemas supplied at runtime rather than from the schema directory.
+type ValueSchema struct {
+	schema *jsonschema.Schema
+}
+
+// NewValueSchema compiles schemaJSON, registered under id for error messages and $ref resolution.
+func NewValueSchema(id string, schemaJSON string) (*ValueSchema, error) {
+	var schemaDoc interface{}
+	if err := json.Unmarshal([]byte(schemaJSON), &schemaDoc); err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", id, err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(id, schemaDoc); err != nil {
+		return nil, fmt.Errorf("failed to add schema %s: %w", id, err)
+	}
+	schema, err := compiler.Compile(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %s: %w", id, err)
+	}
+	return &ValueSchema{schema: schema}, nil
+}
+
+// Validate validates a value decoded from YAML or JSON against the schema.
+// The value is normalised through JSON first, as decoders may produce named map types.
+func (vs *ValueSchema) Validate(value interface{}) error {
+	data, err := json.Marshal(convertYAMLToJSON(value))
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+	var parsedData interface{}
+	if err := json.Unmarshal(data, &parsedData); err != nil {
+		return fmt.Errorf("failed to parse value: %w", err)
+	}
+	if err := vs.schema.Validate(parsedData); err != nil {
+		return formatValidationError(err)
+	}
+	return nil
+}
+
 // convertYAMLToJSON converts YAML-parsed data to JSON-compatible types
 // yaml.v3 can produce map[interface{}]interface{} which JSON doesn't support
 func convertYAMLToJSON(i interface{}) interface{} {
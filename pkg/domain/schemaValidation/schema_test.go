@@ -144,6 +144,53 @@ func TestValidateData_Seg(t *testing.T) {
 		})
 		assertValidationFails(t, seg, "seg-level.json")
 	})
+
+	t.Run("Wildcard and label selector parents pass validation", func(t *testing.T) {
+		seg := testhelpers.NewSeg("test", "Test", map[string]domain.L1Overrides{
+			domain.DefaultOverride: testhelpers.NewL1Override("A", "1", nil),
+		})
+		seg.L1Parents = []string{domain.WildcardParent, "bunsceal.plugin.classifications/sensitivity:A"}
+		assertValidationPasses(t, seg, "seg-level.json")
+	})
+
+	t.Run("Invalid parent entry fails validation", func(t *testing.T) {
+		seg := testhelpers.NewSeg("test", "Test", nil)
+		seg.L1Parents = []string{"Not An ID"}
+		assertValidationFails(t, seg, "seg-level.json")
+	})
+
+	t.Run("Nested metadata passes validation", func(t *testing.T) {
+		seg := testhelpers.NewSeg("test", "Test", map[string]domain.L1Overrides{
+			"prod": {Metadata: domain.Metadata{"team": map[string]interface{}{"oncall": []string{"alice"}}}},
+		})
+		seg.Metadata = domain.Metadata{"bunsceal.plugin.review": map[string]interface{}{"contacts": []interface{}{}}}
+		assertValidationPasses(t, seg, "seg-level.json")
+	})
+
+	t.Run("Invalid metadata namespace fails validation", func(t *testing.T) {
+		seg := testhelpers.NewSeg("test", "Test", map[string]domain.L1Overrides{
+			"prod": testhelpers.NewL1Override("A", "1", nil),
+		})
+		seg.Metadata = domain.Metadata{"bad namespace!": "x"}
+		assertValidationFails(t, seg, "seg-level.json")
+	})
+}
+
+func TestNewValueSchema(t *testing.T) {
+	schema, err := NewValueSchema("https://example.com/value.json", `{"type": "object", "required": ["name"]}`)
+	if err != nil {
+		t.Fatalf("NewValueSchema: %v", err)
+	}
+
+	if err := schema.Validate(map[string]interface{}{"name": "x", "count": 1}); err != nil {
+		t.Errorf("Expected valid value to pass, got %v", err)
+	}
+	if err := schema.Validate(map[string]interface{}{"count": 1}); err == nil {
+		t.Error("Expected value without name to fail")
+	}
+	if _, err := NewValueSchema("https://example.com/bad.json", `{`); err == nil {
+		t.Error("Expected invalid schema JSON to fail")
+	}
 }
 
 func TestValidateData_JSON(t *testing.T) {
@@ -3,25 +3,44 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+const (
+	// WildcardParent in l1_parents or parents selects every segment of the level above.
+	WildcardParent = "*"
+	// DefaultOverride is the override key applied to every parent without its own override.
+	DefaultOverride = "default"
+)
+
 // Seg represents a Level 1 segment (Environment).
+// L1Parents and L1Overrides reference segments in the level directly above; segments below L2
+// declare them as parents and overrides, moved there by PostLoad.
 type Seg struct {
+	Version         string                       `yaml:"version,omitempty" json:"-"` // schema version of the file
 	Name            string                       `yaml:"name" json:"name"`
 	ID              string                       `yaml:"id" json:"id"`
+	Aliases         []string                     `yaml:"aliases,omitempty" json:"aliases,omitempty"`
 	Description     string                       `yaml:"description" json:"description"`
 	Level           string                       `yaml:"level,omitempty" json:"level,omitempty"`
+	Group           string                       `yaml:"group,omitempty" json:"group,omitempty"` // L0 group, L1 only
+	Extends         []string                     `yaml:"extends,omitempty" json:"-"`             // templates, below L1 only
 	L1Parents       []string                     `yaml:"l1_parents,omitempty" json:"l1_parents,omitempty"`
 	L1Overrides     map[string]L1Overrides       `yaml:"l1_overrides,omitempty" json:"l1_overrides,omitempty"`
+	Parents         []string                     `yaml:"parents,omitempty" json:"-"`
+	Overrides       map[string]L1Overrides       `yaml:"overrides,omitempty" json:"-"`
 	Prominence      int                          `yaml:"prominence,omitempty" json:"prominence,omitempty"`
 	Labels          []string                     `yaml:"labels" json:"labels,omitempty"`
-	ParsedLabels    map[string]string            `yaml:"-" json:"-"`
+	Metadata        Metadata                     `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	ParsedLabels    map[string]string            `yaml:"-" json:"effective_labels,omitempty"` // labels after inheritance, published in exports
 	LabelNamespaces map[string]map[string]string `yaml:"-" json:"-"`
 }
 
 type L1Overrides struct {
 	Labels          []string                     `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Metadata        Metadata                     `yaml:"metadata,omitempty" json:"metadata,omitempty"`
 	ParsedLabels    map[string]string            `yaml:"-" json:"-"`
 	LabelNamespaces map[string]map[string]string `yaml:"-" json:"-"`
 }
@@ -33,7 +52,9 @@ func (o *L1Overrides) ParseLabels() error {
 // ###################
 // Segment Methods
 
-func (s Seg) GetIdentities() Identifiers { return Identifiers{Name: s.Name, ID: s.ID} }
+func (s Seg) GetIdentities() Identifiers {
+	return Identifiers{Name: s.Name, ID: s.ID, Aliases: s.Aliases}
+}
 
 func (s Seg) GetKeyString(key string) (string, error) {
 	var val string
@@ -64,6 +85,58 @@ func (s Seg) GetNamespacedValue(parent string, ns string, key string) (string, e
 	return "", fmt.Errorf("no value found for ns(%s), key(%s) on seg(%s)", ns, key, s.ID)
 }
 
+// DeclaresLabel reports whether key is one of the segment's own labels, rather than inherited.
+func (s Seg) DeclaresLabel(key string) bool {
+	for _, label := range s.Labels {
+		if labelKey(label) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveLabels returns the segment's parsed labels with the override for parent applied.
+// Pass an empty parent for L1 segments.
+func (s Seg) EffectiveLabels(parent string) map[string]string {
+	labels := make(map[string]string, len(s.ParsedLabels))
+	for k, v := range s.ParsedLabels {
+		labels[k] = v
+	}
+	if override, ok := s.L1Overrides[parent]; ok && parent != "" {
+		for k, v := range override.ParsedLabels {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// SetEffectiveLabels replaces the segment's parsed labels with labels already resolved by inheritance,
+// such as the effective labels of an export. Override labels are left as parsed.
+func (s *Seg) SetEffectiveLabels(labels map[string]string) error {
+	list := make([]string, 0, len(labels))
+	for k, v := range labels {
+		list = append(list, k+":"+v)
+	}
+	sort.Strings(list)
+	return parseLabelsIntoMaps(list, &s.ParsedLabels, &s.LabelNamespaces)
+}
+
+// EffectiveMetadata returns the segment's metadata with the override for parent applied.
+// Overrides replace whole namespaces rather than merging nested values.
+// Pass an empty parent for L1 segments.
+func (s Seg) EffectiveMetadata(parent string) Metadata {
+	metadata := make(Metadata, len(s.Metadata))
+	for ns, value := range s.Metadata {
+		metadata[ns] = value
+	}
+	if override, ok := s.L1Overrides[parent]; ok && parent != "" {
+		for ns, value := range override.Metadata {
+			metadata[ns] = value
+		}
+	}
+	return metadata
+}
+
 func (s *Seg) PostLoad(level string) error {
 	// Set level if not already set
 	if s.Level == "" {
@@ -72,6 +145,19 @@ func (s *Seg) PostLoad(level string) error {
 		return fmt.Errorf("level (%s) passed as argument, doesn't match level field (%s)", level, s.Level)
 	}
 
+	if s.ID == DefaultOverride {
+		return fmt.Errorf("segment ID %s is reserved for the default override", s.ID)
+	}
+	if level == "1" && len(s.Extends) > 0 {
+		return fmt.Errorf("L1 segments cannot extend templates, segment %s extends %v", s.ID, s.Extends)
+	}
+	if level != "1" && s.Group != "" {
+		return fmt.Errorf("only L1 segments belong to a group, L%s segment sets group %s", level, s.Group)
+	}
+	if (level == "1" || level == "2") && (len(s.Parents) > 0 || len(s.Overrides) > 0) {
+		return fmt.Errorf("parents and overrides are only used below L2, use l1_parents and l1_overrides")
+	}
+
 	// Validate level-specific required fields
 	switch level {
 	case "1":
@@ -86,7 +172,21 @@ func (s *Seg) PostLoad(level string) error {
 			return err
 		}
 	default:
-		return fmt.Errorf("unsupported segment level: %s", level)
+		if n, err := strconv.Atoi(level); err != nil || n < 3 {
+			return fmt.Errorf("unsupported segment level: %s", level)
+		}
+		// Segments below L2 reference parents in the level above with parents and overrides
+		if len(s.L1Parents) > 0 || len(s.L1Overrides) > 0 {
+			return fmt.Errorf("L%s segment uses l1_parents or l1_overrides, use parents and overrides", level)
+		}
+		if len(s.Parents) == 0 {
+			return fmt.Errorf("L%s segment missing required field: Parents", level)
+		}
+		s.L1Parents, s.L1Overrides = s.Parents, s.Overrides
+		s.Parents, s.Overrides = nil, nil
+		if err := s.ValidateL1Consistency(); err != nil {
+			return err
+		}
 	}
 
 	// Apply defaults
@@ -104,9 +204,10 @@ func (s *Seg) SetDefaults() {
 
 // ValidateL1Consistency ensures that all L1Overrides keys are present in L1Parents.
 // This prevents invalid YAML where overrides reference parents not in the parent list.
+// The default override is always allowed, and keys can't be checked until parent selectors are expanded.
 // Returns error if any override key is not in L1Parents.
 func (s *Seg) ValidateL1Consistency() error {
-	if len(s.L1Overrides) == 0 {
+	if len(s.L1Overrides) == 0 || s.HasParentSelectors() {
 		return nil
 	}
 
@@ -118,13 +219,124 @@ func (s *Seg) ValidateL1Consistency() error {
 
 	// Check each override key exists in parent list
 	for overrideKey := range s.L1Overrides {
-		if !parentSet[overrideKey] {
+		if overrideKey != DefaultOverride && !parentSet[overrideKey] {
 			return fmt.Errorf("l1_overrides contains key '%s' which is not in l1_parents", overrideKey)
 		}
 	}
 	return nil
 }
 
+// IsParentSelector reports whether a parent entry selects parents rather than naming one:
+// the wildcard, or a label ("key:value") matched against the parents' own labels.
+func IsParentSelector(entry string) bool {
+	return entry == WildcardParent || strings.Contains(entry, ":")
+}
+
+// HasParentSelectors reports whether any of the segment's parent entries is a selector.
+func (s Seg) HasParentSelectors() bool {
+	for _, entry := range s.L1Parents {
+		if IsParentSelector(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandParents replaces parent selectors with the sorted IDs of the matching segments in parents,
+// keeping named parents first, then gives every parent without its own override a copy of the
+// default override. Parents should have inherited their labels already, so label selectors match
+// inherited labels too. A selector matching no parent is an error. Labels are re-parsed.
+func (s *Seg) ExpandParents(parents map[string]Seg) error {
+	_, hasDefault := s.L1Overrides[DefaultOverride]
+	if !s.HasParentSelectors() && !hasDefault {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var expanded []string
+	for _, entry := range s.L1Parents {
+		if !IsParentSelector(entry) && !seen[entry] {
+			seen[entry] = true
+			expanded = append(expanded, entry)
+		}
+	}
+	for _, entry := range s.L1Parents {
+		if !IsParentSelector(entry) {
+			continue
+		}
+		var matched []string
+		for id, parent := range parents {
+			if parentMatches(parent, entry) {
+				matched = append(matched, id)
+			}
+		}
+		if len(matched) == 0 {
+			if key := shortSelectorKey(parents, entry); key != "" {
+				return fmt.Errorf("segment %s: parent selector %s matches no segments, label selectors need the full key, e.g. %s", s.ID, entry, key)
+			}
+			return fmt.Errorf("segment %s: parent selector %s matches no segments", s.ID, entry)
+		}
+		sort.Strings(matched)
+		for _, id := range matched {
+			if !seen[id] {
+				seen[id] = true
+				expanded = append(expanded, id)
+			}
+		}
+	}
+	s.L1Parents = expanded
+
+	if hasDefault {
+		defaultOverride := s.L1Overrides[DefaultOverride]
+		delete(s.L1Overrides, DefaultOverride)
+		for _, parentID := range s.L1Parents {
+			if _, ok := s.L1Overrides[parentID]; !ok {
+				s.L1Overrides[parentID] = L1Overrides{
+					Labels:   append([]string(nil), defaultOverride.Labels...),
+					Metadata: defaultOverride.Metadata,
+				}
+			}
+		}
+	}
+	if err := s.ValidateL1Consistency(); err != nil {
+		return fmt.Errorf("segment %s: %w", s.ID, err)
+	}
+	return s.ParseLabels()
+}
+
+// parentMatches reports whether a parent is selected by a selector, matching its labels including
+// those it inherited.
+func parentMatches(parent Seg, selector string) bool {
+	if selector == WildcardParent {
+		return true
+	}
+	key, value, _ := strings.Cut(selector, ":")
+	labelValue, ok := parent.ParsedLabels[key]
+	return ok && labelValue == value
+}
+
+// shortSelectorKey returns the first namespaced key, in sorted order, that a selector's key without a
+// namespace could have meant, or "" when the key has a namespace or matches none.
+func shortSelectorKey(parents map[string]Seg, selector string) string {
+	key, _, _ := strings.Cut(selector, ":")
+	if strings.Contains(key, "/") {
+		return ""
+	}
+	var candidates []string
+	for _, parent := range parents {
+		for parentKey := range parent.ParsedLabels {
+			if strings.HasSuffix(parentKey, "/"+key) {
+				candidates = append(candidates, parentKey)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+	return candidates[0]
+}
+
 // parseLabelsIntoMaps parses label strings into both ParsedLabels and LabelNamespaces maps.
 // Format: "namespace/key:value" -> ParsedLabels["namespace/key"] = "value"
 //
@@ -190,6 +190,22 @@ func TestSeg_ValidateL1Consistency(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "Valid - default override",
+			Seg: Seg{
+				L1Parents:   []string{"prod"},
+				L1Overrides: map[string]L1Overrides{DefaultOverride: {}},
+			},
+			expectError: false,
+		},
+		{
+			name: "Valid - override keys are checked after selectors expand",
+			Seg: Seg{
+				L1Parents:   []string{WildcardParent},
+				L1Overrides: map[string]L1Overrides{"prod": {}},
+			},
+			expectError: false,
+		},
 		{
 			name: "Invalid - override key not in parents",
 			Seg: Seg{
@@ -284,6 +300,14 @@ func TestPostLoad_L1Segment(t *testing.T) {
 	})
 }
 
+func TestPostLoad_L1Extends(t *testing.T) {
+	seg := Seg{ID: "prod", Name: "Production", Extends: []string{"defaults"}}
+
+	if err := seg.PostLoad("1"); err == nil || !strings.Contains(err.Error(), "cannot extend templates") {
+		t.Errorf("Expected error for L1 extends, got: %v", err)
+	}
+}
+
 func TestPostLoad_L2Segment(t *testing.T) {
 	t.Run("Valid L2 segment passes validation", func(t *testing.T) {
 		seg := Seg{
@@ -305,6 +329,20 @@ func TestPostLoad_L2Segment(t *testing.T) {
 		}
 	})
 
+	t.Run("Group fails validation", func(t *testing.T) {
+		seg := Seg{
+			ID:        "app",
+			Name:      "Application",
+			L1Parents: []string{"prod"},
+			Group:     "eu",
+		}
+
+		err := seg.PostLoad("2")
+		if err == nil || !strings.Contains(err.Error(), "group") {
+			t.Errorf("Expected error about group, got: %v", err)
+		}
+	})
+
 	t.Run("Missing L1Parents fails validation", func(t *testing.T) {
 		seg := Seg{
 			ID:   "app",
@@ -416,14 +454,51 @@ func TestPostLoad_LevelAssignment(t *testing.T) {
 			Name: "Test",
 		}
 
-		err := seg.PostLoad("99")
+		err := seg.PostLoad("0")
 		if err == nil {
-			t.Error("Expected error for unsupported level")
+			t.Fatal("Expected error for unsupported level")
 		}
 		if !strings.Contains(err.Error(), "unsupported segment level") {
 			t.Errorf("Expected error about unsupported level, got: %v", err)
 		}
 	})
+
+	t.Run("L3 segment moves parents and overrides", func(t *testing.T) {
+		seg := Seg{
+			ID:        "web",
+			Name:      "Web",
+			Parents:   []string{"app"},
+			Overrides: map[string]L1Overrides{"app": {Labels: []string{"ns/key:value"}}},
+		}
+
+		if err := seg.PostLoad("3"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if seg.Level != "3" || len(seg.L1Parents) != 1 || seg.L1Parents[0] != "app" || seg.Parents != nil {
+			t.Errorf("Expected parents moved to L1Parents, got %+v", seg)
+		}
+		if seg.L1Overrides["app"].ParsedLabels["ns/key"] != "value" {
+			t.Errorf("Expected override labels parsed, got %v", seg.L1Overrides)
+		}
+	})
+
+	t.Run("L3 segment requires parents", func(t *testing.T) {
+		seg := Seg{ID: "web", Name: "Web", L1Parents: []string{"app"}}
+		if err := seg.PostLoad("3"); err == nil || !strings.Contains(err.Error(), "use parents and overrides") {
+			t.Errorf("Expected l1_parents to be rejected below L2, got %v", err)
+		}
+		seg = Seg{ID: "web", Name: "Web"}
+		if err := seg.PostLoad("3"); err == nil || !strings.Contains(err.Error(), "missing required field: Parents") {
+			t.Errorf("Expected missing parents error, got %v", err)
+		}
+	})
+
+	t.Run("L2 segment rejects parents", func(t *testing.T) {
+		seg := Seg{ID: "app", Name: "App", L1Parents: []string{"prod"}, Parents: []string{"prod"}}
+		if err := seg.PostLoad("2"); err == nil {
+			t.Error("Expected parents to be rejected at L2")
+		}
+	})
 }
 
 func TestPostLoad_SetDefaults(t *testing.T) {
@@ -610,3 +685,113 @@ func TestParseLabels_Overrides(t *testing.T) {
 		}
 	})
 }
+
+func TestSeg_EffectiveLabels(t *testing.T) {
+	seg := Seg{ID: "app", Labels: []string{"ns/a:1", "ns/b:1"}, L1Overrides: map[string]L1Overrides{
+		"prod": {Labels: []string{"ns/b:2"}},
+	}}
+	if err := seg.ParseLabels(); err != nil {
+		t.Fatalf("ParseLabels: %v", err)
+	}
+
+	if labels := seg.EffectiveLabels("prod"); labels["ns/a"] != "1" || labels["ns/b"] != "2" {
+		t.Errorf("Expected override to win for prod, got %v", labels)
+	}
+	if labels := seg.EffectiveLabels("stg"); labels["ns/b"] != "1" {
+		t.Errorf("Expected base labels for parent without override, got %v", labels)
+	}
+	if seg.ParsedLabels["ns/b"] != "1" {
+		t.Error("Expected ParsedLabels to be unchanged")
+	}
+}
+
+func TestSeg_EffectiveMetadata(t *testing.T) {
+	seg := Seg{ID: "app", Metadata: Metadata{"team": "payments", "links": []interface{}{"a"}}, L1Overrides: map[string]L1Overrides{
+		"prod": {Metadata: Metadata{"team": "payments-oncall"}},
+	}}
+
+	if metadata := seg.EffectiveMetadata("prod"); metadata["team"] != "payments-oncall" || metadata["links"] == nil {
+		t.Errorf("Expected override namespace to win for prod, got %v", metadata)
+	}
+	if metadata := seg.EffectiveMetadata("stg"); metadata["team"] != "payments" {
+		t.Errorf("Expected base metadata for parent without override, got %v", metadata)
+	}
+}
+
+func TestSeg_ExpandParents(t *testing.T) {
+	parents := map[string]Seg{
+		"prod":    {ID: "prod", ParsedLabels: map[string]string{"ns/sensitivity": "A"}},
+		"shared":  {ID: "shared", ParsedLabels: map[string]string{"ns/sensitivity": "A"}},
+		"staging": {ID: "staging", ParsedLabels: map[string]string{"ns/sensitivity": "D"}},
+	}
+
+	t.Run("Wildcard selects every parent and default fills missing overrides", func(t *testing.T) {
+		seg := Seg{ID: "mon", L1Parents: []string{WildcardParent}, L1Overrides: map[string]L1Overrides{
+			DefaultOverride: {Labels: []string{"ns/owner:platform"}},
+			"prod":          {Labels: []string{"ns/owner:sre"}},
+		}}
+
+		if err := seg.ExpandParents(parents); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(seg.L1Parents, ",") != "prod,shared,staging" {
+			t.Errorf("Expected all parents, got %v", seg.L1Parents)
+		}
+		if _, ok := seg.L1Overrides[DefaultOverride]; ok {
+			t.Error("Expected default override to be removed")
+		}
+		if seg.L1Overrides["prod"].ParsedLabels["ns/owner"] != "sre" || seg.L1Overrides["staging"].ParsedLabels["ns/owner"] != "platform" {
+			t.Errorf("Expected own prod override and default elsewhere, got %+v", seg.L1Overrides)
+		}
+	})
+
+	t.Run("Label selector adds matching parents after named ones", func(t *testing.T) {
+		seg := Seg{ID: "vault", L1Parents: []string{"staging", "ns/sensitivity:A"}}
+
+		if err := seg.ExpandParents(parents); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(seg.L1Parents, ",") != "staging,prod,shared" {
+			t.Errorf("Expected staging then the sensitivity A parents, got %v", seg.L1Parents)
+		}
+	})
+
+	t.Run("Selector matching nothing fails", func(t *testing.T) {
+		seg := Seg{ID: "vault", L1Parents: []string{"ns/sensitivity:B"}}
+
+		if err := seg.ExpandParents(parents); err == nil || !strings.Contains(err.Error(), "matches no segments") {
+			t.Errorf("Expected no match error, got %v", err)
+		}
+	})
+
+	t.Run("Selector without the label namespace fails with the full key", func(t *testing.T) {
+		seg := Seg{ID: "vault", L1Parents: []string{"sensitivity:A"}}
+
+		if err := seg.ExpandParents(parents); err == nil || !strings.Contains(err.Error(), "e.g. ns/sensitivity") {
+			t.Errorf("Expected full key hint, got %v", err)
+		}
+	})
+
+	t.Run("Override for an unselected parent fails", func(t *testing.T) {
+		seg := Seg{ID: "vault", L1Parents: []string{"ns/sensitivity:A"}, L1Overrides: map[string]L1Overrides{"staging": {}}}
+
+		if err := seg.ExpandParents(parents); err == nil {
+			t.Error("Expected override consistency error")
+		}
+	})
+}
+
+func TestSeg_DeclaresLabel(t *testing.T) {
+	seg := Seg{ID: "app", Labels: []string{"ns/sensitivity:A"}}
+	if err := seg.ParseLabels(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	seg.ParsedLabels["ns/criticality"] = "1" // inherited
+
+	if !seg.DeclaresLabel("ns/sensitivity") {
+		t.Error("Expected declared label to be reported")
+	}
+	if seg.DeclaresLabel("ns/criticality") {
+		t.Error("Expected inherited label not to be reported as declared")
+	}
+}
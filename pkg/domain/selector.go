@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Selector operators, following Kubernetes label selectors.
+const (
+	SelectorEquals       = "="
+	SelectorNotEquals    = "!="
+	SelectorIn           = "in"
+	SelectorNotIn        = "notin"
+	SelectorExists       = "exists"
+	SelectorDoesNotExist = "!"
+)
+
+var (
+	selectorKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9./_-]*$`)
+	selectorSetPattern = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// LabelSelector is a parsed Kubernetes-style label selector: comma separated requirements that must
+// all hold. Requirements are `key=value` (or `==`), `key!=value`, `key in (a,b)`, `key notin (a,b)`,
+// `key` (label set) and `!key` (label not set). Keys may be namespaced, e.g.
+// `bunsceal.plugin.compliance/pci-dss=in-scope`. As in Kubernetes, `!=` and `notin` also match
+// segments without the label, and values can't contain commas or parentheses.
+// An empty selector matches everything.
+type LabelSelector struct {
+	Requirements []SelectorRequirement
+}
+
+// SelectorRequirement is a single requirement of a LabelSelector.
+type SelectorRequirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// ParseLabelSelector parses a label selector.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var result LabelSelector
+	if strings.TrimSpace(selector) == "" {
+		return result, nil
+	}
+	parts, err := splitSelector(selector)
+	if err != nil {
+		return LabelSelector{}, err
+	}
+	for _, part := range parts {
+		requirement, err := parseRequirement(strings.TrimSpace(part))
+		if err != nil {
+			return LabelSelector{}, fmt.Errorf("invalid selector requirement %q: %w", strings.TrimSpace(part), err)
+		}
+		result.Requirements = append(result.Requirements, requirement)
+	}
+	return result, nil
+}
+
+// splitSelector splits a selector into requirements at commas outside parentheses.
+func splitSelector(selector string) ([]string, error) {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("invalid selector %q: nested parentheses", selector)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("invalid selector %q: unbalanced parentheses", selector)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid selector %q: unbalanced parentheses", selector)
+	}
+	return append(parts, selector[start:]), nil
+}
+
+func parseRequirement(part string) (SelectorRequirement, error) {
+	if part == "" {
+		return SelectorRequirement{}, fmt.Errorf("empty requirement")
+	}
+	if strings.HasPrefix(part, "!") && !strings.ContainsAny(part, "=()") {
+		return newRequirement(strings.TrimSpace(part[1:]), SelectorDoesNotExist, nil)
+	}
+	if match := selectorSetPattern.FindStringSubmatch(part); match != nil {
+		var values []string
+		for _, value := range strings.Split(match[3], ",") {
+			if value = strings.TrimSpace(value); value == "" {
+				return SelectorRequirement{}, fmt.Errorf("empty value in set")
+			}
+			values = append(values, value)
+		}
+		return newRequirement(match[1], match[2], values)
+	}
+	if i := strings.IndexAny(part, "!="); i >= 0 {
+		key, rest, operator := part[:i], part[i:], SelectorEquals
+		switch {
+		case strings.HasPrefix(rest, "!="):
+			operator, rest = SelectorNotEquals, rest[2:]
+		case strings.HasPrefix(rest, "=="):
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "="):
+			rest = rest[1:]
+		default:
+			return SelectorRequirement{}, fmt.Errorf("unknown operator")
+		}
+		value := strings.TrimSpace(rest)
+		if value == "" {
+			return SelectorRequirement{}, fmt.Errorf("missing value")
+		}
+		if strings.ContainsAny(value, "()") {
+			return SelectorRequirement{}, fmt.Errorf("value %q contains parentheses", value)
+		}
+		return newRequirement(strings.TrimSpace(key), operator, []string{value})
+	}
+	return newRequirement(part, SelectorExists, nil)
+}
+
+func newRequirement(key, operator string, values []string) (SelectorRequirement, error) {
+	if !selectorKeyPattern.MatchString(key) {
+		return SelectorRequirement{}, fmt.Errorf("invalid key %q", key)
+	}
+	return SelectorRequirement{Key: key, Operator: operator, Values: values}, nil
+}
+
+// Matches reports whether labels, such as Seg.ParsedLabels or Seg.EffectiveLabels, satisfy every requirement.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range s.Requirements {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether labels satisfy the requirement.
+func (r SelectorRequirement) Matches(labels map[string]string) bool {
+	value, has := labels[r.Key]
+	switch r.Operator {
+	case SelectorExists:
+		return has
+	case SelectorDoesNotExist:
+		return !has
+	case SelectorEquals, SelectorIn:
+		return has && containsValue(r.Values, value)
+	case SelectorNotEquals, SelectorNotIn:
+		return !has || !containsValue(r.Values, value)
+	}
+	return false
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the selector in canonical form.
+func (s LabelSelector) String() string {
+	parts := make([]string, 0, len(s.Requirements))
+	for _, r := range s.Requirements {
+		switch r.Operator {
+		case SelectorExists:
+			parts = append(parts, r.Key)
+		case SelectorDoesNotExist:
+			parts = append(parts, "!"+r.Key)
+		case SelectorIn, SelectorNotIn:
+			parts = append(parts, fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ",")))
+		default:
+			parts = append(parts, r.Key+r.Operator+r.Values[0])
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// SelectorMatch is a segment whose labels a selector matched. Segments below L1 match per parent,
+// on their effective labels for that parent.
+type SelectorMatch struct {
+	Level  int               `json:"level" yaml:"level"`
+	ID     string            `json:"id" yaml:"id"`
+	Parent string            `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Name   string            `json:"name" yaml:"name"`
+	Labels map[string]string `json:"labels" yaml:"labels"`
+}
+
+// Select returns the segments of every level matching the selector, sorted by level, ID and parent.
+// Labels are the effective labels, so inherited labels match once inheritance has been applied.
+func (t Taxonomy) Select(selector LabelSelector) []SelectorMatch {
+	var matches []SelectorMatch
+	for level := 1; level <= t.Depth(); level++ {
+		for id, seg := range t.Segs(level) {
+			parents := seg.L1Parents
+			if level == 1 {
+				parents = []string{""}
+			}
+			for _, parent := range parents {
+				labels := seg.EffectiveLabels(parent)
+				if selector.Matches(labels) {
+					matches = append(matches, SelectorMatch{Level: level, ID: id, Parent: parent, Name: seg.Name, Labels: labels})
+				}
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Parent < b.Parent
+	})
+	return matches
+}
@@ -0,0 +1,105 @@
+package domain
+
+import "testing"
+
+func TestParseLabelSelector_Matches(t *testing.T) {
+	labels := map[string]string{
+		"bunsceal.plugin.compliance/pci-dss":          "in-scope",
+		"bunsceal.plugin.classifications/sensitivity": "A",
+		"team": "payments",
+	}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"team=payments", true},
+		{"team==payments", true},
+		{"team=platform", false},
+		{"team!=platform", true},
+		{"owner!=platform", true},
+		{"bunsceal.plugin.compliance/pci-dss=in-scope", true},
+		{"bunsceal.plugin.classifications/sensitivity in (A, B)", true},
+		{"bunsceal.plugin.classifications/sensitivity in (C,D)", false},
+		{"bunsceal.plugin.classifications/sensitivity notin (C,D)", true},
+		{"owner notin (a)", true},
+		{"team", true},
+		{"owner", false},
+		{"!owner", true},
+		{"!team", false},
+		{"team=payments, bunsceal.plugin.classifications/sensitivity in (A,B), !owner", true},
+		{"team=payments,owner", false},
+	}
+	for _, tt := range tests {
+		selector, err := ParseLabelSelector(tt.selector)
+		if err != nil {
+			t.Errorf("ParseLabelSelector(%q) failed: %v", tt.selector, err)
+			continue
+		}
+		if got := selector.Matches(labels); got != tt.want {
+			t.Errorf("%q matches = %v, expected %v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestParseLabelSelector_Invalid(t *testing.T) {
+	for _, selector := range []string{
+		"team=",
+		"team in (a,",
+		"team in (a,,b)",
+		"team in a)",
+		"=payments",
+		"team=pay,,owner",
+		"bad key=x",
+		"!",
+	} {
+		if _, err := ParseLabelSelector(selector); err == nil {
+			t.Errorf("Expected %q to fail parsing", selector)
+		}
+	}
+}
+
+func TestLabelSelector_String(t *testing.T) {
+	selector, err := ParseLabelSelector("a==1, b!=2,c in (x, y),!d,e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := selector.String(), "a=1,b!=2,c in (x,y),!d,e"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestTaxonomy_Select(t *testing.T) {
+	txy := Taxonomy{
+		SegL1s: map[string]Seg{
+			"prod":    {ID: "prod", Name: "Production", ParsedLabels: map[string]string{"env": "prod"}},
+			"staging": {ID: "staging", Name: "Staging", ParsedLabels: map[string]string{"env": "staging"}},
+		},
+		SegsL2s: map[string]Seg{
+			"app": {
+				ID:        "app",
+				Name:      "App",
+				L1Parents: []string{"staging", "prod"},
+				L1Overrides: map[string]L1Overrides{
+					"prod":    {ParsedLabels: map[string]string{"env": "prod"}},
+					"staging": {ParsedLabels: map[string]string{"env": "staging"}},
+				},
+			},
+		},
+	}
+	selector, err := ParseLabelSelector("env=prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches := txy.Select(selector)
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %+v", matches)
+	}
+	if matches[0].Level != 1 || matches[0].ID != "prod" || matches[0].Parent != "" {
+		t.Errorf("Expected L1 prod first, got %+v", matches[0])
+	}
+	if matches[1].Level != 2 || matches[1].ID != "app" || matches[1].Parent != "prod" {
+		t.Errorf("Expected L2 app under prod, got %+v", matches[1])
+	}
+}
@@ -1,8 +1,70 @@
 package domain
 
+import (
+	"slices"
+	"sort"
+	"strconv"
+)
+
 // Taxonomy is the root aggregate containing all taxonomy data.
 type Taxonomy struct {
 	ApiVersion string
 	SegL1s     map[string]Seg
 	SegsL2s    map[string]Seg
+	Levels     map[string]map[string]Seg `json:",omitempty"` // segments below L2 by level ("3", "4", ...)
+	Groups     map[string]Group          `json:",omitempty"` // optional L0 groups of L1s
+	Flows      map[string]Flow           `json:",omitempty"`
+	Templates  map[string]Template       `json:"-"` // resolved into segments by inheritance
+}
+
+// Depth returns the number of segment levels, at least 2.
+func (t Taxonomy) Depth() int {
+	return 2 + len(t.Levels)
+}
+
+// Segs returns the segments of a level, nil if the taxonomy has no such level.
+func (t Taxonomy) Segs(level int) map[string]Seg {
+	switch level {
+	case 1:
+		return t.SegL1s
+	case 2:
+		return t.SegsL2s
+	}
+	return t.Levels[strconv.Itoa(level)]
+}
+
+// SegIDs returns the IDs of the segments of a level, sorted.
+func (t Taxonomy) SegIDs(level int) []string {
+	segs := t.Segs(level)
+	ids := make([]string, 0, len(segs))
+	for id := range segs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ResolveL1 returns the L1 segment with the given ID or alias. aliased reports
+// whether id is a deprecated alias rather than the canonical ID.
+func (t Taxonomy) ResolveL1(id string) (seg Seg, aliased bool, ok bool) {
+	return ResolveSeg(t.SegL1s, id)
+}
+
+// ResolveL2 returns the L2 segment with the given ID or alias. aliased reports
+// whether id is a deprecated alias rather than the canonical ID.
+func (t Taxonomy) ResolveL2(id string) (seg Seg, aliased bool, ok bool) {
+	return ResolveSeg(t.SegsL2s, id)
+}
+
+// ResolveSeg returns the segment in segs with the given ID or alias.
+func ResolveSeg(segs map[string]Seg, id string) (seg Seg, aliased bool, ok bool) {
+	if seg, ok := segs[id]; ok {
+		return seg, false, true
+	}
+	for _, seg := range segs {
+		if slices.Contains(seg.Aliases, id) {
+			return seg, true, true
+		}
+	}
+	return Seg{}, false, false
 }
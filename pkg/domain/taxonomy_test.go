@@ -0,0 +1,46 @@
+package domain
+
+import "testing"
+
+func TestTaxonomyResolve(t *testing.T) {
+	txy := Taxonomy{
+		SegL1s:  map[string]Seg{"prod": {ID: "prod", Aliases: []string{"production"}}},
+		SegsL2s: map[string]Seg{"app": {ID: "app", Aliases: []string{"application", "web"}}},
+	}
+
+	if seg, aliased, ok := txy.ResolveL1("prod"); !ok || aliased || seg.ID != "prod" {
+		t.Errorf("Expected canonical L1 prod, got %s %v %v", seg.ID, aliased, ok)
+	}
+	if seg, aliased, ok := txy.ResolveL1("production"); !ok || !aliased || seg.ID != "prod" {
+		t.Errorf("Expected alias production to resolve to prod, got %s %v %v", seg.ID, aliased, ok)
+	}
+	if seg, aliased, ok := txy.ResolveL2("web"); !ok || !aliased || seg.ID != "app" {
+		t.Errorf("Expected alias web to resolve to app, got %s %v %v", seg.ID, aliased, ok)
+	}
+	if _, _, ok := txy.ResolveL2("production"); ok {
+		t.Error("Expected L1 alias not to resolve at L2")
+	}
+}
+
+func TestTaxonomy_SegGroups(t *testing.T) {
+	txy := Taxonomy{
+		SegL1s: map[string]Seg{
+			"prod-eu": {ID: "prod-eu", Group: "eu"},
+			"prod-us": {ID: "prod-us", Group: "us"},
+			"shared":  {ID: "shared"},
+		},
+		SegsL2s: map[string]Seg{
+			"app": {ID: "app", L1Parents: []string{"prod-us", "prod-eu", "shared"}},
+		},
+	}
+
+	if groups := txy.SegGroups(2, txy.SegsL2s["app"]); len(groups) != 2 || groups[0] != "eu" || groups[1] != "us" {
+		t.Errorf("Expected app in groups [eu us], got %v", groups)
+	}
+	if groups := txy.SegGroups(1, txy.SegL1s["shared"]); len(groups) != 0 {
+		t.Errorf("Expected shared to have no group, got %v", groups)
+	}
+	if members := txy.GroupMembers("eu"); len(members) != 1 || members[0] != "prod-eu" {
+		t.Errorf("Expected eu members [prod-eu], got %v", members)
+	}
+}
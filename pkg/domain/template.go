@@ -0,0 +1,104 @@
+package domain
+
+import "strings"
+
+// Template is an abstract definition of labels, per-parent overrides and metadata that segments
+// below L1 share by listing it in extends. Templates may extend other templates. Later entries in
+// extends win over earlier ones, and a segment's own values win over all of its templates.
+// Overrides are keyed by parent ID and only apply to segments with that parent, or by default to
+// apply to every other parent.
+type Template struct {
+	Version     string                 `yaml:"version,omitempty" json:"-"` // schema version of the file
+	ID          string                 `yaml:"id" json:"id"`
+	Description string                 `yaml:"description,omitempty" json:"description,omitempty"`
+	Extends     []string               `yaml:"extends,omitempty" json:"extends,omitempty"`
+	Labels      []string               `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Overrides   map[string]L1Overrides `yaml:"overrides,omitempty" json:"overrides,omitempty"`
+	Metadata    Metadata               `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+}
+
+// Merge returns the template with other applied on top: other's labels replace labels with the
+// same key, per parent, and other's metadata replaces whole namespaces. Neither input is modified.
+func (t Template) Merge(other Template) Template {
+	result := Template{
+		ID:        other.ID,
+		Labels:    mergeLabels(t.Labels, other.Labels),
+		Metadata:  mergeMetadata(t.Metadata, other.Metadata),
+		Overrides: make(map[string]L1Overrides),
+	}
+	for parentID, override := range t.Overrides {
+		result.Overrides[parentID] = L1Overrides{Labels: override.Labels, Metadata: override.Metadata}
+	}
+	for parentID, override := range other.Overrides {
+		base := result.Overrides[parentID]
+		result.Overrides[parentID] = L1Overrides{
+			Labels:   mergeLabels(base.Labels, override.Labels),
+			Metadata: mergeMetadata(base.Metadata, override.Metadata),
+		}
+	}
+	return result
+}
+
+// ApplyTo merges the template under the segment's own labels, overrides and metadata.
+// The segment's parents must already be expanded. The template's default override applies to
+// every parent the template has no override for. Template overrides for parents the segment
+// doesn't have are ignored. Labels are re-parsed.
+func (t Template) ApplyTo(seg *Seg) error {
+	seg.Labels = mergeLabels(t.Labels, seg.Labels)
+	seg.Metadata = mergeMetadata(t.Metadata, seg.Metadata)
+	for _, parentID := range seg.L1Parents {
+		templateOverride, ok := t.Overrides[parentID]
+		if !ok {
+			templateOverride, ok = t.Overrides[DefaultOverride]
+		}
+		if !ok {
+			continue
+		}
+		if seg.L1Overrides == nil {
+			seg.L1Overrides = make(map[string]L1Overrides)
+		}
+		override := seg.L1Overrides[parentID]
+		override.Labels = mergeLabels(templateOverride.Labels, override.Labels)
+		override.Metadata = mergeMetadata(templateOverride.Metadata, override.Metadata)
+		seg.L1Overrides[parentID] = override
+	}
+	return seg.ParseLabels()
+}
+
+// mergeLabels returns base's labels whose keys own doesn't set, followed by own's labels.
+func mergeLabels(base, own []string) []string {
+	if len(base) == 0 {
+		return own
+	}
+	ownKeys := make(map[string]bool, len(own))
+	for _, label := range own {
+		ownKeys[labelKey(label)] = true
+	}
+	result := make([]string, 0, len(base)+len(own))
+	for _, label := range base {
+		if !ownKeys[labelKey(label)] {
+			result = append(result, label)
+		}
+	}
+	return append(result, own...)
+}
+
+func labelKey(label string) string {
+	key, _, _ := strings.Cut(label, ":")
+	return key
+}
+
+// mergeMetadata returns base with own's namespaces replacing base's.
+func mergeMetadata(base, own Metadata) Metadata {
+	if len(base) == 0 {
+		return own
+	}
+	result := make(Metadata, len(base)+len(own))
+	for ns, value := range base {
+		result[ns] = value
+	}
+	for ns, value := range own {
+		result[ns] = value
+	}
+	return result
+}
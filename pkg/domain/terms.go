@@ -1,13 +1,15 @@
 package domain
 
 import (
+	"fmt"
 	"strings"
 )
 
-// TermConfig holds terminology configuration for L1 and L2 segments.
+// TermConfig holds terminology configuration for each segment level.
 type TermConfig struct {
-	L1 TermDef `yaml:"l1,omitempty"`
-	L2 TermDef `yaml:"l2,omitempty"`
+	L1     TermDef   `yaml:"l1,omitempty"`
+	L2     TermDef   `yaml:"l2,omitempty"`
+	Levels []TermDef `yaml:"levels,omitempty"` // levels below L2, starting at L3
 }
 
 // TermDef defines singular and plural forms for a segment level.
@@ -31,11 +33,26 @@ func (td TermDef) Merge(defaults TermDef) TermDef {
 // Merge merges this TermConfig with defaults, using defaults for any blank fields.
 func (tc TermConfig) Merge(defaults TermConfig) TermConfig {
 	return TermConfig{
-		L1: tc.L1.Merge(defaults.L1),
-		L2: tc.L2.Merge(defaults.L2),
+		L1:     tc.L1.Merge(defaults.L1),
+		L2:     tc.L2.Merge(defaults.L2),
+		Levels: tc.Levels,
 	}
 }
 
+// Level returns the terms for a level, "Level N" for levels below L2 without configured terms.
+func (tc TermConfig) Level(level int) TermDef {
+	switch {
+	case level == 1:
+		return tc.L1
+	case level == 2:
+		return tc.L2
+	case level > 2 && level-3 < len(tc.Levels):
+		return tc.Levels[level-3]
+	}
+	name := fmt.Sprintf("Level %d", level)
+	return TermDef{Singular: name, Plural: name}
+}
+
 // DirName converts the plural form to a kebab-case directory name
 // Examples:
 //
@@ -83,6 +100,13 @@ const TermsConfigSchema = `{
 				},
 				"l2": {
 					"$ref": "#/$defs/termDef" 
+				},
+				"levels": {
+					"type": "array",
+					"description": "Terminology for levels below L2, starting at L3",
+					"items": {
+						"$ref": "#/$defs/termDef"
+					}
 				}
 			},
 			"additionalProperties": false
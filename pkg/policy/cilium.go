@@ -0,0 +1,106 @@
+package policy
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+// ciliumNamespaceKey is the label Cilium sets on endpoints with their namespace.
+const ciliumNamespaceKey = "k8s:io.kubernetes.pod.namespace"
+
+type ciliumNetworkPolicy struct {
+	APIVersion string     `yaml:"apiVersion"`
+	Kind       string     `yaml:"kind"`
+	Metadata   objectMeta `yaml:"metadata"`
+	Spec       ciliumSpec `yaml:"spec"`
+}
+
+type ciliumSpec struct {
+	Description      string              `yaml:"description,omitempty"`
+	EndpointSelector labelSelector       `yaml:"endpointSelector"`
+	Ingress          []ciliumIngressRule `yaml:"ingress"`
+}
+
+type ciliumIngressRule struct {
+	FromEndpoints []ciliumEndpointSelector `yaml:"fromEndpoints"`
+	ToPorts       []ciliumPortRule         `yaml:"toPorts,omitempty"`
+	ICMPs         []ciliumICMPRule         `yaml:"icmps,omitempty"`
+}
+
+// ciliumEndpointSelector adds a namespace existence expression so sources match in any namespace.
+type ciliumEndpointSelector struct {
+	MatchLabels      map[string]string       `yaml:"matchLabels"`
+	MatchExpressions []ciliumMatchExpression `yaml:"matchExpressions"`
+}
+
+type ciliumMatchExpression struct {
+	Key      string `yaml:"key"`
+	Operator string `yaml:"operator"`
+}
+
+type ciliumPortRule struct {
+	Ports []ciliumPort `yaml:"ports"`
+}
+
+type ciliumPort struct {
+	Port     string `yaml:"port"`
+	EndPort  int    `yaml:"endPort,omitempty"`
+	Protocol string `yaml:"protocol"`
+}
+
+type ciliumICMPRule struct {
+	Fields []ciliumICMPField `yaml:"fields"`
+}
+
+type ciliumICMPField struct {
+	Type int `yaml:"type"`
+}
+
+// icmpEchoRequest is the ICMP type allowed for icmp flows.
+const icmpEchoRequest = 8
+
+// CiliumNetworkPolicies renders rules as CiliumNetworkPolicy documents allowing ingress to each destination.
+// ICMP rules allow echo requests.
+func CiliumNetworkPolicies(rules []Rule, def PoliciesDef) ([]byte, error) {
+	def = def.Merge()
+	docs := make([]any, 0, len(rules))
+	for _, rule := range rules {
+		ingress := ciliumIngressRule{
+			FromEndpoints: []ciliumEndpointSelector{{
+				MatchLabels:      rule.Source.Labels(def),
+				MatchExpressions: []ciliumMatchExpression{{Key: ciliumNamespaceKey, Operator: "Exists"}},
+			}},
+		}
+		switch rule.Protocol {
+		case domain.FlowProtocolICMP:
+			ingress.ICMPs = []ciliumICMPRule{{Fields: []ciliumICMPField{{Type: icmpEchoRequest}}}}
+		case domain.FlowProtocolTCP, domain.FlowProtocolUDP:
+			portRule := ciliumPortRule{}
+			for _, pr := range rule.Ports {
+				port := ciliumPort{Port: strconv.Itoa(pr.From), Protocol: strings.ToUpper(rule.Protocol)}
+				if pr.To != pr.From {
+					port.EndPort = pr.To
+				}
+				portRule.Ports = append(portRule.Ports, port)
+			}
+			if len(rule.Ports) == 0 {
+				// Port 0 allows all ports of the protocol
+				portRule.Ports = []ciliumPort{{Port: "0", Protocol: strings.ToUpper(rule.Protocol)}}
+			}
+			ingress.ToPorts = []ciliumPortRule{portRule}
+		}
+		docs = append(docs, ciliumNetworkPolicy{
+			APIVersion: "cilium.io/v2",
+			Kind:       "CiliumNetworkPolicy",
+			Metadata:   ruleMeta(rule, def),
+			Spec: ciliumSpec{
+				Description:      rule.Rationale,
+				EndpointSelector: labelSelector{MatchLabels: rule.Destination.Labels(def)},
+				Ingress:          []ciliumIngressRule{ingress},
+			},
+		})
+	}
+	return encodeYAMLDocuments(docs)
+}
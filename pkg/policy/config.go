@@ -0,0 +1,59 @@
+// Package policy generates network enforcement artefacts from the taxonomy's flows.
+package policy
+
+import "github.com/kvql/bunsceal/pkg/domain"
+
+const (
+	// DefaultL1LabelKey is the workload label/tag key holding the L1 segment ID.
+	DefaultL1LabelKey = domain.SegmentL1LabelKey
+	// DefaultL2LabelKey is the workload label/tag key holding the L2 segment ID.
+	DefaultL2LabelKey = domain.SegmentL2LabelKey
+)
+
+// PoliciesDef configures how segment membership is selected in generated policies.
+type PoliciesDef struct {
+	L1LabelKey string `yaml:"l1_label_key,omitempty"`
+	L2LabelKey string `yaml:"l2_label_key,omitempty"`
+	Namespace  string `yaml:"namespace,omitempty"`
+}
+
+// Merge returns the definition with defaults applied to empty label keys.
+func (p PoliciesDef) Merge() PoliciesDef {
+	if p.L1LabelKey == "" {
+		p.L1LabelKey = DefaultL1LabelKey
+	}
+	if p.L2LabelKey == "" {
+		p.L2LabelKey = DefaultL2LabelKey
+	}
+	return p
+}
+
+const PoliciesConfigSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://github.com/kvql/bunsceal/pkg/config/schemas/policies.json",
+	"title": "Policy Generation Configuration",
+	"$defs": {
+		"policies": {
+			"type": "object",
+			"description": "Configuration for network policy generation from flows",
+			"additionalProperties": false,
+			"properties": {
+				"l1_label_key": {
+					"type": "string",
+					"description": "Label/tag key identifying a workload's L1 segment. Defaults to bunsceal.segment/l1",
+					"minLength": 1
+				},
+				"l2_label_key": {
+					"type": "string",
+					"description": "Label/tag key identifying a workload's L2 segment. Defaults to bunsceal.segment/l2",
+					"minLength": 1
+				},
+				"namespace": {
+					"type": "string",
+					"description": "Kubernetes namespace for generated policies. Omitted when empty",
+					"pattern": "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+				}
+			}
+		}
+	}
+}`
@@ -0,0 +1,47 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+const (
+	NetworkPoliciesFile       = "networkpolicies.yaml"
+	CiliumNetworkPoliciesFile = "ciliumnetworkpolicies.yaml"
+	SecurityGroupsFile        = "security-groups.tf"
+)
+
+// GeneratePolicies writes the Kubernetes, Cilium and AWS security group artefacts for the taxonomy's flows to dir.
+func GeneratePolicies(txy domain.Taxonomy, dir string, def PoliciesDef) error {
+	rules, err := ResolveRules(txy)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	k8s, err := NetworkPolicies(rules, def)
+	if err != nil {
+		return err
+	}
+	cilium, err := CiliumNetworkPolicies(rules, def)
+	if err != nil {
+		return err
+	}
+	outputs := map[string][]byte{
+		NetworkPoliciesFile:       k8s,
+		CiliumNetworkPoliciesFile: cilium,
+		SecurityGroupsFile:        SecurityGroupsTerraform(rules, def),
+	}
+	for name, data := range outputs {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return err
+		}
+	}
+	o11y.Log.Printf("Generated %d policy rule(s) in %s", len(rules), dir)
+	return nil
+}
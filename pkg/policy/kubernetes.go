@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+	"gopkg.in/yaml.v3"
+)
+
+type objectMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type labelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels,omitempty"`
+}
+
+type networkPolicy struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   objectMeta        `yaml:"metadata"`
+	Spec       networkPolicySpec `yaml:"spec"`
+}
+
+type networkPolicySpec struct {
+	PodSelector labelSelector       `yaml:"podSelector"`
+	PolicyTypes []string            `yaml:"policyTypes"`
+	Ingress     []networkPolicyRule `yaml:"ingress"`
+}
+
+type networkPolicyRule struct {
+	From  []networkPolicyPeer `yaml:"from"`
+	Ports []networkPolicyPort `yaml:"ports,omitempty"`
+}
+
+// networkPolicyPeer always sets an empty namespaceSelector so sources match in any namespace.
+type networkPolicyPeer struct {
+	NamespaceSelector labelSelector `yaml:"namespaceSelector"`
+	PodSelector       labelSelector `yaml:"podSelector"`
+}
+
+type networkPolicyPort struct {
+	Protocol string `yaml:"protocol"`
+	Port     int    `yaml:"port,omitempty"`
+	EndPort  int    `yaml:"endPort,omitempty"`
+}
+
+// ruleMeta returns the shared metadata of a generated policy.
+func ruleMeta(rule Rule, def PoliciesDef) objectMeta {
+	return objectMeta{
+		Name:        rule.Name,
+		Namespace:   def.Namespace,
+		Labels:      map[string]string{"app.kubernetes.io/managed-by": "bunsceal"},
+		Annotations: map[string]string{"bunsceal.flow/id": rule.FlowID, "bunsceal.flow/rationale": rule.Rationale},
+	}
+}
+
+// NetworkPolicies renders rules as Kubernetes NetworkPolicy documents allowing ingress to each destination.
+// NetworkPolicy has no ICMP support, so ICMP rules are logged and skipped.
+func NetworkPolicies(rules []Rule, def PoliciesDef) ([]byte, error) {
+	def = def.Merge()
+	var docs []any
+	for _, rule := range rules {
+		if rule.Protocol == domain.FlowProtocolICMP {
+			o11y.Log.Printf("WARNING: %s: Kubernetes NetworkPolicy does not support icmp, skipped", rule.Name)
+			continue
+		}
+		ingress := networkPolicyRule{
+			From: []networkPolicyPeer{{PodSelector: labelSelector{MatchLabels: rule.Source.Labels(def)}}},
+		}
+		for _, pr := range rule.Ports {
+			port := networkPolicyPort{Protocol: strings.ToUpper(rule.Protocol), Port: pr.From}
+			if pr.To != pr.From {
+				port.EndPort = pr.To
+			}
+			ingress.Ports = append(ingress.Ports, port)
+		}
+		if len(rule.Ports) == 0 && rule.Protocol != domain.FlowProtocolAny {
+			// All ports of the flow's protocol only
+			ingress.Ports = []networkPolicyPort{{Protocol: strings.ToUpper(rule.Protocol)}}
+		}
+		docs = append(docs, networkPolicy{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+			Metadata:   ruleMeta(rule, def),
+			Spec: networkPolicySpec{
+				PodSelector: labelSelector{MatchLabels: rule.Destination.Labels(def)},
+				PolicyTypes: []string{"Ingress"},
+				Ingress:     []networkPolicyRule{ingress},
+			},
+		})
+	}
+	return encodeYAMLDocuments(docs)
+}
+
+// encodeYAMLDocuments writes docs as a multi-document YAML stream.
+func encodeYAMLDocuments(docs []any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
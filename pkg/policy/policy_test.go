@@ -0,0 +1,182 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"gopkg.in/yaml.v3"
+)
+
+func newPolicyTaxonomy(flows ...domain.Flow) domain.Taxonomy {
+	txy := domain.Taxonomy{
+		SegL1s: map[string]domain.Seg{"prod": {ID: "prod"}, "stg": {ID: "stg"}},
+		SegsL2s: map[string]domain.Seg{
+			"app": {ID: "app", L1Parents: []string{"prod", "stg"}},
+			"db":  {ID: "db", L1Parents: []string{"prod"}},
+			"ci":  {ID: "ci", L1Parents: []string{"stg"}},
+		},
+		Flows: map[string]domain.Flow{},
+	}
+	for _, f := range flows {
+		txy.Flows[f.ID] = f
+	}
+	return txy
+}
+
+var appToDB = domain.Flow{
+	ID: "app-to-db", Level: 2, Source: "app", Destination: "db",
+	Protocol: domain.FlowProtocolTCP, Ports: []string{"5432", "9000-9100"}, Direction: domain.FlowOneWay,
+	Rationale: "App reads its database",
+}
+
+func TestResolveRules(t *testing.T) {
+	t.Run("Generates L2 rules per shared parent", func(t *testing.T) {
+		rules, err := ResolveRules(newPolicyTaxonomy(appToDB))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(rules) != 1 {
+			t.Fatalf("Expected 1 rule for the single shared parent, got %+v", rules)
+		}
+		if rules[0].Source != (Endpoint{L1: "prod", L2: "app"}) || rules[0].Destination != (Endpoint{L1: "prod", L2: "db"}) {
+			t.Errorf("Expected prod app to prod db, got %+v", rules[0])
+		}
+		if len(rules[0].Ports) != 2 || rules[0].Ports[1] != (domain.PortRange{From: 9000, To: 9100}) {
+			t.Errorf("Expected parsed ports, got %v", rules[0].Ports)
+		}
+	})
+
+	t.Run("Bidirectional flows produce a reverse rule", func(t *testing.T) {
+		f := domain.Flow{ID: "prod-stg", Level: 1, Source: "prod", Destination: "stg", Protocol: domain.FlowProtocolAny, Direction: domain.FlowBidirectional}
+
+		rules, _ := ResolveRules(newPolicyTaxonomy(f))
+
+		if len(rules) != 2 || rules[1].Name != "bunsceal-prod-stg-reverse" || rules[1].Source.L1 != "stg" {
+			t.Errorf("Expected forward and reverse rules, got %+v", rules)
+		}
+	})
+
+	t.Run("Skips L2 flows without shared parent", func(t *testing.T) {
+		f := appToDB
+		f.ID, f.Source = "ci-to-db", "ci"
+
+		rules, _ := ResolveRules(newPolicyTaxonomy(f))
+
+		if len(rules) != 0 {
+			t.Errorf("Expected no rules, got %+v", rules)
+		}
+	})
+
+	t.Run("Fails for flows below L2", func(t *testing.T) {
+		f := appToDB
+		f.ID, f.Level = "api-to-worker", 3
+
+		if _, err := ResolveRules(newPolicyTaxonomy(f)); err == nil || !strings.Contains(err.Error(), "only cover L1 and L2 flows") {
+			t.Errorf("Expected error for L3 flow, got %v", err)
+		}
+	})
+}
+
+func TestNetworkPolicies(t *testing.T) {
+	icmp := domain.Flow{ID: "ping", Level: 1, Source: "prod", Destination: "stg", Protocol: domain.FlowProtocolICMP, Direction: domain.FlowOneWay}
+	rules, _ := ResolveRules(newPolicyTaxonomy(appToDB, icmp))
+
+	data, err := NetworkPolicies(rules, PoliciesDef{Namespace: "apps", L2LabelKey: "example.com/segment"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var docs []networkPolicy
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		var doc networkPolicy
+		if dec.Decode(&doc) != nil {
+			break
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("Expected 1 policy with icmp skipped, got %d", len(docs))
+	}
+	np := docs[0]
+	if np.Metadata.Namespace != "apps" || np.Spec.PodSelector.MatchLabels["example.com/segment"] != "db" || np.Spec.PodSelector.MatchLabels[DefaultL1LabelKey] != "prod" {
+		t.Errorf("Expected destination selector with configured keys, got %+v", np)
+	}
+	ports := np.Spec.Ingress[0].Ports
+	if len(ports) != 2 || ports[1].Port != 9000 || ports[1].EndPort != 9100 || ports[1].Protocol != "TCP" {
+		t.Errorf("Expected port range 9000-9100/TCP, got %+v", ports)
+	}
+	if !strings.Contains(string(data), "namespaceSelector: {}") {
+		t.Error("Expected sources to match in any namespace")
+	}
+}
+
+func TestCiliumNetworkPolicies(t *testing.T) {
+	icmp := domain.Flow{ID: "ping", Level: 1, Source: "prod", Destination: "stg", Protocol: domain.FlowProtocolICMP, Direction: domain.FlowOneWay}
+	rules, _ := ResolveRules(newPolicyTaxonomy(icmp))
+
+	data, err := CiliumNetworkPolicies(rules, PoliciesDef{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var cnp ciliumNetworkPolicy
+	if err := yaml.Unmarshal(data, &cnp); err != nil {
+		t.Fatalf("Invalid YAML: %v", err)
+	}
+	ingress := cnp.Spec.Ingress[0]
+	if cnp.Kind != "CiliumNetworkPolicy" || cnp.Spec.EndpointSelector.MatchLabels[DefaultL1LabelKey] != "stg" {
+		t.Errorf("Expected policy selecting stg, got %+v", cnp)
+	}
+	if len(ingress.ICMPs) != 1 || len(ingress.ToPorts) != 0 || ingress.FromEndpoints[0].MatchLabels[DefaultL1LabelKey] != "prod" {
+		t.Errorf("Expected icmp rule from prod, got %+v", ingress)
+	}
+}
+
+func TestSecurityGroupsTerraform(t *testing.T) {
+	rules, _ := ResolveRules(newPolicyTaxonomy(appToDB))
+
+	tf := string(SecurityGroupsTerraform(rules, PoliciesDef{}))
+
+	for _, want := range []string{
+		`resource "aws_security_group" "prod_app"`,
+		`"bunsceal.segment/l2" = "db"`,
+		`resource "aws_vpc_security_group_ingress_rule" "bunsceal_app_to_db_prod_1"`,
+		"security_group_id            = aws_security_group.prod_db.id",
+		"referenced_security_group_id = aws_security_group.prod_app.id",
+		"from_port                    = 9000",
+	} {
+		if !strings.Contains(tf, want) {
+			t.Errorf("Expected Terraform to contain %q, got:\n%s", want, tf)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("données", 4); got != "donn" {
+		t.Errorf("Expected donn, got %q", got)
+	}
+	if got := truncate("éé", 1); got != "é" || !utf8.ValidString(got) {
+		t.Errorf("Expected a single é, got %q", got)
+	}
+	if got := truncate("short", 255); got != "short" {
+		t.Errorf("Expected short strings unchanged, got %q", got)
+	}
+}
+
+func TestGeneratePolicies(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "policies")
+
+	if err := GeneratePolicies(newPolicyTaxonomy(appToDB), dir, PoliciesDef{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, name := range []string{NetworkPoliciesFile, CiliumNetworkPoliciesFile, SecurityGroupsFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+// Endpoint identifies the workloads of one segment. L2 is empty for L1 flows.
+type Endpoint struct {
+	L1 string
+	L2 string
+}
+
+// Labels returns the label/tag selector for the endpoint.
+func (e Endpoint) Labels(def PoliciesDef) map[string]string {
+	labels := map[string]string{def.L1LabelKey: e.L1}
+	if e.L2 != "" {
+		labels[def.L2LabelKey] = e.L2
+	}
+	return labels
+}
+
+// Name returns the endpoint as l1 or l1-l2.
+func (e Endpoint) Name() string {
+	if e.L2 == "" {
+		return e.L1
+	}
+	return e.L1 + "-" + e.L2
+}
+
+// Rule allows traffic from Source to Destination; it is the unit every generator renders.
+type Rule struct {
+	Name        string
+	FlowID      string
+	Source      Endpoint
+	Destination Endpoint
+	Protocol    string
+	Ports       []domain.PortRange
+	Rationale   string
+}
+
+// ResolveRules expands the taxonomy's flows into directional rules, sorted by name.
+// Bidirectional flows produce a rule each way. L2 flows produce a rule per L1 parent
+// shared by source and destination; flows without a shared parent are logged and skipped.
+// Endpoints select workloads by L1 and L2 label, so flows below L2 are an error.
+func ResolveRules(txy domain.Taxonomy) ([]Rule, error) {
+	ids := make([]string, 0, len(txy.Flows))
+	for id := range txy.Flows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var rules []Rule
+	for _, id := range ids {
+		flow := txy.Flows[id]
+		if flow.Level > 2 {
+			return nil, fmt.Errorf("flow %s: policies only cover L1 and L2 flows, flow is L%d", id, flow.Level)
+		}
+		ports, err := flow.PortRanges()
+		if err != nil {
+			return nil, fmt.Errorf("flow %s: %w", id, err)
+		}
+
+		type pair struct{ src, dst Endpoint }
+		var pairs []pair
+		if flow.Level == 1 {
+			pairs = append(pairs, pair{Endpoint{L1: flow.Source}, Endpoint{L1: flow.Destination}})
+		} else {
+			parents := sharedParents(txy.SegsL2s[flow.Source], txy.SegsL2s[flow.Destination])
+			if len(parents) == 0 {
+				o11y.Log.Printf("WARNING: flow %s: %s and %s share no L1 parent, no policy generated", id, flow.Source, flow.Destination)
+			}
+			for _, l1 := range parents {
+				pairs = append(pairs, pair{Endpoint{L1: l1, L2: flow.Source}, Endpoint{L1: l1, L2: flow.Destination}})
+			}
+		}
+
+		for _, p := range pairs {
+			rules = append(rules, newRule(flow, p.src, p.dst, ports, ""))
+			if flow.Direction == domain.FlowBidirectional {
+				rules = append(rules, newRule(flow, p.dst, p.src, ports, "reverse"))
+			}
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules, nil
+}
+
+func newRule(flow domain.Flow, src, dst Endpoint, ports []domain.PortRange, suffix string) Rule {
+	parts := []string{"bunsceal", flow.ID}
+	if src.L2 != "" {
+		parts = append(parts, src.L1)
+	}
+	if suffix != "" {
+		parts = append(parts, suffix)
+	}
+	return Rule{
+		Name:        dnsName(strings.Join(parts, "-")),
+		FlowID:      flow.ID,
+		Source:      src,
+		Destination: dst,
+		Protocol:    flow.Protocol,
+		Ports:       ports,
+		Rationale:   flow.Rationale,
+	}
+}
+
+// sharedParents returns the L1 parents common to both segments, sorted.
+func sharedParents(a, b domain.Seg) []string {
+	inB := make(map[string]bool, len(b.L1Parents))
+	for _, p := range b.L1Parents {
+		inB[p] = true
+	}
+	var shared []string
+	for _, p := range a.L1Parents {
+		if inB[p] {
+			shared = append(shared, p)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// dnsName converts a name to a Kubernetes compatible lowercase DNS label.
+func dnsName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	out := strings.Trim(b.String(), "-")
+	if len(out) > 253 {
+		out = strings.TrimRight(out[:253], "-")
+	}
+	return out
+}
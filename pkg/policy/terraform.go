@@ -0,0 +1,117 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+// tfIdentifier converts a name to a Terraform resource name.
+func tfIdentifier(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// tfMap renders a map as an HCL object with sorted keys.
+func tfMap(m map[string]string, indent string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s  %s = %s\n", indent, strconv.Quote(k), strconv.Quote(m[k]))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// ipProtocol maps a flow protocol to the security group rule protocol.
+func ipProtocol(protocol string) string {
+	if protocol == domain.FlowProtocolAny {
+		return "-1"
+	}
+	return protocol
+}
+
+// SecurityGroupsTerraform renders rules as AWS security groups, one per endpoint, and
+// ingress rules referencing the source group. Segment membership is carried in the group tags.
+// The VPC is taken from the vpc_id variable.
+func SecurityGroupsTerraform(rules []Rule, def PoliciesDef) []byte {
+	def = def.Merge()
+	endpoints := map[string]Endpoint{}
+	for _, rule := range rules {
+		endpoints[rule.Source.Name()] = rule.Source
+		endpoints[rule.Destination.Name()] = rule.Destination
+	}
+	names := make([]string, 0, len(endpoints))
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# Generated by bunsceal from the taxonomy flows. Do not edit.\n\n")
+	b.WriteString("variable \"vpc_id\" {\n  type        = string\n  description = \"VPC for the segment security groups\"\n}\n")
+
+	for _, name := range names {
+		fmt.Fprintf(&b, "\nresource \"aws_security_group\" %s {\n", strconv.Quote(tfIdentifier(name)))
+		fmt.Fprintf(&b, "  name        = %s\n", strconv.Quote("bunsceal-"+name))
+		fmt.Fprintf(&b, "  description = %s\n", strconv.Quote("Members of segment "+name))
+		b.WriteString("  vpc_id      = var.vpc_id\n")
+		fmt.Fprintf(&b, "  tags = %s\n}\n", tfMap(endpoints[name].Labels(def), "  "))
+	}
+
+	for _, rule := range rules {
+		ports := rule.Ports
+		if len(ports) == 0 {
+			ports = []domain.PortRange{{}}
+		}
+		for i, pr := range ports {
+			resource := tfIdentifier(rule.Name)
+			if len(ports) > 1 {
+				resource += "_" + strconv.Itoa(i)
+			}
+			fmt.Fprintf(&b, "\nresource \"aws_vpc_security_group_ingress_rule\" %s {\n", strconv.Quote(resource))
+			fmt.Fprintf(&b, "  security_group_id            = aws_security_group.%s.id\n", tfIdentifier(rule.Destination.Name()))
+			fmt.Fprintf(&b, "  referenced_security_group_id = aws_security_group.%s.id\n", tfIdentifier(rule.Source.Name()))
+			fmt.Fprintf(&b, "  ip_protocol                  = %s\n", strconv.Quote(ipProtocol(rule.Protocol)))
+			switch {
+			case rule.Protocol == domain.FlowProtocolICMP:
+				b.WriteString("  from_port                    = -1\n  to_port                      = -1\n")
+			case rule.Protocol == domain.FlowProtocolAny:
+			case len(rule.Ports) == 0:
+				b.WriteString("  from_port                    = 0\n  to_port                      = 65535\n")
+			default:
+				fmt.Fprintf(&b, "  from_port                    = %d\n  to_port                      = %d\n", pr.From, pr.To)
+			}
+			fmt.Fprintf(&b, "  description                  = %s\n}\n", strconv.Quote(truncate(rule.FlowID+": "+rule.Rationale, 255)))
+		}
+	}
+	return []byte(b.String())
+}
+
+// truncate shortens s to at most n characters, cutting on a rune boundary so the result stays valid
+// UTF-8; security group rule descriptions are limited to 255 characters.
+func truncate(s string, n int) string {
+	count := 0
+	for i := range s {
+		if count == n {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
@@ -0,0 +1,217 @@
+package application
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+const (
+	CompatSegmentRemoved  = "segment-removed"
+	CompatParentRemoved   = "parent-removed"
+	CompatLabelKeyRemoved = "label-key-removed"
+
+	BumpNone  = "none"
+	BumpMinor = "minor"
+	BumpMajor = "major"
+)
+
+// CompatIssue is a removal that can break consumers of a published taxonomy.
+// Allowed removals were announced through deprecation; Reason says how.
+type CompatIssue struct {
+	Kind    string `json:"kind"`
+	Segment string `json:"segment"`
+	Detail  string `json:"detail"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func (i CompatIssue) String() string {
+	s := fmt.Sprintf("%s %s: %s", i.Kind, i.Segment, i.Detail)
+	if i.Allowed {
+		s += " (allowed: " + i.Reason + ")"
+	}
+	return s
+}
+
+// CompatReport is the result of comparing the current taxonomy with a published export.
+type CompatReport struct {
+	PublishedVersion string        `json:"published_version"`
+	Issues           []CompatIssue `json:"issues,omitempty"`
+	Additions        int           `json:"additions"`
+	Bump             string        `json:"bump"`
+	SuggestedVersion string        `json:"suggested_version"`
+}
+
+// Failed reports whether any removal was not announced.
+func (r CompatReport) Failed() bool {
+	for _, issue := range r.Issues {
+		if !issue.Allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCompat compares the current taxonomy with a published one. Removed segment IDs,
+// removed parents and removed label keys are issues, allowed when the segment was deprecated
+// or retired in the published taxonomy (lifecycle plugin), listed in the compat config, or when
+// the removed ID is an alias of a current segment.
+// Any removal needs a major version bump, additions alone a minor one.
+// Both taxonomies must have inheritance applied.
+func CheckCompat(published, current domain.Taxonomy, cfg configdomain.CompatConfig) CompatReport {
+	c := compatChecker{
+		lifecycle: plugins.NewLifecyclePlugin(&plugins.LifecycleConfig{}, plugins.NsPrefix),
+		cfg:       cfg,
+		published: published,
+		current:   current,
+	}
+	for level := 1; level <= max(published.Depth(), current.Depth()); level++ {
+		c.checkLevel(level)
+	}
+
+	report := CompatReport{PublishedVersion: published.ApiVersion, Issues: c.issues, Additions: c.additions, Bump: BumpNone}
+	switch {
+	case len(c.issues) > 0:
+		report.Bump = BumpMajor
+	case c.additions > 0:
+		report.Bump = BumpMinor
+	}
+	report.SuggestedVersion = SuggestVersion(published.ApiVersion, report.Bump)
+	return report
+}
+
+type compatChecker struct {
+	lifecycle *plugins.LifecyclePlugin
+	cfg       configdomain.CompatConfig
+	published domain.Taxonomy
+	current   domain.Taxonomy
+	issues    []CompatIssue
+	additions int
+}
+
+// deprecated returns why removals from seg are allowed, or "" if they aren't.
+func (c *compatChecker) deprecated(seg domain.Seg, parentID string) string {
+	if slices.Contains(c.cfg.DeprecatedSegments, seg.ID) {
+		return "listed in compat.deprecated_segments"
+	}
+	if state := c.lifecycle.State(seg, parentID); state == plugins.LifecycleDeprecated || state == plugins.LifecycleRetired {
+		return "published as " + state
+	}
+	return ""
+}
+
+// parentDeprecated returns why a segment may leave parentID: either the segment was deprecated
+// there or the parent itself was deprecated.
+func (c *compatChecker) parentDeprecated(level int, seg domain.Seg, parentID string) string {
+	if reason := c.deprecated(seg, parentID); reason != "" {
+		return reason
+	}
+	if parent, ok := c.published.Segs(level - 1)[parentID]; ok {
+		if reason := c.deprecated(parent, ""); reason != "" {
+			return fmt.Sprintf("L%d %s %s", level-1, parentID, reason)
+		}
+	}
+	return ""
+}
+
+func (c *compatChecker) add(kind, segment, detail, reason string) {
+	c.issues = append(c.issues, CompatIssue{Kind: kind, Segment: segment, Detail: detail, Allowed: reason != "", Reason: reason})
+}
+
+func (c *compatChecker) checkLevel(level int) {
+	published, current := c.published.Segs(level), c.current.Segs(level)
+	name := fmt.Sprintf("L%d", level)
+	for _, id := range sortedKeys(current) {
+		if _, ok := published[id]; !ok {
+			c.additions++
+		}
+	}
+	for _, id := range sortedKeys(published) {
+		old := published[id]
+		seg, aliased, ok := domain.ResolveSeg(current, id)
+		if !ok {
+			c.add(CompatSegmentRemoved, name+" "+id, "segment ID no longer exists", c.deprecated(old, ""))
+			continue
+		}
+		if aliased {
+			c.add(CompatSegmentRemoved, name+" "+id, "segment ID no longer exists", "alias of "+seg.ID)
+		}
+		if level == 1 {
+			c.checkLabelKeys(name+" "+id, old, "", seg, "")
+			continue
+		}
+		var parents []string
+		for _, parent := range sortedCopy(old.L1Parents) {
+			canonical := c.canonicalParent(level, parent)
+			parents = append(parents, canonical)
+			if !slices.Contains(seg.L1Parents, canonical) {
+				c.add(CompatParentRemoved, name+" "+id, fmt.Sprintf("no longer a child of L%d %s", level-1, parent), c.parentDeprecated(level, old, parent))
+				continue
+			}
+			c.checkLabelKeys(fmt.Sprintf("%s %s in L%d %s", name, id, level-1, parent), old, parent, seg, canonical)
+		}
+		for _, parent := range seg.L1Parents {
+			if !slices.Contains(parents, parent) {
+				c.additions++
+			}
+		}
+	}
+}
+
+// canonicalParent returns the current ID of a published parent of a segment at level, following aliases.
+func (c *compatChecker) canonicalParent(level int, id string) string {
+	if seg, _, ok := domain.ResolveSeg(c.current.Segs(level-1), id); ok {
+		return seg.ID
+	}
+	return id
+}
+
+// checkLabelKeys reports label keys removed from a segment's effective labels.
+// Rationale keys are skipped, they go with the key they explain.
+func (c *compatChecker) checkLabelKeys(name string, old domain.Seg, oldParent string, seg domain.Seg, parent string) {
+	before, after := old.EffectiveLabels(oldParent), seg.EffectiveLabels(parent)
+	for _, key := range sortedKeys(before) {
+		if strings.HasSuffix(key, "_rationale") {
+			continue
+		}
+		if _, ok := after[key]; ok {
+			continue
+		}
+		reason := c.deprecated(old, oldParent)
+		if slices.Contains(c.cfg.DeprecatedLabelKeys, key) {
+			reason = "listed in compat.deprecated_label_keys"
+		}
+		c.add(CompatLabelKeyRemoved, name, "label key "+key+" removed", reason)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok && !strings.HasSuffix(key, "_rationale") {
+			c.additions++
+		}
+	}
+}
+
+// apiVersionPattern matches Kubernetes style versions such as v1, v1beta1 and v2alpha3.
+var apiVersionPattern = regexp.MustCompile(`^v([0-9]+)(?:(alpha|beta)([0-9]+))?$`)
+
+// SuggestVersion returns the version to publish after a bump from version. API versions only
+// change for breaking changes: a major bump increments the pre-release number (v1beta1 to v1beta2)
+// or, for stable versions, the major version (v1 to v2). Unrecognised versions are returned unchanged.
+func SuggestVersion(version, bump string) string {
+	m := apiVersionPattern.FindStringSubmatch(version)
+	if m == nil || bump != BumpMajor {
+		return version
+	}
+	if m[2] == "" {
+		major, _ := strconv.Atoi(m[1])
+		return "v" + strconv.Itoa(major+1)
+	}
+	pre, _ := strconv.Atoi(m[3])
+	return "v" + m[1] + m[2] + strconv.Itoa(pre+1)
+}
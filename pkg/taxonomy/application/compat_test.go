@@ -0,0 +1,132 @@
+package application
+
+import (
+	"testing"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+func findCompatIssue(issues []CompatIssue, kind, segment string) *CompatIssue {
+	for i, issue := range issues {
+		if issue.Kind == kind && issue.Segment == segment {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+func TestCheckCompat(t *testing.T) {
+	deprecated := plugins.NsPrefix + "lifecycle/state:" + plugins.LifecycleDeprecated
+	published := domain.Taxonomy{
+		ApiVersion: "v1beta1",
+		SegL1s: map[string]domain.Seg{
+			"prod":   newTestSegWithLabels("prod", []string{label("sensitivity", "high"), label("criticality", "1")}),
+			"legacy": newTestSegWithLabels("legacy", []string{deprecated}),
+			"sbx":    newTestSegWithLabels("sbx", nil),
+		},
+		SegsL2s: map[string]domain.Seg{
+			"app": newDiffL2("app", []string{"prod", "sbx"}, label("sensitivity", "low")),
+		},
+	}
+	current := domain.Taxonomy{
+		SegL1s: map[string]domain.Seg{
+			"prod": newTestSegWithLabels("prod", []string{label("sensitivity", "high")}),
+			"new":  newTestSegWithLabels("new", nil),
+		},
+		SegsL2s: map[string]domain.Seg{
+			"app": newDiffL2("app", []string{"prod"}, label("sensitivity", "low")),
+		},
+	}
+
+	report := CheckCompat(published, current, configdomain.CompatConfig{})
+	if !report.Failed() {
+		t.Fatal("Expected unannounced removals to fail")
+	}
+	if issue := findCompatIssue(report.Issues, CompatSegmentRemoved, "L1 legacy"); issue == nil || !issue.Allowed {
+		t.Errorf("Expected removal of deprecated segment to be allowed, got %+v", issue)
+	}
+	if issue := findCompatIssue(report.Issues, CompatSegmentRemoved, "L1 sbx"); issue == nil || issue.Allowed {
+		t.Errorf("Expected removal of sbx to be disallowed, got %+v", issue)
+	}
+	if issue := findCompatIssue(report.Issues, CompatParentRemoved, "L2 app"); issue == nil || issue.Allowed {
+		t.Errorf("Expected removed parent to be disallowed, got %+v", issue)
+	}
+	if issue := findCompatIssue(report.Issues, CompatLabelKeyRemoved, "L1 prod"); issue == nil || issue.Allowed {
+		t.Errorf("Expected removed label key to be disallowed, got %+v", issue)
+	}
+	if report.Bump != BumpMajor || report.SuggestedVersion != "v1beta2" {
+		t.Errorf("Expected major bump to v1beta2, got %s %s", report.Bump, report.SuggestedVersion)
+	}
+
+	report = CheckCompat(published, current, configdomain.CompatConfig{
+		DeprecatedSegments:  []string{"sbx"},
+		DeprecatedLabelKeys: []string{testNs + "/criticality"},
+	})
+	if report.Failed() {
+		t.Errorf("Expected announced removals to pass, got %v", report.Issues)
+	}
+	if report.Additions != 1 {
+		t.Errorf("Expected 1 addition, got %d", report.Additions)
+	}
+}
+
+func TestCheckCompat_AdditionsOnly(t *testing.T) {
+	published := domain.Taxonomy{
+		ApiVersion: "v1",
+		SegL1s:     map[string]domain.Seg{"prod": newTestSegWithLabels("prod", nil)},
+	}
+	current := domain.Taxonomy{
+		SegL1s: map[string]domain.Seg{"prod": newTestSegWithLabels("prod", []string{label("sensitivity", "high")})},
+	}
+	report := CheckCompat(published, current, configdomain.CompatConfig{})
+	if report.Failed() || len(report.Issues) != 0 {
+		t.Errorf("Expected no issues, got %v", report.Issues)
+	}
+	if report.Bump != BumpMinor || report.SuggestedVersion != "v1" {
+		t.Errorf("Expected minor bump keeping v1, got %s %s", report.Bump, report.SuggestedVersion)
+	}
+}
+
+func TestSuggestVersion(t *testing.T) {
+	cases := []struct{ version, bump, want string }{
+		{"v1", BumpMajor, "v2"},
+		{"v1beta1", BumpMajor, "v1beta2"},
+		{"v2alpha9", BumpMajor, "v2alpha10"},
+		{"v1", BumpMinor, "v1"},
+		{"1.0", BumpMajor, "1.0"},
+	}
+	for _, c := range cases {
+		if got := SuggestVersion(c.version, c.bump); got != c.want {
+			t.Errorf("SuggestVersion(%s, %s) = %s, want %s", c.version, c.bump, got, c.want)
+		}
+	}
+}
+
+func TestCheckCompat_Aliases(t *testing.T) {
+	published := domain.Taxonomy{
+		ApiVersion: "v1",
+		SegL1s:     map[string]domain.Seg{"stg": newTestSegWithLabels("stg", []string{label("sensitivity", "low")})},
+		SegsL2s:    map[string]domain.Seg{"app": newDiffL2("app", []string{"stg"}, label("sensitivity", "low"))},
+	}
+	staging := newTestSegWithLabels("staging", []string{label("sensitivity", "low")})
+	staging.Aliases = []string{"stg"}
+	application := newDiffL2("application", []string{"staging"}, label("sensitivity", "low"))
+	application.Aliases = []string{"app"}
+	current := domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"staging": staging},
+		SegsL2s: map[string]domain.Seg{"application": application},
+	}
+
+	report := CheckCompat(published, current, configdomain.CompatConfig{})
+	if report.Failed() {
+		t.Fatalf("Expected aliased renames to pass, got %v", report.Issues)
+	}
+	if len(report.Issues) != 2 || report.Issues[0].Reason != "alias of staging" || report.Issues[1].Reason != "alias of application" {
+		t.Errorf("Expected allowed removals of both aliased IDs, got %v", report.Issues)
+	}
+	if report.SuggestedVersion != "v2" {
+		t.Errorf("Expected aliased removals to still suggest v2, got %s", report.SuggestedVersion)
+	}
+}
@@ -0,0 +1,197 @@
+package application
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+const (
+	FindingUnknownL1              = "unknown-l1"
+	FindingUnknownL2              = "unknown-l2"
+	FindingMissingL1              = "missing-l1"
+	FindingInvalidParent          = "invalid-parent"
+	FindingClassificationMismatch = "classification-mismatch"
+	FindingResourceTypeNotAllowed = "resource-type-not-allowed"
+	FindingDeprecatedAlias        = "deprecated-alias"
+)
+
+// ResourceFinding is a resource whose tags don't conform to the taxonomy.
+// Deprecated findings are warnings, such as tags using a segment alias, and don't fail checks.
+type ResourceFinding struct {
+	ResourceID string `json:"resource_id"`
+	Kind       string `json:"kind"`
+	Message    string `json:"message"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+func (f ResourceFinding) String() string {
+	return fmt.Sprintf("%s [%s]: %s", f.ResourceID, f.Kind, f.Message)
+}
+
+// BlockingFindings returns the findings that aren't deprecation warnings.
+func BlockingFindings(findings []ResourceFinding) []ResourceFinding {
+	var blocking []ResourceFinding
+	for _, f := range findings {
+		if !f.Deprecated {
+			blocking = append(blocking, f)
+		}
+	}
+	return blocking
+}
+
+// ResourceResult groups the findings of one resource. Checked is false for resources
+// without segment tags, which the taxonomy doesn't manage.
+type ResourceResult struct {
+	ResourceID string            `json:"resource_id"`
+	Type       string            `json:"type,omitempty"`
+	Checked    bool              `json:"checked"`
+	Findings   []ResourceFinding `json:"findings,omitempty"`
+}
+
+// Failed reports whether the resource has any finding other than deprecation warnings.
+func (r ResourceResult) Failed() bool {
+	return len(BlockingFindings(r.Findings)) > 0
+}
+
+// ResourceResults returns a result per resource, in input order, with its findings.
+func ResourceResults(resources []domain.Resource, findings []ResourceFinding, cfg configdomain.ResourcesConfig) []ResourceResult {
+	byID := make(map[string][]ResourceFinding)
+	for _, f := range findings {
+		byID[f.ResourceID] = append(byID[f.ResourceID], f)
+	}
+	results := make([]ResourceResult, 0, len(resources))
+	for _, res := range resources {
+		results = append(results, ResourceResult{
+			ResourceID: res.ID,
+			Type:       res.Type,
+			Checked:    res.Tags[cfg.L1TagKey] != "" || res.Tags[cfg.L2TagKey] != "",
+			Findings:   byID[res.ID],
+		})
+	}
+	return results
+}
+
+// CheckResources compares resource segment and classification tags with the resolved taxonomy.
+// Resources without segment tags are not managed by the taxonomy and are ignored. An L2 tag
+// without an L1 tag is resolved to the segment's parent when it has exactly one. Tags using a
+// segment alias resolve to the canonical segment with a deprecated finding.
+// Classification tags are only compared when present. When compliance is set, typed resources
+// in segments in scope for a requirement must be one of its allowed resource types.
+// Findings are sorted by resource ID.
+func CheckResources(txy domain.Taxonomy, resources []domain.Resource, cfg configdomain.ResourcesConfig, compliance *plugins.CompliancePlugin) []ResourceFinding {
+	var findings []ResourceFinding
+	for _, res := range resources {
+		findings = append(findings, checkResource(txy, res, cfg, compliance)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].ResourceID < findings[j].ResourceID })
+	return findings
+}
+
+func checkResource(txy domain.Taxonomy, res domain.Resource, cfg configdomain.ResourcesConfig, compliance *plugins.CompliancePlugin) []ResourceFinding {
+	finding := func(kind, format string, args ...any) []ResourceFinding {
+		return []ResourceFinding{{ResourceID: res.ID, Kind: kind, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	l1ID, l2ID := res.Tags[cfg.L1TagKey], res.Tags[cfg.L2TagKey]
+	if l1ID == "" && l2ID == "" {
+		return nil
+	}
+
+	var findings []ResourceFinding
+	deprecatedAlias := func(tagKey, alias, id string) {
+		findings = append(findings, ResourceFinding{ResourceID: res.ID, Kind: FindingDeprecatedAlias, Deprecated: true,
+			Message: fmt.Sprintf("%s %q is a deprecated alias, use %q", tagKey, alias, id)})
+	}
+
+	var seg domain.Seg
+	if l2ID != "" {
+		l2, aliased, ok := txy.ResolveL2(l2ID)
+		if !ok {
+			return finding(FindingUnknownL2, "%s %q is not a known L2 segment", cfg.L2TagKey, l2ID)
+		}
+		if aliased {
+			deprecatedAlias(cfg.L2TagKey, l2ID, l2.ID)
+			l2ID = l2.ID
+		}
+		if l1ID == "" {
+			if len(l2.L1Parents) != 1 {
+				return append(findings, finding(FindingMissingL1, "L2 %s has %d L1 parents, %s is required", l2ID, len(l2.L1Parents), cfg.L1TagKey)...)
+			}
+			l1ID = l2.L1Parents[0]
+		}
+		seg = l2
+	}
+	l1, aliased, ok := txy.ResolveL1(l1ID)
+	if !ok {
+		return append(findings, finding(FindingUnknownL1, "%s %q is not a known L1 segment", cfg.L1TagKey, l1ID)...)
+	}
+	if aliased {
+		deprecatedAlias(cfg.L1TagKey, l1ID, l1.ID)
+		l1ID = l1.ID
+	}
+	if l2ID == "" {
+		seg = l1
+	} else if !hasParent(seg, l1ID) {
+		return append(findings, finding(FindingInvalidParent, "L2 %s is not a child of L1 %s", l2ID, l1ID)...)
+	}
+
+	keys := make([]string, 0, len(cfg.ClassificationTags))
+	for key := range cfg.ClassificationTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ns := plugins.NsPrefix + "classifications"
+	for _, key := range keys {
+		tagKey := cfg.ClassificationTags[key]
+		tagged, ok := res.Tags[tagKey]
+		if !ok {
+			continue
+		}
+		expected, _ := seg.GetNamespacedValue(l1ID, ns, key)
+		if tagged != expected {
+			findings = append(findings, finding(FindingClassificationMismatch,
+				"%s is %q, taxonomy resolves %s to %q for %s", tagKey, tagged, key, expected, segmentName(l1ID, l2ID))...)
+		}
+	}
+
+	if compliance != nil && res.Type != "" {
+		reqIDs := make([]string, 0, len(compliance.Config.Definitions))
+		for reqID := range compliance.Config.Definitions {
+			reqIDs = append(reqIDs, reqID)
+		}
+		sort.Strings(reqIDs)
+		for _, reqID := range reqIDs {
+			allowed := compliance.Config.Definitions[reqID].AllowedResourceTypes
+			if len(allowed) == 0 || slices.Contains(allowed, res.Type) {
+				continue
+			}
+			if scope, _ := seg.GetNamespacedValue(l1ID, compliance.Namespace, reqID); scope == plugins.ScopeInScope {
+				findings = append(findings, finding(FindingResourceTypeNotAllowed,
+					"%s is in scope for %s which does not allow resource type %s", segmentName(l1ID, l2ID), reqID, res.Type)...)
+			}
+		}
+	}
+	return findings
+}
+
+func hasParent(seg domain.Seg, l1ID string) bool {
+	for _, parent := range seg.L1Parents {
+		if parent == l1ID {
+			return true
+		}
+	}
+	return false
+}
+
+func segmentName(l1ID, l2ID string) string {
+	if l2ID == "" {
+		return "L1 " + l1ID
+	}
+	return fmt.Sprintf("L2 %s in L1 %s", l2ID, l1ID)
+}
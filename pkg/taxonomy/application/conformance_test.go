@@ -0,0 +1,140 @@
+package application
+
+import (
+	"testing"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+func TestCheckResources(t *testing.T) {
+	sensitivity := plugins.NsPrefix + "classifications/sensitivity"
+	newSeg := func(id, value string, parents ...string) domain.Seg {
+		seg := domain.Seg{ID: id, L1Parents: parents, Labels: []string{sensitivity + ":" + value}}
+		_ = seg.ParseLabels()
+		return seg
+	}
+	app := newSeg("app", "B", "prod", "stg")
+	override := domain.L1Overrides{Labels: []string{sensitivity + ":C"}}
+	_ = override.ParseLabels()
+	app.L1Overrides = map[string]domain.L1Overrides{"stg": override}
+
+	txy := domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"prod": newSeg("prod", "A"), "stg": newSeg("stg", "C"), "dev": newSeg("dev", "D")},
+		SegsL2s: map[string]domain.Seg{"app": app, "db": newSeg("db", "A", "prod")},
+	}
+	cfg := configdomain.DefaultConfig().Resources
+	cfg.ClassificationTags = map[string]string{"sensitivity": "data-sensitivity"}
+
+	resource := func(id string, tags ...string) domain.Resource {
+		res := domain.Resource{ID: id, Tags: map[string]string{}}
+		keys := []string{cfg.L1TagKey, cfg.L2TagKey, "data-sensitivity"}
+		for i, value := range tags {
+			if value != "" {
+				res.Tags[keys[i]] = value
+			}
+		}
+		return res
+	}
+
+	tests := []struct {
+		name     string
+		resource domain.Resource
+		expected string
+	}{
+		{"Conforming L2 resource", resource("r", "prod", "app", "B"), ""},
+		{"Override value for parent", resource("r", "stg", "app", "C"), ""},
+		{"Untagged resource ignored", resource("r"), ""},
+		{"L1 inferred from single parent", resource("r", "", "db"), ""},
+		{"Unknown L1", resource("r", "qa"), FindingUnknownL1},
+		{"Unknown L2", resource("r", "prod", "cache"), FindingUnknownL2},
+		{"Missing L1 for multi-parent L2", resource("r", "", "app"), FindingMissingL1},
+		{"Invalid parent", resource("r", "dev", "app"), FindingInvalidParent},
+		{"Classification differs", resource("r", "prod", "app", "C"), FindingClassificationMismatch},
+		{"L1 classification differs", resource("r", "dev", "", "A"), FindingClassificationMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := CheckResources(txy, []domain.Resource{tt.resource}, cfg, nil)
+
+			if tt.expected == "" {
+				if len(findings) > 0 {
+					t.Errorf("Expected no findings, got %v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].Kind != tt.expected {
+				t.Errorf("Expected %s finding, got %v", tt.expected, findings)
+			}
+		})
+	}
+}
+
+func TestCheckResources_AllowedResourceTypes(t *testing.T) {
+	pciDss := plugins.NsPrefix + "compliance/pci-dss"
+	prod := domain.Seg{ID: "prod", Labels: []string{pciDss + ":" + plugins.ScopeInScope}}
+	_ = prod.ParseLabels()
+	stg := domain.Seg{ID: "stg", Labels: []string{pciDss + ":" + plugins.ScopeOutOfScope}}
+	_ = stg.ParseLabels()
+	txy := domain.Taxonomy{SegL1s: map[string]domain.Seg{"prod": prod, "stg": stg}}
+
+	compliance := plugins.NewCompliancePlugin(&plugins.ComplianceConfig{Definitions: map[string]plugins.ComplianceDefinition{
+		"pci-dss": {AllowedResourceTypes: []string{"aws_instance"}},
+		"soc2":    {},
+	}}, plugins.NsPrefix)
+	cfg := configdomain.DefaultConfig().Resources
+	resources := []domain.Resource{
+		{ID: "allowed", Type: "aws_instance", Tags: map[string]string{cfg.L1TagKey: "prod"}},
+		{ID: "denied", Type: "aws_s3_bucket", Tags: map[string]string{cfg.L1TagKey: "prod"}},
+		{ID: "out-of-scope", Type: "aws_s3_bucket", Tags: map[string]string{cfg.L1TagKey: "stg"}},
+		{ID: "untagged", Type: "aws_s3_bucket"},
+	}
+
+	findings := CheckResources(txy, resources, cfg, compliance)
+
+	if len(findings) != 1 || findings[0].ResourceID != "denied" || findings[0].Kind != FindingResourceTypeNotAllowed {
+		t.Fatalf("Expected only denied to fail, got %v", findings)
+	}
+
+	results := ResourceResults(resources, findings, cfg)
+	if len(results) != 4 || !results[0].Checked || len(results[1].Findings) != 1 || results[3].Checked {
+		t.Errorf("Unexpected per-resource results: %+v", results)
+	}
+}
+
+func TestCheckResources_Aliases(t *testing.T) {
+	sensitivity := plugins.NsPrefix + "classifications/sensitivity"
+	prod := domain.Seg{ID: "prod", Aliases: []string{"production"}, Labels: []string{sensitivity + ":A"}}
+	_ = prod.ParseLabels()
+	app := domain.Seg{ID: "app", Aliases: []string{"application"}, L1Parents: []string{"prod"}, Labels: []string{sensitivity + ":B"}}
+	_ = app.ParseLabels()
+	txy := domain.Taxonomy{SegL1s: map[string]domain.Seg{"prod": prod}, SegsL2s: map[string]domain.Seg{"app": app}}
+	cfg := configdomain.DefaultConfig().Resources
+	cfg.ClassificationTags = map[string]string{"sensitivity": "data-sensitivity"}
+
+	resources := []domain.Resource{
+		{ID: "aliased", Tags: map[string]string{cfg.L1TagKey: "production", cfg.L2TagKey: "application", "data-sensitivity": "B"}},
+		{ID: "mismatch", Tags: map[string]string{cfg.L1TagKey: "production", "data-sensitivity": "B"}},
+	}
+	findings := CheckResources(txy, resources, cfg, nil)
+
+	kinds := map[string][]string{}
+	for _, f := range findings {
+		kinds[f.ResourceID] = append(kinds[f.ResourceID], f.Kind)
+		if f.Kind == FindingDeprecatedAlias && !f.Deprecated {
+			t.Errorf("Expected alias finding to be a deprecation warning: %v", f)
+		}
+	}
+	if len(kinds["aliased"]) != 2 || kinds["aliased"][0] != FindingDeprecatedAlias || kinds["aliased"][1] != FindingDeprecatedAlias {
+		t.Errorf("Expected two deprecated-alias findings for aliased, got %v", kinds["aliased"])
+	}
+	if len(kinds["mismatch"]) != 2 || kinds["mismatch"][1] != FindingClassificationMismatch {
+		t.Errorf("Expected alias then classification findings for mismatch, got %v", kinds["mismatch"])
+	}
+
+	results := ResourceResults(resources, findings, cfg)
+	if results[0].Failed() || !results[1].Failed() {
+		t.Errorf("Expected only mismatch to fail, got %+v", results)
+	}
+}
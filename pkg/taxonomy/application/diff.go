@@ -0,0 +1,303 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+const (
+	ChangeSegmentAdded          = "segment-added"
+	ChangeSegmentRemoved        = "segment-removed"
+	ChangeSegmentRenamed        = "segment-renamed"
+	ChangeNameChanged           = "name-changed"
+	ChangeParentAdded           = "parent-added"
+	ChangeParentRemoved         = "parent-removed"
+	ChangeLabelAdded            = "label-added"
+	ChangeLabelRemoved          = "label-removed"
+	ChangeLabelChanged          = "label-changed"
+	ChangeClassificationRaised  = "classification-raised"
+	ChangeClassificationLowered = "classification-lowered"
+	ChangeScopeAdded            = "scope-added"
+	ChangeScopeRemoved          = "scope-removed"
+)
+
+const (
+	DiffFormatText     = "text"
+	DiffFormatJSON     = "json"
+	DiffFormatMarkdown = "markdown"
+)
+
+// TaxonomyChange is one semantic difference between two taxonomies. Segment names the L1,
+// or the L2 and the L1 parent the effective labels were resolved for.
+type TaxonomyChange struct {
+	Kind     string `json:"kind"`
+	Segment  string `json:"segment"`
+	Key      string `json:"key,omitempty"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+	Breaking bool   `json:"breaking"`
+	Summary  string `json:"summary"`
+}
+
+// DiffTaxonomies compares two resolved taxonomies. Removed or renamed segments and removed
+// parent relationships are breaking, unless the new segment keeps the old ID as an alias. Labels are compared after inheritance for every L1 and
+// every L2/parent pair present in both; pluginsList classifies changes to plugin labels.
+func DiffTaxonomies(base, head domain.Taxonomy, pluginsList plugins.Plugins) []TaxonomyChange {
+	d := differ{plugins: pluginsList}
+	for level := 1; level <= max(base.Depth(), head.Depth()); level++ {
+		d.diffLevel(fmt.Sprintf("L%d", level), base.Segs(level), head.Segs(level))
+	}
+	return d.changes
+}
+
+type differ struct {
+	plugins plugins.Plugins
+	changes []TaxonomyChange
+}
+
+func (d *differ) add(change TaxonomyChange) {
+	d.changes = append(d.changes, change)
+}
+
+func (d *differ) diffLevel(level string, base, head map[string]domain.Seg) {
+	var removed, added []string
+	for _, id := range sortedKeys(base) {
+		if _, ok := head[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	for _, id := range sortedKeys(head) {
+		if _, ok := base[id]; !ok {
+			added = append(added, id)
+		}
+	}
+
+	renamed := matchRenames(removed, added, base, head)
+	for _, id := range removed {
+		if newID, ok := renamed[id]; ok {
+			if slices.Contains(head[newID].Aliases, id) {
+				d.add(TaxonomyChange{Kind: ChangeSegmentRenamed, Segment: level + " " + newID, Before: id, After: newID,
+					Summary: fmt.Sprintf("%s %s renamed to %s, old ID kept as alias", level, id, newID)})
+				continue
+			}
+			d.add(TaxonomyChange{Kind: ChangeSegmentRenamed, Segment: level + " " + newID, Before: id, After: newID, Breaking: true,
+				Summary: fmt.Sprintf("%s %s renamed to %s (breaking)", level, id, newID)})
+			continue
+		}
+		d.add(TaxonomyChange{Kind: ChangeSegmentRemoved, Segment: level + " " + id, Breaking: true,
+			Summary: fmt.Sprintf("%s %s deleted (breaking)", level, id)})
+	}
+	renamedTo := make(map[string]bool, len(renamed))
+	for _, newID := range renamed {
+		renamedTo[newID] = true
+	}
+	for _, id := range added {
+		if !renamedTo[id] {
+			d.add(TaxonomyChange{Kind: ChangeSegmentAdded, Segment: level + " " + id, Summary: fmt.Sprintf("%s %s added", level, id)})
+		}
+	}
+
+	for _, id := range sortedKeys(base) {
+		if headSeg, ok := head[id]; ok {
+			d.diffSegment(level, base[id], headSeg)
+		}
+	}
+}
+
+// matchRenames pairs removed IDs with added IDs that alias them, failing that with the same name,
+// or failing that the same labels and parents.
+func matchRenames(removed, added []string, base, head map[string]domain.Seg) map[string]string {
+	renamed := map[string]string{}
+	used := map[string]bool{}
+	sameLabels := func(a, b domain.Seg) bool {
+		return slices.Equal(sortedCopy(a.Labels), sortedCopy(b.Labels)) && slices.Equal(sortedCopy(a.L1Parents), sortedCopy(b.L1Parents))
+	}
+	for _, match := range []func(a, b domain.Seg) bool{
+		func(a, b domain.Seg) bool { return slices.Contains(b.Aliases, a.ID) },
+		func(a, b domain.Seg) bool { return a.Name == b.Name },
+		sameLabels,
+	} {
+		for _, oldID := range removed {
+			if _, done := renamed[oldID]; done {
+				continue
+			}
+			for _, newID := range added {
+				if !used[newID] && match(base[oldID], head[newID]) {
+					renamed[oldID] = newID
+					used[newID] = true
+					break
+				}
+			}
+		}
+	}
+	return renamed
+}
+
+func (d *differ) diffSegment(level string, base, head domain.Seg) {
+	name := level + " " + head.ID
+	if base.Name != head.Name {
+		d.add(TaxonomyChange{Kind: ChangeNameChanged, Segment: name, Key: "name", Before: base.Name, After: head.Name,
+			Summary: fmt.Sprintf("%s name changed from %q to %q", name, base.Name, head.Name)})
+	}
+
+	if level == "L1" {
+		d.diffLabels(name, base.EffectiveLabels(""), head.EffectiveLabels(""))
+		return
+	}
+	above := parentLevel(level)
+	for _, parent := range sortedCopy(base.L1Parents) {
+		if !slices.Contains(head.L1Parents, parent) {
+			d.add(TaxonomyChange{Kind: ChangeParentRemoved, Segment: name, Before: parent, Breaking: true,
+				Summary: fmt.Sprintf("%s no longer in %s %s (breaking)", name, above, parent)})
+		}
+	}
+	for _, parent := range sortedCopy(head.L1Parents) {
+		if !slices.Contains(base.L1Parents, parent) {
+			d.add(TaxonomyChange{Kind: ChangeParentAdded, Segment: name, After: parent,
+				Summary: fmt.Sprintf("%s added to %s %s", name, above, parent)})
+		}
+	}
+	for _, parent := range sortedCopy(head.L1Parents) {
+		if slices.Contains(base.L1Parents, parent) {
+			d.diffLabels(fmt.Sprintf("%s in %s %s", name, above, parent), base.EffectiveLabels(parent), head.EffectiveLabels(parent))
+		}
+	}
+}
+
+// parentLevel returns the name of the level above level, e.g. L1 for L2.
+func parentLevel(level string) string {
+	n, _ := strconv.Atoi(strings.TrimPrefix(level, "L"))
+	return fmt.Sprintf("L%d", n-1)
+}
+
+func (d *differ) diffLabels(segment string, base, head map[string]string) {
+	keys := make(map[string]bool, len(base)+len(head))
+	for k := range base {
+		keys[k] = true
+	}
+	for k := range head {
+		keys[k] = true
+	}
+	for _, label := range sortedKeys(keys) {
+		before, inBase := base[label]
+		after, inHead := head[label]
+		if inBase && inHead && before == after {
+			continue
+		}
+		change := TaxonomyChange{Segment: segment, Key: label, Before: before, After: after}
+		d.classifyLabelChange(&change, inBase, inHead)
+		d.add(change)
+	}
+}
+
+// classifyLabelChange sets the kind and summary of a label change, using the owning plugin's
+// strictness ranking for classification and compliance labels.
+func (d *differ) classifyLabelChange(change *TaxonomyChange, inBase, inHead bool) {
+	ns, key, _ := strings.Cut(change.Key, "/")
+	for _, plugin := range d.plugins {
+		if plugin.GetNamespace() != ns {
+			continue
+		}
+		if _, ok := plugin.(*plugins.CompliancePlugin); ok {
+			wasInScope, isInScope := change.Before == plugins.ScopeInScope, change.After == plugins.ScopeInScope
+			if wasInScope != isInScope {
+				change.Kind, change.Summary = ChangeScopeAdded, fmt.Sprintf("%s %s scope added", change.Segment, key)
+				if wasInScope {
+					change.Kind, change.Summary = ChangeScopeRemoved, fmt.Sprintf("%s %s scope removed", change.Segment, key)
+				}
+				return
+			}
+		}
+		if ranker, ok := plugin.(plugins.InheritanceConfigurer); ok && inBase && inHead {
+			beforeRank, okBefore := ranker.StrictnessRank(key, change.Before)
+			afterRank, okAfter := ranker.StrictnessRank(key, change.After)
+			if okBefore && okAfter && beforeRank != afterRank {
+				change.Kind = ChangeClassificationRaised
+				direction := "raised"
+				if afterRank > beforeRank {
+					change.Kind, direction = ChangeClassificationLowered, "lowered"
+				}
+				change.Summary = fmt.Sprintf("%s %s %s from %s to %s", change.Segment, key, direction, change.Before, change.After)
+				return
+			}
+		}
+	}
+	switch {
+	case !inBase:
+		change.Kind, change.Summary = ChangeLabelAdded, fmt.Sprintf("%s label %s added: %s", change.Segment, change.Key, change.After)
+	case !inHead:
+		change.Kind, change.Summary = ChangeLabelRemoved, fmt.Sprintf("%s label %s removed", change.Segment, change.Key)
+	default:
+		change.Kind, change.Summary = ChangeLabelChanged, fmt.Sprintf("%s label %s changed from %s to %s", change.Segment, change.Key, change.Before, change.After)
+	}
+}
+
+// RenderDiff formats changes as text, JSON or markdown for PR comments.
+func RenderDiff(changes []TaxonomyChange, format string) (string, error) {
+	breaking := 0
+	for _, c := range changes {
+		if c.Breaking {
+			breaking++
+		}
+	}
+	summary := fmt.Sprintf("%d change(s), %d breaking", len(changes), breaking)
+
+	var b strings.Builder
+	switch format {
+	case DiffFormatText, "":
+		for _, c := range changes {
+			b.WriteString(c.Summary + "\n")
+		}
+		b.WriteString(summary + "\n")
+	case DiffFormatJSON:
+		if changes == nil {
+			changes = []TaxonomyChange{}
+		}
+		data, err := json.MarshalIndent(struct {
+			Breaking int              `json:"breaking"`
+			Changes  []TaxonomyChange `json:"changes"`
+		}{breaking, changes}, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		b.Write(data)
+		b.WriteString("\n")
+	case DiffFormatMarkdown:
+		b.WriteString("### Taxonomy changes\n\n" + summary + "\n")
+		if len(changes) > 0 {
+			b.WriteString("\n| Change | Segment | Details |\n|---|---|---|\n")
+			for _, c := range changes {
+				kind := c.Kind
+				if c.Breaking {
+					kind = "**" + kind + " (breaking)**"
+				}
+				fmt.Fprintf(&b, "| %s | %s | %s |\n", kind, c.Segment, strings.ReplaceAll(c.Summary, "|", "\\|"))
+			}
+		}
+	default:
+		return "", fmt.Errorf("unsupported diff format %q, expected text, json or markdown", format)
+	}
+	return b.String(), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedCopy(values []string) []string {
+	out := slices.Clone(values)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,182 @@
+package application
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+func newDiffPlugins() plugins.Plugins {
+	p := newTestPlugins(true)
+	p["compliance"] = plugins.NewCompliancePlugin(&plugins.ComplianceConfig{
+		Definitions: map[string]plugins.ComplianceDefinition{"pci-dss": {}},
+	}, plugins.NsPrefix)
+	return p
+}
+
+func newDiffL2(id string, parents []string, labels ...string) domain.Seg {
+	seg := newTestSegWithLabels(id, labels)
+	seg.L1Parents = parents
+	return seg
+}
+
+func findChange(changes []TaxonomyChange, kind, segment string) *TaxonomyChange {
+	for i, c := range changes {
+		if c.Kind == kind && c.Segment == segment {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestDiffTaxonomies(t *testing.T) {
+	pci := plugins.NsPrefix + "compliance/pci-dss"
+	base := domain.Taxonomy{
+		SegL1s: map[string]domain.Seg{
+			"prod": newTestSegWithLabels("prod", []string{label("sensitivity", "low"), pci + ":" + plugins.ScopeInScope}),
+			"stg":  newTestSegWithLabels("stg", []string{label("sensitivity", "low")}),
+			"dev":  newTestSegWithLabels("dev", []string{label("sensitivity", "low")}),
+		},
+		SegsL2s: map[string]domain.Seg{
+			"app": newDiffL2("app", []string{"prod", "stg"}, label("sensitivity", "low")),
+		},
+	}
+	head := domain.Taxonomy{
+		SegL1s: map[string]domain.Seg{
+			"prod":       newTestSegWithLabels("prod", []string{label("sensitivity", "high"), testNs + "/owner:team"}),
+			"staging":    newTestSegWithLabels("stg", []string{label("sensitivity", "low")}),
+			"production": newTestSegWithLabels("production", nil),
+		},
+		SegsL2s: map[string]domain.Seg{
+			"app": newDiffL2("app", []string{"prod", "production"}, label("sensitivity", "low")),
+		},
+	}
+	staging := head.SegL1s["staging"]
+	staging.ID = "staging"
+	head.SegL1s["staging"] = staging
+
+	changes := DiffTaxonomies(base, head, newDiffPlugins())
+
+	tests := []struct {
+		kind     string
+		segment  string
+		breaking bool
+	}{
+		{ChangeSegmentRenamed, "L1 staging", true},
+		{ChangeSegmentRemoved, "L1 dev", true},
+		{ChangeSegmentAdded, "L1 production", false},
+		{ChangeClassificationRaised, "L1 prod", false},
+		{ChangeScopeRemoved, "L1 prod", false},
+		{ChangeLabelAdded, "L1 prod", false},
+		{ChangeParentRemoved, "L2 app", true},
+		{ChangeParentAdded, "L2 app", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind+" "+tt.segment, func(t *testing.T) {
+			change := findChange(changes, tt.kind, tt.segment)
+			if change == nil {
+				t.Fatalf("Expected %s for %s in %+v", tt.kind, tt.segment, changes)
+			}
+			if change.Breaking != tt.breaking {
+				t.Errorf("Expected breaking=%v, got %+v", tt.breaking, change)
+			}
+		})
+	}
+
+	if len(changes) != len(tests) {
+		t.Errorf("Expected %d changes, got %d: %+v", len(tests), len(changes), changes)
+	}
+	if c := findChange(changes, ChangeClassificationRaised, "L1 prod"); c != nil && c.Summary != "L1 prod sensitivity raised from low to high" {
+		t.Errorf("Unexpected summary: %s", c.Summary)
+	}
+}
+
+func TestDiffTaxonomies_EffectiveLabelsPerParent(t *testing.T) {
+	app := newDiffL2("app", []string{"prod", "stg"}, label("sensitivity", "low"))
+	base := domain.Taxonomy{SegsL2s: map[string]domain.Seg{"app": app}}
+
+	changed := newDiffL2("app", []string{"prod", "stg"}, label("sensitivity", "low"))
+	override := domain.L1Overrides{Labels: []string{label("sensitivity", "high")}}
+	_ = override.ParseLabels()
+	changed.L1Overrides = map[string]domain.L1Overrides{"prod": override}
+	head := domain.Taxonomy{SegsL2s: map[string]domain.Seg{"app": changed}}
+
+	changes := DiffTaxonomies(base, head, newDiffPlugins())
+
+	if len(changes) != 1 || changes[0].Segment != "L2 app in L1 prod" || changes[0].Kind != ChangeClassificationRaised {
+		t.Errorf("Expected sensitivity raised for app in prod only, got %+v", changes)
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	changes := []TaxonomyChange{
+		{Kind: ChangeSegmentRemoved, Segment: "L1 dev", Breaking: true, Summary: "L1 dev deleted (breaking)"},
+		{Kind: ChangeSegmentAdded, Segment: "L1 qa", Summary: "L1 qa added"},
+	}
+
+	text, err := RenderDiff(changes, DiffFormatText)
+	if err != nil || !strings.Contains(text, "L1 dev deleted (breaking)\n") || !strings.Contains(text, "2 change(s), 1 breaking") {
+		t.Errorf("Unexpected text output: %q, %v", text, err)
+	}
+
+	md, err := RenderDiff(changes, DiffFormatMarkdown)
+	if err != nil || !strings.Contains(md, "| **segment-removed (breaking)** | L1 dev |") {
+		t.Errorf("Unexpected markdown output: %q, %v", md, err)
+	}
+
+	out, err := RenderDiff(nil, DiffFormatJSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded struct {
+		Breaking int              `json:"breaking"`
+		Changes  []TaxonomyChange `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil || decoded.Changes == nil {
+		t.Errorf("Expected JSON with empty changes list, got %q, %v", out, err)
+	}
+
+	if _, err := RenderDiff(changes, "html"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestDiffTaxonomies_AliasedRename(t *testing.T) {
+	base := domain.Taxonomy{SegL1s: map[string]domain.Seg{"stg": newTestSegWithLabels("stg", []string{label("sensitivity", "low")})}}
+	staging := newTestSegWithLabels("staging", []string{label("sensitivity", "high")})
+	staging.Name = "Staging"
+	staging.Aliases = []string{"stg"}
+	head := domain.Taxonomy{SegL1s: map[string]domain.Seg{"staging": staging}}
+
+	changes := DiffTaxonomies(base, head, newDiffPlugins())
+	rename := findChange(changes, ChangeSegmentRenamed, "L1 staging")
+	if rename == nil || rename.Breaking {
+		t.Errorf("Expected non-breaking rename to staging, got %+v", changes)
+	}
+}
+
+func TestDiffTaxonomies_Levels(t *testing.T) {
+	base := domain.Taxonomy{Levels: map[string]map[string]domain.Seg{
+		"3": {"web": newDiffL2("web", []string{"app", "api"}), "cron": newDiffL2("cron", []string{"app"})},
+	}}
+	head := domain.Taxonomy{Levels: map[string]map[string]domain.Seg{
+		"3": {"web": newDiffL2("web", []string{"app"})},
+	}}
+
+	changes := DiffTaxonomies(base, head, newDiffPlugins())
+	if c := findChange(changes, ChangeSegmentRemoved, "L3 cron"); c == nil || !c.Breaking {
+		t.Errorf("Expected breaking removal of L3 cron, got %+v", changes)
+	}
+	if c := findChange(changes, ChangeParentRemoved, "L3 web"); c == nil || c.Summary != "L3 web no longer in L2 api (breaking)" {
+		t.Errorf("Expected L3 web to leave L2 api, got %+v", changes)
+	}
+
+	report := CheckCompat(base, head, configdomain.CompatConfig{})
+	if !report.Failed() || findCompatIssue(report.Issues, CompatParentRemoved, "L3 web") == nil {
+		t.Errorf("Expected compat to report the L3 parent removal, got %v", report.Issues)
+	}
+}
@@ -0,0 +1,32 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+// LoadFlows loads flows from the repository and returns them indexed by ID.
+// Flow IDs must be unique across all flow documents.
+func LoadFlows(repository FlowRepository) (map[string]domain.Flow, error) {
+	flowList, err := repository.LoadFlows()
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make(map[string]domain.Flow, len(flowList))
+	duplicates := 0
+	for _, flow := range flowList {
+		if _, exists := flows[flow.ID]; exists {
+			o11y.Log.Printf("Flow ID %s is not unique", flow.ID)
+			duplicates++
+			continue
+		}
+		flows[flow.ID] = flow
+	}
+	if duplicates > 0 {
+		return nil, fmt.Errorf("flow validation failed: %d duplicate ID(s)", duplicates)
+	}
+	return flows, nil
+}
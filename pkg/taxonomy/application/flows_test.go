@@ -0,0 +1,36 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+type stubFlowRepository struct {
+	flows []domain.Flow
+}
+
+func (r stubFlowRepository) LoadFlows() ([]domain.Flow, error) {
+	return r.flows, nil
+}
+
+func TestLoadFlows(t *testing.T) {
+	t.Run("Indexes flows by ID", func(t *testing.T) {
+		flows, err := LoadFlows(stubFlowRepository{flows: []domain.Flow{{ID: "a"}, {ID: "b"}}})
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(flows) != 2 || flows["b"].ID != "b" {
+			t.Errorf("Expected flows a and b, got %v", flows)
+		}
+	})
+
+	t.Run("Rejects duplicate IDs", func(t *testing.T) {
+		_, err := LoadFlows(stubFlowRepository{flows: []domain.Flow{{ID: "a"}, {ID: "a"}}})
+
+		if err == nil {
+			t.Error("Expected duplicate ID error")
+		}
+	})
+}
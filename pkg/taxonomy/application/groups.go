@@ -0,0 +1,112 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+// LoadGroups loads groups from the repository and returns them indexed by ID.
+// Group IDs must be unique across all group documents.
+func LoadGroups(repository GroupRepository) (map[string]domain.Group, error) {
+	groupList, err := repository.LoadGroups()
+	if err != nil {
+		return nil, err
+	}
+	if len(groupList) == 0 {
+		return nil, nil
+	}
+
+	groups := make(map[string]domain.Group, len(groupList))
+	duplicates := 0
+	for _, group := range groupList {
+		if _, exists := groups[group.ID]; exists {
+			o11y.Log.Printf("Group ID %s is not unique", group.ID)
+			duplicates++
+			continue
+		}
+		groups[group.ID] = group
+	}
+	if duplicates > 0 {
+		return nil, fmt.Errorf("group validation failed: %d duplicate ID(s)", duplicates)
+	}
+	return groups, nil
+}
+
+// FilterGroups returns a copy of the taxonomy limited to the L1s in the given groups.
+// Lower level segments keep only parents that survive the filter and are dropped when none do;
+// flows are kept when both ends survive. Unknown group IDs are an error.
+func FilterGroups(txy domain.Taxonomy, groupIDs []string) (domain.Taxonomy, error) {
+	wanted := make(map[string]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		if _, exists := txy.Groups[id]; !exists {
+			return domain.Taxonomy{}, fmt.Errorf("group %s not found", id)
+		}
+		wanted[id] = true
+	}
+
+	result := domain.Taxonomy{
+		ApiVersion: txy.ApiVersion,
+		SegL1s:     make(map[string]domain.Seg),
+		SegsL2s:    make(map[string]domain.Seg),
+		Groups:     make(map[string]domain.Group),
+	}
+	for id := range wanted {
+		result.Groups[id] = txy.Groups[id]
+	}
+	for id, seg := range txy.SegL1s {
+		if wanted[seg.Group] {
+			result.SegL1s[id] = seg
+		}
+	}
+	result.SegsL2s = filterParents(txy.SegsL2s, result.SegL1s)
+	for level := 3; level <= txy.Depth(); level++ {
+		if result.Levels == nil {
+			result.Levels = make(map[string]map[string]domain.Seg)
+		}
+		result.Levels[strconv.Itoa(level)] = filterParents(txy.Segs(level), result.Segs(level-1))
+	}
+
+	for id, flow := range txy.Flows {
+		segs := result.FlowSegments(flow)
+		_, srcKept := segs[flow.Source]
+		_, dstKept := segs[flow.Destination]
+		if srcKept && dstKept {
+			if result.Flows == nil {
+				result.Flows = make(map[string]domain.Flow)
+			}
+			result.Flows[id] = flow
+		}
+	}
+	return result, nil
+}
+
+// filterParents keeps the segments with at least one parent in parents, trimming the others.
+func filterParents(segs map[string]domain.Seg, parents map[string]domain.Seg) map[string]domain.Seg {
+	result := make(map[string]domain.Seg)
+	for id, seg := range segs {
+		kept := []string{}
+		for _, parentID := range seg.L1Parents {
+			if _, exists := parents[parentID]; exists {
+				kept = append(kept, parentID)
+			}
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		if len(kept) != len(seg.L1Parents) {
+			overrides := make(map[string]domain.L1Overrides, len(kept))
+			for _, parentID := range kept {
+				if override, ok := seg.L1Overrides[parentID]; ok {
+					overrides[parentID] = override
+				}
+			}
+			seg.L1Parents = kept
+			seg.L1Overrides = overrides
+		}
+		result[id] = seg
+	}
+	return result
+}
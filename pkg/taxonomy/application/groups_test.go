@@ -0,0 +1,82 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+type stubGroupRepository struct {
+	groups []domain.Group
+}
+
+func (r stubGroupRepository) LoadGroups() ([]domain.Group, error) {
+	return r.groups, nil
+}
+
+func TestLoadGroups(t *testing.T) {
+	t.Run("Indexes groups by ID", func(t *testing.T) {
+		groups, err := LoadGroups(stubGroupRepository{groups: []domain.Group{{ID: "eu"}, {ID: "us"}}})
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(groups) != 2 || groups["us"].ID != "us" {
+			t.Errorf("Expected groups eu and us, got %v", groups)
+		}
+	})
+
+	t.Run("Rejects duplicate IDs", func(t *testing.T) {
+		_, err := LoadGroups(stubGroupRepository{groups: []domain.Group{{ID: "eu"}, {ID: "eu"}}})
+
+		if err == nil {
+			t.Error("Expected duplicate ID error")
+		}
+	})
+}
+
+func TestFilterGroups(t *testing.T) {
+	txy := domain.Taxonomy{
+		Groups: map[string]domain.Group{"eu": {ID: "eu"}, "us": {ID: "us"}},
+		SegL1s: map[string]domain.Seg{
+			"prod-eu": {ID: "prod-eu", Group: "eu"},
+			"prod-us": {ID: "prod-us", Group: "us"},
+		},
+		SegsL2s: map[string]domain.Seg{
+			"app": {ID: "app", L1Parents: []string{"prod-eu", "prod-us"}, L1Overrides: map[string]domain.L1Overrides{
+				"prod-eu": {}, "prod-us": {},
+			}},
+			"db": {ID: "db", L1Parents: []string{"prod-us"}},
+		},
+		Flows: map[string]domain.Flow{
+			"app-to-db": {ID: "app-to-db", Level: 2, Source: "app", Destination: "db"},
+		},
+	}
+
+	t.Run("Keeps segments under the selected groups", func(t *testing.T) {
+		result, err := FilterGroups(txy, []string{"eu"})
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.SegL1s) != 1 || len(result.SegsL2s) != 1 || len(result.Groups) != 1 {
+			t.Fatalf("Expected only prod-eu and app, got %v and %v", result.SegL1s, result.SegsL2s)
+		}
+		app := result.SegsL2s["app"]
+		if len(app.L1Parents) != 1 || len(app.L1Overrides) != 1 {
+			t.Errorf("Expected app trimmed to prod-eu, got %+v", app)
+		}
+		if len(result.Flows) != 0 {
+			t.Errorf("Expected flow to db to be dropped, got %v", result.Flows)
+		}
+		if len(txy.SegsL2s["app"].L1Parents) != 2 {
+			t.Error("Expected input taxonomy to be unchanged")
+		}
+	})
+
+	t.Run("Rejects unknown groups", func(t *testing.T) {
+		if _, err := FilterGroups(txy, []string{"apac"}); err == nil {
+			t.Error("Expected unknown group error")
+		}
+	})
+}
@@ -1,45 +1,106 @@
 package application
 
 import (
+	"fmt"
+
 	"github.com/kvql/bunsceal/pkg/domain"
 	"github.com/kvql/bunsceal/pkg/o11y"
 	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
 )
 
 // ApplyInheritance applies inheritance rules for taxonomy segments and validates cross-entity references.
+// Levels are resolved top down: each level's parent selectors and default overrides are expanded
+// against its parents' resolved labels, then its templates are applied and it inherits from them.
 // Pass nil for pluginsList to skip plugin label inheritance (backwards compatible).
 func ApplyInheritance(txy *domain.Taxonomy, pluginsList plugins.Plugins) error {
-	for _, seg := range txy.SegsL2s {
+	resolver, errs := newTemplateResolver(txy.Templates)
+	if len(errs) > 0 {
+		return logErrors(errs, "template resolution")
+	}
+	for level := 2; level <= txy.Depth(); level++ {
+		if errs := expandLevelParents(txy, level); len(errs) > 0 {
+			return logErrors(errs, "parent expansion")
+		}
+		if errs := resolver.applyLevel(txy, level); len(errs) > 0 {
+			return logErrors(errs, "template resolution")
+		}
+		if err := inheritLevel(txy.Segs(level-1), txy.Segs(level), pluginsList); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logErrors logs each error and returns a summary error for the failed step.
+func logErrors(errs []error, step string) error {
+	for _, err := range errs {
+		o11y.Log.Println(err)
+	}
+	return fmt.Errorf("%s failed with %d error(s)", step, len(errs))
+}
+
+// inheritLevel applies inheritance from the parent level to the segments of the level below it.
+func inheritLevel(parentSegs, segs map[string]domain.Seg, pluginsList plugins.Plugins) error {
+	for id, seg := range segs {
 		// Initialize L1Overrides map if nil (enables parent-without-override pattern)
 		if seg.L1Overrides == nil {
 			seg.L1Overrides = make(map[string]domain.L1Overrides)
+			segs[id] = seg
 		}
 
 		// REFACTORED: Iterate over L1Parents instead of L1Overrides keys
-		for _, l1ID := range seg.L1Parents {
+		parents := make([]domain.Seg, 0, len(seg.L1Parents))
+		for _, parentID := range seg.L1Parents {
 			// Get existing override or create empty struct for full inheritance
-			l1Override, exists := seg.L1Overrides[l1ID]
+			override, exists := seg.L1Overrides[parentID]
 			if !exists {
-				l1Override = domain.L1Overrides{}
+				override = domain.L1Overrides{}
 			}
 
 			// Write back override (creates new entry if didn't exist)
-			seg.L1Overrides[l1ID] = l1Override
-
-			// Plugin labels are inherited via plugin system
-			if pluginsList != nil {
-				errs := pluginsList.ApplyPluginInheritanceAndValidate(
-					txy.SegL1s[l1ID],
-					&seg,
-				)
-				for _, err := range errs {
-					o11y.Log.Println(err)
-				}
-				if len(errs) > 0 {
-					return errs[0]
-				}
+			seg.L1Overrides[parentID] = override
+			parents = append(parents, parentSegs[parentID])
+		}
+
+		// Plugin labels are inherited via plugin system, resolved across all parents at once
+		// so per-key strategies (e.g. strictest) don't depend on parent iteration order
+		if pluginsList != nil {
+			var errs []error
+			// Each parent is validated against the child as inherited from the parents up to it,
+			// matching the order-dependent checks of single-parent inheritance
+			for i, parent := range parents[:max(len(parents)-1, 0)] {
+				partial := cloneSegLabels(seg)
+				pluginsList.ApplyPluginInheritance(parents[:i+1], &partial)
+				errs = append(errs, pluginsList.ValidateRelationships(parent, &partial)...)
+			}
+			errs = append(errs, pluginsList.ApplyPluginInheritance(parents, &seg)...)
+			if len(parents) > 0 {
+				errs = append(errs, pluginsList.ValidateRelationships(parents[len(parents)-1], &seg)...)
+			}
+			for _, err := range errs {
+				o11y.Log.Println(err)
+			}
+			if len(errs) > 0 {
+				return errs[0]
 			}
 		}
 	}
 	return nil
 }
+
+// cloneSegLabels copies a segment with its own label maps so inheritance can be trialled without mutating it.
+func cloneSegLabels(seg domain.Seg) domain.Seg {
+	clone := seg
+	clone.ParsedLabels = make(map[string]string, len(seg.ParsedLabels))
+	for k, v := range seg.ParsedLabels {
+		clone.ParsedLabels[k] = v
+	}
+	clone.LabelNamespaces = make(map[string]map[string]string, len(seg.LabelNamespaces))
+	for ns, labels := range seg.LabelNamespaces {
+		clone.LabelNamespaces[ns] = make(map[string]string, len(labels))
+		for k, v := range labels {
+			clone.LabelNamespaces[ns][k] = v
+		}
+	}
+	return clone
+}
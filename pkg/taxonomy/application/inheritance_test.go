@@ -1,8 +1,10 @@
 package application
 
 import (
+	"os"
 	"testing"
 
+	"github.com/kvql/bunsceal/pkg/config"
 	"github.com/kvql/bunsceal/pkg/domain"
 	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
 )
@@ -172,3 +174,140 @@ func TestApplyInheritance_NilPlugins(t *testing.T) {
 		}
 	})
 }
+
+func TestApplyInheritance_Strategies(t *testing.T) {
+	t.Run("Strictest resolves across all parents regardless of order", func(t *testing.T) {
+		p := newTestPlugins(true)
+		classifications := p["classifications"].(*plugins.ClassificationsPlugin)
+		def := classifications.Config.Definitions["sensitivity"]
+		def.Inheritance = plugins.InheritStrictest
+		classifications.Config.Definitions["sensitivity"] = def
+
+		dev := newTestSegWithLabels("dev", []string{
+			label("sensitivity", "low"),
+			label("sensitivity_rationale", "Synthetic data only"),
+		})
+		prod := newTestSegWithLabels("prod", []string{
+			label("sensitivity", "high"),
+			label("sensitivity_rationale", "Production contains PII data"),
+		})
+		childSeg := newTestSegWithLabels("app", []string{})
+		childSeg.L1Parents = []string{"dev", "prod"}
+
+		txy := domain.Taxonomy{
+			SegL1s:  map[string]domain.Seg{"dev": dev, "prod": prod},
+			SegsL2s: map[string]domain.Seg{"app": childSeg},
+		}
+
+		if err := ApplyInheritance(&txy, p); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if txy.SegsL2s["app"].LabelNamespaces[testNs]["sensitivity"] != "high" {
+			t.Errorf("Expected child to inherit strictest sensitivity=high, got %q",
+				txy.SegsL2s["app"].LabelNamespaces[testNs]["sensitivity"])
+		}
+	})
+}
+
+func TestApplyInheritance_StrictestWithExampleConfig(t *testing.T) {
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir("../../.."); err != nil {
+		t.Fatalf("Failed to change to project root: %v", err)
+	}
+	defer os.Chdir(originalWd)
+
+	cfg, err := config.LoadConfig("example/config.yaml", "pkg/config/schemas")
+	if err != nil {
+		t.Fatalf("Failed to load example config: %v", err)
+	}
+	def := cfg.Plugins.Classifications.Definitions["sensitivity"]
+	def.Inheritance = plugins.InheritStrictest
+	cfg.Plugins.Classifications.Definitions["sensitivity"] = def
+	p := make(plugins.Plugins)
+	if err := p.LoadPlugins(cfg.Plugins); err != nil {
+		t.Fatalf("Failed to load plugins: %v", err)
+	}
+
+	// Strictest parent first, so the final check runs against the less strict staging
+	prod := newTestSegWithLabels("prod", []string{
+		label("sensitivity", "A"),
+		label("sensitivity_rationale", "Production holds card data"),
+	})
+	staging := newTestSegWithLabels("staging", []string{
+		label("sensitivity", "C"),
+		label("sensitivity_rationale", "Synthetic data only"),
+	})
+	childSeg := newTestSegWithLabels("app", []string{})
+	childSeg.L1Parents = []string{"prod", "staging"}
+
+	txy := domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"prod": prod, "staging": staging},
+		SegsL2s: map[string]domain.Seg{"app": childSeg},
+	}
+
+	if err := ApplyInheritance(&txy, p); err != nil {
+		t.Fatalf("Expected strictest to pass enforce_order, got %v", err)
+	}
+	if got := txy.SegsL2s["app"].LabelNamespaces[testNs]["sensitivity"]; got != "A" {
+		t.Errorf("Expected child to inherit strictest sensitivity=A, got %q", got)
+	}
+}
+
+func TestApplyInheritance_Levels(t *testing.T) {
+	prod := newTestSegWithLabels("prod", []string{label("sensitivity", "high"), label("sensitivity_rationale", "Production holds PII")})
+	app := newTestSegWithLabels("app", nil)
+	app.L1Parents = []string{"prod"}
+	web := newTestSegWithLabels("web", nil)
+	web.L1Parents = []string{"app"}
+	batch := newTestSegWithLabels("batch", nil)
+	batch.L1Parents = []string{"web"}
+
+	txy := domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"prod": prod},
+		SegsL2s: map[string]domain.Seg{"app": app},
+		Levels: map[string]map[string]domain.Seg{
+			"3": {"web": web},
+			"4": {"batch": batch},
+		},
+	}
+
+	if err := ApplyInheritance(&txy, newTestPlugins(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for level, id := range map[int]string{3: "web", 4: "batch"} {
+		seg := txy.Segs(level)[id]
+		if got := seg.ParsedLabels[testNs+"/sensitivity"]; got != "high" {
+			t.Errorf("Expected L%d %s to inherit sensitivity high through the chain, got %q", level, id, got)
+		}
+		if _, ok := seg.L1Overrides[seg.L1Parents[0]]; !ok {
+			t.Errorf("Expected L%d %s to have an override entry for its parent", level, id)
+		}
+	}
+}
+
+func TestApplyInheritance_ParentSelectors(t *testing.T) {
+	prod := newTestSegWithLabels("prod", []string{label("sensitivity", "high"), label("sensitivity_rationale", "Production holds PII")})
+	staging := newTestSegWithLabels("staging", []string{label("sensitivity", "low"), label("sensitivity_rationale", "Synthetic data only")})
+	app := newTestSegWithLabels("app", nil)
+	app.L1Parents = []string{"prod"}
+	tools := newTestSegWithLabels("tools", nil)
+	tools.L1Parents = []string{"staging"}
+	web := newTestSegWithLabels("web", nil)
+	web.L1Parents = []string{label("sensitivity", "high")}
+
+	txy := domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"prod": prod, "staging": staging},
+		SegsL2s: map[string]domain.Seg{"app": app, "tools": tools},
+		Levels:  map[string]map[string]domain.Seg{"3": {"web": web}},
+	}
+
+	if err := ApplyInheritance(&txy, newTestPlugins(true)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parents := txy.Segs(3)["web"].L1Parents; len(parents) != 1 || parents[0] != "app" {
+		t.Errorf("Expected selector to match the L2 that inherited sensitivity high, got %v", parents)
+	}
+}
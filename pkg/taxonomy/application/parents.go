@@ -0,0 +1,25 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+// expandLevelParents resolves wildcard and label selector parents, and default overrides, of the
+// segments of a level against the level above. The level above must have inherited its labels
+// already so label selectors match inherited labels. Segments are updated in place.
+func expandLevelParents(txy *domain.Taxonomy, level int) []error {
+	var errs []error
+	parents := txy.Segs(level - 1)
+	segs := txy.Segs(level)
+	for _, id := range txy.SegIDs(level) {
+		seg := segs[id]
+		if err := seg.ExpandParents(parents); err != nil {
+			errs = append(errs, fmt.Errorf("L%d %w", level, err))
+			continue
+		}
+		segs[id] = seg
+	}
+	return errs
+}
@@ -1,7 +1,9 @@
 package plugins
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/kvql/bunsceal/pkg/domain"
 )
@@ -18,6 +20,7 @@ type ClassificationDefinition struct {
 	EnforceOrder    bool              `yaml:"enforce_order"`
 	Values          map[string]string `yaml:"values"`
 	Order           []string          `yaml:"order"`
+	Inheritance     string            `yaml:"inheritance,omitempty"`
 }
 
 type ClassificationsPlugin struct {
@@ -41,6 +44,17 @@ func NewClassificationPlugin(config *ClassificationsConfig, prefix string) *Clas
 	}
 }
 
+// MetadataSchema accepts supporting documents, a rationale and links, per configured classification key.
+func (p ClassificationsPlugin) MetadataSchema() string {
+	keys := make([]string, 0, len(p.Config.Definitions))
+	for key := range p.Config.Definitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	enum, _ := json.Marshal(keys)
+	return fmt.Sprintf(ClassificationsMetadataSchema, enum)
+}
+
 func (p ClassificationsPlugin) validateNamespaceLabels(labels map[string]string, ctx string, errs *[]error) int {
 	foundKeys := 0
 	for defKey, def := range p.Config.Definitions {
@@ -96,7 +110,8 @@ func (p ClassificationsPlugin) ValidateLabels(seg *domain.Seg) PluginValidationR
 	return result
 }
 
-// ValidateRelationship checks parent >= child in severity order for all definitions
+// ValidateRelationship checks parent >= child in severity order for all definitions.
+// Definitions inheriting with InheritStrictest are not checked for values the child inherited.
 func (p ClassificationsPlugin) ValidateRelationship(parent, child *domain.Seg) []error {
 	var errs []error
 	override, hasOverride := child.L1Overrides[parent.ID]
@@ -108,8 +123,14 @@ func (p ClassificationsPlugin) ValidateRelationship(parent, child *domain.Seg) [
 
 		parentValue := parent.LabelNamespaces[p.Namespace][defKey]
 		childValue := child.LabelNamespaces[p.Namespace][defKey]
+		overridden := false
 		if hasOverride && len(override.LabelNamespaces[p.Namespace]) > 0 {
 			childValue = override.LabelNamespaces[p.Namespace][defKey]
+			overridden = childValue != ""
+		}
+		// An inherited strictest value is meant to outrank its less strict parents, declared ones are still checked
+		if def.Inheritance == InheritStrictest && !overridden && !child.DeclaresLabel(p.Namespace+"/"+defKey) {
+			continue
 		}
 
 		parentIdx, pOk := p.OrderIndex[defKey][parentValue]
@@ -123,6 +144,23 @@ func (p ClassificationsPlugin) ValidateRelationship(parent, child *domain.Seg) [
 	return errs
 }
 
+// InheritanceStrategies returns the configured inheritance strategy per definition key.
+func (p ClassificationsPlugin) InheritanceStrategies() map[string]string {
+	strategies := make(map[string]string)
+	for defKey, def := range p.Config.Definitions {
+		if def.Inheritance != "" {
+			strategies[defKey] = def.Inheritance
+		}
+	}
+	return strategies
+}
+
+// StrictnessRank returns the position of value in the definition order, first is strictest.
+func (p ClassificationsPlugin) StrictnessRank(key, value string) (int, bool) {
+	rank, ok := p.OrderIndex[key][value]
+	return rank, ok
+}
+
 func (p ClassificationsPlugin) GetEnabled() bool {
 	return p.Config.Common.LabelInheritance
 }
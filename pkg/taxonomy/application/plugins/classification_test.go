@@ -250,6 +250,44 @@ func TestValidateRelationship(t *testing.T) {
 		}
 	})
 
+	t.Run("Skips inherited strictest values only", func(t *testing.T) {
+		config := newTestConfigWithOrder(true, 10, true)
+		def := config.Definitions["sensitivity"]
+		def.Inheritance = InheritStrictest
+		config.Definitions["sensitivity"] = def
+		plugin := NewClassificationPlugin(config, NsPrefix)
+		parent := newTestSeg("parent", []string{
+			label("sensitivity", "low"),
+			label("sensitivity_rationale", "Parent is low"),
+		})
+		child := newTestSeg("child", []string{})
+		child.LabelNamespaces[testNs] = map[string]string{}
+		setInheritedLabel(child, testNs, "sensitivity", "high")
+
+		if errs := plugin.ValidateRelationship(parent, child); len(errs) > 0 {
+			t.Errorf("Expected no errors for inherited strictest value, got: %v", errs)
+		}
+
+		declared := newTestSeg("child", []string{
+			label("sensitivity", "high"),
+			label("sensitivity_rationale", "Child is high"),
+		})
+		if errs := plugin.ValidateRelationship(parent, declared); len(errs) == 0 {
+			t.Error("Expected error when the child declares a value more severe than parent")
+		}
+
+		override := domain.L1Overrides{Labels: []string{
+			label("sensitivity", "high"),
+			label("sensitivity_rationale", "Override is high"),
+		}}
+		override.ParseLabels()
+		child.L1Overrides = map[string]domain.L1Overrides{"parent": override}
+
+		if errs := plugin.ValidateRelationship(parent, child); len(errs) == 0 {
+			t.Error("Expected error when override makes child more severe than parent")
+		}
+	})
+
 	t.Run("Skips when values not in order list", func(t *testing.T) {
 		config := newTestConfigWithOrder(true, 10, true)
 		plugin := NewClassificationPlugin(config, NsPrefix)
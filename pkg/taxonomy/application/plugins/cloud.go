@@ -0,0 +1,222 @@
+package plugins
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+const (
+	ProviderAWS   = "aws"
+	ProviderGCP   = "gcp"
+	ProviderAzure = "azure"
+)
+
+// cloudIDFormats holds the account/project/subscription ID format for each supported provider.
+var cloudIDFormats = map[string]*regexp.Regexp{
+	ProviderAWS:   regexp.MustCompile(`^[0-9]{12}$`),
+	ProviderGCP:   regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`),
+	ProviderAzure: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+}
+
+// CloudConfig configures the cloud account mapping plugin.
+// Providers restricts which providers may be used; empty allows all supported providers.
+type CloudConfig struct {
+	Providers []string `yaml:"providers,omitempty"`
+}
+
+type CloudPlugin struct {
+	Config    *CloudConfig
+	Namespace string
+	providers map[string]bool
+}
+
+// CloudAccountOwner records the segment that owns a cloud account.
+// L2ID is empty when the account belongs directly to an L1 segment.
+type CloudAccountOwner struct {
+	Provider  string `json:"provider"`
+	AccountID string `json:"account_id"`
+	L1ID      string `json:"l1_id"`
+	L2ID      string `json:"l2_id,omitempty"`
+}
+
+func (o CloudAccountOwner) segment() string {
+	if o.L2ID == "" {
+		return "L1 " + o.L1ID
+	}
+	return fmt.Sprintf("L2 %s in L1 %s", o.L2ID, o.L1ID)
+}
+
+func NewCloudPlugin(config *CloudConfig, prefix string) *CloudPlugin {
+	providers := make(map[string]bool)
+	if len(config.Providers) == 0 {
+		for provider := range cloudIDFormats {
+			providers[provider] = true
+		}
+	}
+	for _, provider := range config.Providers {
+		providers[provider] = true
+	}
+	return &CloudPlugin{
+		Config:    config,
+		Namespace: prefix + "cloud",
+		providers: providers,
+	}
+}
+
+// splitAccountIDs splits a comma separated label value into trimmed, non-empty IDs.
+func splitAccountIDs(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (p CloudPlugin) validateNamespaceLabels(labels map[string]string, ctx string, errs *[]error) {
+	for provider, value := range labels {
+		format, supported := cloudIDFormats[provider]
+		if !supported || !p.providers[provider] {
+			*errs = append(*errs, fmt.Errorf("%s has unsupported cloud provider %s", ctx, provider))
+			continue
+		}
+		ids := splitAccountIDs(value)
+		if len(ids) == 0 {
+			*errs = append(*errs, fmt.Errorf("%s has no %s account IDs", ctx, provider))
+		}
+		seen := make(map[string]bool)
+		for _, id := range ids {
+			if !format.MatchString(id) {
+				*errs = append(*errs, fmt.Errorf("%s invalid %s account ID %s", ctx, provider, id))
+			}
+			if seen[id] {
+				*errs = append(*errs, fmt.Errorf("%s lists %s account ID %s more than once", ctx, provider, id))
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func (p CloudPlugin) ValidateLabels(seg *domain.Seg) PluginValidationResult {
+	result := PluginValidationResult{Valid: false, Errors: []error{}}
+
+	p.validateNamespaceLabels(seg.LabelNamespaces[p.Namespace], "segment "+seg.ID, &result.Errors)
+
+	for parentID, override := range seg.L1Overrides {
+		if len(override.LabelNamespaces[p.Namespace]) > 0 {
+			p.validateNamespaceLabels(override.LabelNamespaces[p.Namespace], fmt.Sprintf("segment %s l1_override[%s]", seg.ID, parentID), &result.Errors)
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// segmentAccounts returns provider -> account IDs for an L1 segment, or the effective
+// accounts of an L2 segment for the given parent (override > child base value, per provider).
+func (p CloudPlugin) segmentAccounts(seg domain.Seg, parentID string) map[string][]string {
+	values := make(map[string]string)
+	for provider, value := range seg.LabelNamespaces[p.Namespace] {
+		values[provider] = value
+	}
+	if override, ok := seg.L1Overrides[parentID]; ok && parentID != "" {
+		for provider, value := range override.LabelNamespaces[p.Namespace] {
+			values[provider] = value
+		}
+	}
+
+	accounts := make(map[string][]string)
+	for provider, value := range values {
+		accounts[provider] = splitAccountIDs(value)
+	}
+	return accounts
+}
+
+// Owners returns every account claim in the taxonomy, sorted by provider, account and segment.
+func (p CloudPlugin) Owners(txy *domain.Taxonomy) []CloudAccountOwner {
+	var owners []CloudAccountOwner
+	add := func(accounts map[string][]string, l1ID, l2ID string) {
+		for provider, ids := range accounts {
+			for _, id := range ids {
+				owners = append(owners, CloudAccountOwner{Provider: provider, AccountID: id, L1ID: l1ID, L2ID: l2ID})
+			}
+		}
+	}
+	for l1ID, seg := range txy.SegL1s {
+		add(p.segmentAccounts(seg, ""), l1ID, "")
+	}
+	for l2ID, seg := range txy.SegsL2s {
+		for _, l1ID := range seg.L1Parents {
+			add(p.segmentAccounts(seg, l1ID), l1ID, l2ID)
+		}
+	}
+
+	sort.Slice(owners, func(i, j int) bool {
+		a, b := owners[i], owners[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		if a.L1ID != b.L1ID {
+			return a.L1ID < b.L1ID
+		}
+		return a.L2ID < b.L2ID
+	})
+	return owners
+}
+
+// ValidateTaxonomy ensures no account is claimed by more than one segment.
+// An L2 listing an account in its base labels claims it for every parent,
+// so multi-parent L2s must set accounts per parent in l1_overrides.
+// Accounts are owned by L1 or L2 segments, segments below L2 must not claim any.
+func (p CloudPlugin) ValidateTaxonomy(txy *domain.Taxonomy) []error {
+	var errs []error
+	for _, seg := range segmentsBelowL2(txy, p.Namespace, "") {
+		errs = append(errs, fmt.Errorf("%s sets cloud accounts, accounts are only supported on L1 and L2 segments", seg))
+	}
+	owners := p.Owners(txy)
+	for i := 1; i < len(owners); i++ {
+		prev, cur := owners[i-1], owners[i]
+		if prev.Provider == cur.Provider && prev.AccountID == cur.AccountID {
+			errs = append(errs, fmt.Errorf("%s account %s is claimed by both %s and %s",
+				cur.Provider, cur.AccountID, prev.segment(), cur.segment()))
+		}
+	}
+	return errs
+}
+
+// AccountIndex returns the reverse index provider -> account ID -> owning segment.
+// Must only be used on a taxonomy that passed ValidateTaxonomy; duplicate claims keep the first owner.
+func (p CloudPlugin) AccountIndex(txy *domain.Taxonomy) map[string]map[string]CloudAccountOwner {
+	index := make(map[string]map[string]CloudAccountOwner)
+	for _, owner := range p.Owners(txy) {
+		if index[owner.Provider] == nil {
+			index[owner.Provider] = make(map[string]CloudAccountOwner)
+		}
+		if _, exists := index[owner.Provider][owner.AccountID]; !exists {
+			index[owner.Provider][owner.AccountID] = owner
+		}
+	}
+	return index
+}
+
+// GetEnabled always returns false: account ownership is exclusive,
+// a child copying its parent's accounts would create duplicate claims.
+func (p CloudPlugin) GetEnabled() bool {
+	return false
+}
+
+func (p CloudPlugin) GetNamespace() string {
+	return p.Namespace
+}
+
+func (p CloudPlugin) GetImageData() []ImageGroupingData {
+	return []ImageGroupingData{}
+}
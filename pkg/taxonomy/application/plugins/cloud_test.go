@@ -0,0 +1,149 @@
+package plugins
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+const cloudTestNs = "bunsceal.plugin.cloud"
+
+// Helper to build cloud account label
+func cloudLabel(provider, value string) string {
+	return cloudTestNs + "/" + provider + ":" + value
+}
+
+// newCloudTestTaxonomy builds prod and dev L1s with a multi-parent L2 using per-parent accounts
+func newCloudTestTaxonomy() *domain.Taxonomy {
+	prod := newTestSeg("prod", []string{cloudLabel("aws", "111111111111")})
+	dev := newTestSeg("dev", []string{cloudLabel("gcp", "dev-shared-project")})
+
+	app := domain.Seg{
+		ID:        "app",
+		L1Parents: []string{"prod", "dev"},
+		L1Overrides: map[string]domain.L1Overrides{
+			"prod": {Labels: []string{cloudLabel("aws", "222222222222,333333333333")}},
+			"dev":  {Labels: []string{cloudLabel("aws", "444444444444")}},
+		},
+	}
+	app.ParseLabels()
+
+	return &domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"prod": *prod, "dev": *dev},
+		SegsL2s: map[string]domain.Seg{"app": app},
+	}
+}
+
+func TestCloudValidateLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []string
+		label     string
+		valid     bool
+	}{
+		{"Valid AWS account", nil, cloudLabel("aws", "123456789012"), true},
+		{"Valid AWS account list", nil, cloudLabel("aws", "123456789012, 210987654321"), true},
+		{"Valid GCP project", nil, cloudLabel("gcp", "my-project-123"), true},
+		{"Valid Azure subscription", nil, cloudLabel("azure", "0f8fad5b-d9cb-469f-a165-70867728950e"), true},
+		{"AWS account too short", nil, cloudLabel("aws", "12345"), false},
+		{"GCP project uppercase", nil, cloudLabel("gcp", "My-Project"), false},
+		{"Azure subscription not UUID", nil, cloudLabel("azure", "subscription-1"), false},
+		{"Unknown provider", nil, cloudLabel("oci", "ocid1"), false},
+		{"Provider not enabled", []string{"aws"}, cloudLabel("gcp", "my-project-123"), false},
+		{"Duplicate within list", nil, cloudLabel("aws", "123456789012,123456789012"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := NewCloudPlugin(&CloudConfig{Providers: tt.providers}, NsPrefix)
+			seg := newTestSeg("prod", []string{tt.label})
+
+			result := plugin.ValidateLabels(seg)
+
+			if result.Valid != tt.valid {
+				t.Errorf("Expected valid=%v, got %v (errors: %v)", tt.valid, result.Valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestCloudValidateTaxonomy(t *testing.T) {
+	plugin := NewCloudPlugin(&CloudConfig{}, NsPrefix)
+
+	t.Run("Passes when every account has a single owner", func(t *testing.T) {
+		errs := plugin.ValidateTaxonomy(newCloudTestTaxonomy())
+
+		if len(errs) > 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when two segments claim the same account", func(t *testing.T) {
+		txy := newCloudTestTaxonomy()
+		db := domain.Seg{ID: "db", L1Parents: []string{"prod"}, Labels: []string{cloudLabel("aws", "333333333333")}}
+		db.ParseLabels()
+		txy.SegsL2s["db"] = db
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "333333333333") {
+			t.Errorf("Expected duplicate claim error for 333333333333, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when multi-parent L2 sets account in base labels", func(t *testing.T) {
+		txy := newCloudTestTaxonomy()
+		shared := domain.Seg{ID: "shared", L1Parents: []string{"prod", "dev"}, Labels: []string{cloudLabel("aws", "555555555555")}}
+		shared.ParseLabels()
+		txy.SegsL2s["shared"] = shared
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 {
+			t.Errorf("Expected 1 duplicate claim error, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when a segment below L2 claims an account", func(t *testing.T) {
+		txy := newCloudTestTaxonomy()
+		api := domain.Seg{ID: "api", L1Parents: []string{"app"}, Labels: []string{cloudLabel("aws", "666666666666")}}
+		api.ParseLabels()
+		txy.Levels = map[string]map[string]domain.Seg{"3": {"api": api}}
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "L3 segment api sets cloud accounts") {
+			t.Errorf("Expected error for L3 api claiming an account, got %v", errs)
+		}
+	})
+}
+
+func TestCloudAccountIndex(t *testing.T) {
+	plugin := NewCloudPlugin(&CloudConfig{}, NsPrefix)
+
+	index := plugin.AccountIndex(newCloudTestTaxonomy())
+
+	if len(index["aws"]) != 4 {
+		t.Errorf("Expected 4 AWS accounts, got %d", len(index["aws"]))
+	}
+	owner := index["aws"]["444444444444"]
+	if owner.L1ID != "dev" || owner.L2ID != "app" {
+		t.Errorf("Expected 444444444444 owned by (dev, app), got (%s, %s)", owner.L1ID, owner.L2ID)
+	}
+	owner = index["gcp"]["dev-shared-project"]
+	if owner.L1ID != "dev" || owner.L2ID != "" {
+		t.Errorf("Expected dev-shared-project owned by L1 dev, got (%s, %s)", owner.L1ID, owner.L2ID)
+	}
+}
+
+func TestCloudGetEnabled(t *testing.T) {
+	plugin := NewCloudPlugin(&CloudConfig{}, NsPrefix)
+
+	if plugin.GetEnabled() {
+		t.Error("Expected cloud plugin to never inherit labels")
+	}
+	if plugin.GetNamespace() != cloudTestNs {
+		t.Errorf("Expected namespace %s, got %s", cloudTestNs, plugin.GetNamespace())
+	}
+}
@@ -22,6 +22,9 @@ type ComplianceDefinition struct {
 	DescriptiveName  string `yaml:"name"`
 	Description      string `yaml:"description"`
 	RequirementsLink string `yaml:"requirements_link,omitempty"`
+	Inheritance      string `yaml:"inheritance,omitempty"`
+	// AllowedResourceTypes restricts the resource types in-scope segments may deploy; empty allows all.
+	AllowedResourceTypes []string `yaml:"allowed_resource_types,omitempty"`
 }
 
 type CompliancePlugin struct {
@@ -88,6 +91,7 @@ func (p CompliancePlugin) ValidateLabels(seg *domain.Seg) PluginValidationResult
 
 // ValidateRelationship checks parent-child compliance scope hierarchy when EnforceScopeHierarchy is enabled.
 // A child cannot be in-scope for a requirement that the parent doesn't have defined.
+// Requirements inheriting with InheritStrictest are not checked for values the child inherited.
 func (p CompliancePlugin) ValidateRelationship(parent, child *domain.Seg) []error {
 	if !p.Config.EnforceScopeHierarchy {
 		return nil
@@ -96,14 +100,20 @@ func (p CompliancePlugin) ValidateRelationship(parent, child *domain.Seg) []erro
 	var errs []error
 	override, hasOverride := child.L1Overrides[parent.ID]
 
-	for reqID := range p.Config.Definitions {
+	for reqID, def := range p.Config.Definitions {
 		parentScope := parent.LabelNamespaces[p.Namespace][reqID]
 		childScope := child.LabelNamespaces[p.Namespace][reqID]
+		overridden := false
 		if hasOverride && len(override.LabelNamespaces[p.Namespace]) > 0 {
 			if overrideScope, ok := override.LabelNamespaces[p.Namespace][reqID]; ok {
 				childScope = overrideScope
+				overridden = true
 			}
 		}
+		// An inherited strictest scope is meant to outrank its less strict parents, declared ones are still checked
+		if def.Inheritance == InheritStrictest && !overridden && !child.DeclaresLabel(p.Namespace+"/"+reqID) {
+			continue
+		}
 
 		// If parent doesn't have the requirement defined and child is in-scope, error
 		if parentScope == "" && childScope == ScopeInScope {
@@ -114,6 +124,28 @@ func (p CompliancePlugin) ValidateRelationship(parent, child *domain.Seg) []erro
 	return errs
 }
 
+// InheritanceStrategies returns the configured inheritance strategy per requirement.
+func (p CompliancePlugin) InheritanceStrategies() map[string]string {
+	strategies := make(map[string]string)
+	for reqID, def := range p.Config.Definitions {
+		if def.Inheritance != "" {
+			strategies[reqID] = def.Inheritance
+		}
+	}
+	return strategies
+}
+
+// StrictnessRank ranks in-scope as stricter than out-of-scope.
+func (p CompliancePlugin) StrictnessRank(_, value string) (int, bool) {
+	switch value {
+	case ScopeInScope:
+		return 0, true
+	case ScopeOutOfScope:
+		return 1, true
+	}
+	return 0, false
+}
+
 func (p CompliancePlugin) GetEnabled() bool {
 	return p.Config.Common.LabelInheritance
 }
@@ -152,6 +152,30 @@ func TestComplianceValidateRelationship_HierarchyEnforced(t *testing.T) {
 		}
 	})
 
+	t.Run("Skips inherited strictest requirements only", func(t *testing.T) {
+		config := newComplianceTestConfig(true, 10, true)
+		def := config.Definitions["pci-dss"]
+		def.Inheritance = InheritStrictest
+		config.Definitions["pci-dss"] = def
+		plugin := NewCompliancePlugin(config, NsPrefix)
+		parent := newTestSeg("parent", []string{}) // No compliance labels
+		child := newTestSeg("child", []string{})
+		child.LabelNamespaces[complianceTestNs] = map[string]string{}
+		setInheritedLabel(child, complianceTestNs, "pci-dss", "in-scope")
+
+		if errs := plugin.ValidateRelationship(parent, child); len(errs) > 0 {
+			t.Errorf("Expected no errors for inherited strictest requirement, got: %v", errs)
+		}
+
+		declared := newTestSeg("child", []string{
+			complianceLabel("pci-dss", "in-scope"),
+			complianceLabel("pci-dss_rationale", "Child is in scope"),
+		})
+		if errs := plugin.ValidateRelationship(parent, declared); len(errs) == 0 {
+			t.Error("Expected error when the child declares in-scope without the parent requirement")
+		}
+	})
+
 	t.Run("Passes when child is out-of-scope", func(t *testing.T) {
 		config := newComplianceTestConfig(true, 10, true)
 		plugin := NewCompliancePlugin(config, NsPrefix)
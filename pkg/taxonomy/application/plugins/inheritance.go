@@ -0,0 +1,169 @@
+package plugins
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+// Inheritance strategies, configured per definition key.
+// Rationale keys (<key>_rationale) always follow the strategy of their key
+// and are copied from the same parent as the inherited value.
+const (
+	// InheritFill copies the value from the first parent (in l1_parents order) that has it. Default.
+	InheritFill = "fill"
+	// InheritStrictest copies the strictest value across all parents, ranked by StrictnessRank.
+	InheritStrictest = "strictest"
+	// InheritNone never inherits the key.
+	InheritNone = "none"
+	// InheritRequired never inherits and requires an explicit value for every parent.
+	InheritRequired = "required"
+)
+
+const rationaleSuffix = "_rationale"
+
+// InheritanceConfigurer is implemented by plugins whose inheritance behaviour is configured per key.
+type InheritanceConfigurer interface {
+	// InheritanceStrategies returns the strategy per definition key. Keys not listed use InheritFill.
+	InheritanceStrategies() map[string]string
+	// StrictnessRank ranks a value for key, lower is stricter. ok is false for unknown values.
+	StrictnessRank(key, value string) (rank int, ok bool)
+}
+
+func setInheritedLabel(child *domain.Seg, ns, key, value string) {
+	child.LabelNamespaces[ns][key] = value
+	child.ParsedLabels[ns+"/"+key] = value
+}
+
+// ApplyPluginInheritance fills missing child labels from all of its parents at once,
+// using each plugin's per-key strategy. Overrides are never inheritance sources or targets.
+// Returns errors for keys using InheritRequired that have no explicit value for a parent.
+func (p Plugins) ApplyPluginInheritance(parents []domain.Seg, child *domain.Seg) []error {
+	var allErrors []error
+
+	for _, plugin := range p {
+		if !plugin.GetEnabled() {
+			continue
+		}
+
+		ns := plugin.GetNamespace()
+		if child.LabelNamespaces[ns] == nil {
+			child.LabelNamespaces[ns] = make(map[string]string)
+		}
+
+		strategies := map[string]string{}
+		configurer, hasConfig := plugin.(InheritanceConfigurer)
+		if hasConfig {
+			strategies = configurer.InheritanceStrategies()
+		}
+
+		// Keys with an explicit strategy, rationale handled alongside its key
+		handled := make(map[string]bool)
+		keys := make([]string, 0, len(strategies))
+		for key := range strategies {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			handled[key] = true
+			handled[key+rationaleSuffix] = true
+			allErrors = append(allErrors, inheritKey(strategies[key], configurer, ns, key, parents, child)...)
+		}
+
+		// Remaining keys are filled from the first parent that has them
+		for _, parent := range parents {
+			for k, v := range parent.LabelNamespaces[ns] {
+				if handled[k] {
+					continue
+				}
+				if _, childHas := child.LabelNamespaces[ns][k]; !childHas {
+					setInheritedLabel(child, ns, k, v)
+				}
+			}
+		}
+	}
+	return allErrors
+}
+
+// inheritKey applies a single strategy for key (and its rationale) to child.
+func inheritKey(strategy string, configurer InheritanceConfigurer, ns, key string, parents []domain.Seg, child *domain.Seg) []error {
+	_, childHas := child.LabelNamespaces[ns][key]
+
+	switch strategy {
+	case InheritNone:
+		return nil
+	case InheritRequired:
+		var errs []error
+		for _, parent := range parents {
+			value, _ := child.GetNamespacedValue(parent.ID, ns, key)
+			if value == "" {
+				errs = append(errs, fmt.Errorf("child %s must explicitly set %s for parent %s (inheritance: %s)",
+					child.ID, key, parent.ID, InheritRequired))
+			}
+		}
+		return errs
+	}
+	if childHas {
+		return nil
+	}
+
+	var source *domain.Seg
+	switch strategy {
+	case InheritStrictest:
+		// Falls back to the first parent with a value when no parent value can be ranked
+		bestRank := -1
+		for i := range parents {
+			value, has := parents[i].LabelNamespaces[ns][key]
+			if !has {
+				continue
+			}
+			if source == nil {
+				source = &parents[i]
+			}
+			if rank, ranked := configurer.StrictnessRank(key, value); ranked && (bestRank < 0 || rank < bestRank) {
+				source = &parents[i]
+				bestRank = rank
+			}
+		}
+	default:
+		for i := range parents {
+			if _, has := parents[i].LabelNamespaces[ns][key]; has {
+				source = &parents[i]
+				break
+			}
+		}
+	}
+
+	if source == nil {
+		return nil
+	}
+	setInheritedLabel(child, ns, key, source.LabelNamespaces[ns][key])
+	copyRationale(source, child, ns, key)
+	return nil
+}
+
+// copyRationale copies the rationale for key from source when the child has none.
+func copyRationale(source, child *domain.Seg, ns, key string) {
+	rationaleKey := key + rationaleSuffix
+	if _, childHas := child.LabelNamespaces[ns][rationaleKey]; childHas {
+		return
+	}
+	if rationale, has := source.LabelNamespaces[ns][rationaleKey]; has {
+		setInheritedLabel(child, ns, rationaleKey, rationale)
+	}
+}
+
+// ValidateRelationships runs relational validation between a parent and child for all enabled plugins.
+func (p Plugins) ValidateRelationships(parent domain.Seg, child *domain.Seg) []error {
+	var allErrors []error
+	for _, plugin := range p {
+		if !plugin.GetEnabled() {
+			continue
+		}
+		if validator, ok := plugin.(RelationalValidator); ok {
+			allErrors = append(allErrors, validator.ValidateRelationship(&parent, child)...)
+		}
+	}
+	return allErrors
+}
@@ -0,0 +1,132 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+// newStrategyPlugins builds classifications with the given sensitivity strategy, order not enforced
+func newStrategyPlugins(strategy string) Plugins {
+	config := newTestConfigWithOrder(true, 10, false)
+	def := config.Definitions["sensitivity"]
+	def.Inheritance = strategy
+	config.Definitions["sensitivity"] = def
+
+	plugs := make(Plugins)
+	plugs["classifications"] = NewClassificationPlugin(config, NsPrefix)
+	return plugs
+}
+
+func newStrategyParents() []domain.Seg {
+	staging := newTestSeg("staging", []string{
+		label("sensitivity", "low"),
+		label("sensitivity_rationale", "Synthetic data only"),
+	})
+	prod := newTestSeg("prod", []string{
+		label("sensitivity", "high"),
+		label("sensitivity_rationale", "Production holds PII"),
+	})
+	return []domain.Seg{*staging, *prod}
+}
+
+func TestApplyPluginInheritance(t *testing.T) {
+	t.Run("Fill takes first parent in l1_parents order", func(t *testing.T) {
+		child := newTestSeg("app", []string{})
+
+		errs := newStrategyPlugins(InheritFill).ApplyPluginInheritance(newStrategyParents(), child)
+
+		if len(errs) > 0 {
+			t.Fatalf("Expected no errors, got %v", errs)
+		}
+		if child.LabelNamespaces[testNs]["sensitivity"] != "low" {
+			t.Errorf("Expected sensitivity=low from first parent, got %q", child.LabelNamespaces[testNs]["sensitivity"])
+		}
+	})
+
+	t.Run("Strictest takes highest ranked value and its rationale", func(t *testing.T) {
+		child := newTestSeg("app", []string{})
+
+		errs := newStrategyPlugins(InheritStrictest).ApplyPluginInheritance(newStrategyParents(), child)
+
+		if len(errs) > 0 {
+			t.Fatalf("Expected no errors, got %v", errs)
+		}
+		if child.LabelNamespaces[testNs]["sensitivity"] != "high" {
+			t.Errorf("Expected sensitivity=high, got %q", child.LabelNamespaces[testNs]["sensitivity"])
+		}
+		if child.LabelNamespaces[testNs]["sensitivity_rationale"] != "Production holds PII" {
+			t.Errorf("Expected rationale from prod, got %q", child.LabelNamespaces[testNs]["sensitivity_rationale"])
+		}
+		if child.ParsedLabels[testNs+"/sensitivity"] != "high" {
+			t.Errorf("Expected ParsedLabels updated, got %q", child.ParsedLabels[testNs+"/sensitivity"])
+		}
+	})
+
+	t.Run("Strictest keeps explicit child value", func(t *testing.T) {
+		child := newTestSeg("app", []string{
+			label("sensitivity", "medium"),
+			label("sensitivity_rationale", "Reviewed separately"),
+		})
+
+		newStrategyPlugins(InheritStrictest).ApplyPluginInheritance(newStrategyParents(), child)
+
+		if child.LabelNamespaces[testNs]["sensitivity"] != "medium" {
+			t.Errorf("Expected sensitivity=medium, got %q", child.LabelNamespaces[testNs]["sensitivity"])
+		}
+	})
+
+	t.Run("None never inherits key or rationale", func(t *testing.T) {
+		child := newTestSeg("app", []string{})
+
+		newStrategyPlugins(InheritNone).ApplyPluginInheritance(newStrategyParents(), child)
+
+		if _, has := child.LabelNamespaces[testNs]["sensitivity"]; has {
+			t.Error("Expected sensitivity not to be inherited")
+		}
+		if _, has := child.LabelNamespaces[testNs]["sensitivity_rationale"]; has {
+			t.Error("Expected sensitivity_rationale not to be inherited")
+		}
+	})
+
+	t.Run("Required errors for each parent without explicit value", func(t *testing.T) {
+		child := newTestSeg("app", []string{})
+		override := domain.L1Overrides{Labels: []string{label("sensitivity", "high"), label("sensitivity_rationale", "Production holds PII")}}
+		override.ParseLabels()
+		child.L1Overrides = map[string]domain.L1Overrides{"prod": override}
+
+		errs := newStrategyPlugins(InheritRequired).ApplyPluginInheritance(newStrategyParents(), child)
+
+		if len(errs) != 1 || !contains(errs[0].Error(), "parent staging") {
+			t.Errorf("Expected one error for parent staging, got %v", errs)
+		}
+		if _, has := child.LabelNamespaces[testNs]["sensitivity"]; has {
+			t.Error("Expected required key not to be inherited")
+		}
+	})
+
+	t.Run("Keys without strategy still fill", func(t *testing.T) {
+		parents := newStrategyParents()
+		parents[0].LabelNamespaces[testNs]["criticality"] = "2"
+		child := newTestSeg("app", []string{})
+
+		newStrategyPlugins(InheritNone).ApplyPluginInheritance(parents, child)
+
+		if child.LabelNamespaces[testNs]["criticality"] != "2" {
+			t.Errorf("Expected criticality=2 filled from parent, got %q", child.LabelNamespaces[testNs]["criticality"])
+		}
+	})
+}
+
+func TestComplianceStrictnessRank(t *testing.T) {
+	plugin := NewCompliancePlugin(&ComplianceConfig{}, NsPrefix)
+
+	inRank, _ := plugin.StrictnessRank("pci-dss", ScopeInScope)
+	outRank, _ := plugin.StrictnessRank("pci-dss", ScopeOutOfScope)
+	if inRank >= outRank {
+		t.Errorf("Expected in-scope to rank stricter than out-of-scope, got %d and %d", inRank, outRank)
+	}
+	if _, ok := plugin.StrictnessRank("pci-dss", "unknown"); ok {
+		t.Error("Expected unknown scope to be unranked")
+	}
+}
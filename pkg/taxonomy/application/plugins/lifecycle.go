@@ -0,0 +1,188 @@
+package plugins
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+const (
+	LifecycleProposed   = "proposed"
+	LifecycleActive     = "active"
+	LifecycleDeprecated = "deprecated"
+	LifecycleRetired    = "retired"
+
+	// ModeWarn logs findings without failing validation, ModeStrict fails validation.
+	ModeWarn   = "warn"
+	ModeStrict = "strict"
+
+	lifecycleStateKey  = "state"
+	lifecycleSunsetKey = "sunset"
+	labelDateFormat    = "2006-01-02"
+)
+
+// lifecycleOrder ranks states from earliest to latest in a segment's life.
+var lifecycleOrder = map[string]int{
+	LifecycleProposed:   0,
+	LifecycleActive:     1,
+	LifecycleDeprecated: 2,
+	LifecycleRetired:    3,
+}
+
+type LifecycleConfig struct {
+	Mode        string `yaml:"mode,omitempty"`
+	HideRetired bool   `yaml:"hide_retired"`
+}
+
+type LifecyclePlugin struct {
+	Config    *LifecycleConfig
+	Namespace string
+	now       func() time.Time
+}
+
+func NewLifecyclePlugin(config *LifecycleConfig, prefix string) *LifecyclePlugin {
+	return &LifecyclePlugin{
+		Config:    config,
+		Namespace: prefix + "lifecycle",
+		now:       time.Now,
+	}
+}
+
+func (p LifecyclePlugin) validateNamespaceLabels(labels map[string]string, ctx string, errs *[]error) {
+	for key, value := range labels {
+		switch key {
+		case lifecycleStateKey:
+			if _, ok := lifecycleOrder[value]; !ok {
+				*errs = append(*errs, fmt.Errorf("%s invalid lifecycle state %s", ctx, value))
+			}
+		case lifecycleStateKey + "_rationale":
+		case lifecycleSunsetKey:
+			if _, err := time.Parse(labelDateFormat, value); err != nil {
+				*errs = append(*errs, fmt.Errorf("%s invalid sunset date %s (expected YYYY-MM-DD)", ctx, value))
+			}
+		default:
+			*errs = append(*errs, fmt.Errorf("%s has unsupported lifecycle label key %s", ctx, key))
+		}
+	}
+}
+
+func (p LifecyclePlugin) ValidateLabels(seg *domain.Seg) PluginValidationResult {
+	result := PluginValidationResult{Valid: false, Errors: []error{}}
+
+	p.validateNamespaceLabels(seg.LabelNamespaces[p.Namespace], "segment "+seg.ID, &result.Errors)
+
+	for parentID, override := range seg.L1Overrides {
+		if len(override.LabelNamespaces[p.Namespace]) > 0 {
+			p.validateNamespaceLabels(override.LabelNamespaces[p.Namespace], fmt.Sprintf("segment %s l1_override[%s]", seg.ID, parentID), &result.Errors)
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// effectiveValue returns the override value for parentID if set, else the segment value.
+func (p LifecyclePlugin) effectiveValue(seg domain.Seg, parentID, key string) string {
+	if override, ok := seg.L1Overrides[parentID]; ok && parentID != "" {
+		if value, has := override.LabelNamespaces[p.Namespace][key]; has {
+			return value
+		}
+	}
+	return seg.LabelNamespaces[p.Namespace][key]
+}
+
+// State returns the lifecycle state of a segment for the given parent (empty for L1s).
+// Segments without a state label are active.
+func (p LifecyclePlugin) State(seg domain.Seg, parentID string) string {
+	if state := p.effectiveValue(seg, parentID, lifecycleStateKey); state != "" {
+		return state
+	}
+	return LifecycleActive
+}
+
+func (p LifecyclePlugin) sunsetPassed(seg domain.Seg, parentID string) (string, bool) {
+	value := p.effectiveValue(seg, parentID, lifecycleSunsetKey)
+	if value == "" {
+		return "", false
+	}
+	sunset, err := time.Parse(labelDateFormat, value)
+	if err != nil {
+		return "", false
+	}
+	return value, p.now().After(sunset) && p.State(seg, parentID) != LifecycleRetired
+}
+
+// ValidateTaxonomy reports deprecated or retired segments that still have children in an earlier
+// state, and segments whose sunset date has passed without being retired.
+// In warn mode (default) findings are logged and not returned.
+func (p LifecyclePlugin) ValidateTaxonomy(txy *domain.Taxonomy) []error {
+	var findings []error
+
+	for _, l1ID := range txy.SegIDs(1) {
+		if sunset, passed := p.sunsetPassed(txy.SegL1s[l1ID], ""); passed {
+			findings = append(findings, fmt.Errorf("L1 segment %s sunset date %s has passed but it is not retired", l1ID, sunset))
+		}
+	}
+
+	for level := 2; level <= txy.Depth(); level++ {
+		segs, parents := txy.Segs(level), txy.Segs(level-1)
+		for _, id := range txy.SegIDs(level) {
+			seg := segs[id]
+			for _, parentID := range seg.L1Parents {
+				parent, exists := parents[parentID]
+				if !exists {
+					continue
+				}
+				// Parents below L1 are compared by their own state, not per-parent overrides
+				parentState := p.State(parent, "")
+				childState := p.State(seg, parentID)
+				if lifecycleOrder[parentState] >= lifecycleOrder[LifecycleDeprecated] && lifecycleOrder[childState] < lifecycleOrder[parentState] {
+					findings = append(findings, fmt.Errorf("L%d segment %s is %s but child %s is %s", level-1, parentID, parentState, id, childState))
+				}
+				if sunset, passed := p.sunsetPassed(seg, parentID); passed {
+					findings = append(findings, fmt.Errorf("L%d segment %s in L%d %s sunset date %s has passed but it is not retired", level, id, level-1, parentID, sunset))
+				}
+			}
+		}
+	}
+
+	if p.Config.Mode != ModeStrict {
+		for _, finding := range findings {
+			o11y.Log.Printf("WARNING: lifecycle: %v", finding)
+		}
+		return nil
+	}
+	return findings
+}
+
+// Visible reports whether a segment is drawn in diagrams. Retired segments are hidden when HideRetired is set.
+func (p LifecyclePlugin) Visible(seg domain.Seg, parentID string) bool {
+	return !p.Config.HideRetired || p.State(seg, parentID) != LifecycleRetired
+}
+
+// DisplaySuffix marks deprecated and retired segments in diagram labels.
+func (p LifecyclePlugin) DisplaySuffix(seg domain.Seg) string {
+	switch p.State(seg, "") {
+	case LifecycleDeprecated:
+		return " [deprecated]"
+	case LifecycleRetired:
+		return " [retired]"
+	}
+	return ""
+}
+
+// GetEnabled always returns false: lifecycle state is set per segment,
+// inheriting a parent's deprecation would hide children that still need migrating.
+func (p LifecyclePlugin) GetEnabled() bool {
+	return false
+}
+
+func (p LifecyclePlugin) GetNamespace() string {
+	return p.Namespace
+}
+
+func (p LifecyclePlugin) GetImageData() []ImageGroupingData {
+	return []ImageGroupingData{}
+}
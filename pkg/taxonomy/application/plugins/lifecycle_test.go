@@ -0,0 +1,169 @@
+package plugins
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+const lifecycleTestNs = "bunsceal.plugin.lifecycle"
+
+// Helper to build lifecycle label
+func lifecycleLabel(key, value string) string {
+	return lifecycleTestNs + "/" + key + ":" + value
+}
+
+func newTestLifecyclePlugin(mode string) *LifecyclePlugin {
+	plugin := NewLifecyclePlugin(&LifecycleConfig{Mode: mode}, NsPrefix)
+	plugin.now = func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+	return plugin
+}
+
+func newLifecycleTestTaxonomy(parentLabels, childLabels []string) *domain.Taxonomy {
+	parent := newTestSeg("legacy", parentLabels)
+	child := domain.Seg{ID: "app", L1Parents: []string{"legacy"}, Labels: childLabels}
+	child.ParseLabels()
+	return &domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"legacy": *parent},
+		SegsL2s: map[string]domain.Seg{"app": child},
+	}
+}
+
+func TestLifecycleValidateLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		valid  bool
+	}{
+		{"Valid state", []string{lifecycleLabel("state", "deprecated")}, true},
+		{"Valid state with rationale and sunset", []string{
+			lifecycleLabel("state", "deprecated"),
+			lifecycleLabel("state_rationale", "Replaced by the new platform"),
+			lifecycleLabel("sunset", "2026-12-31"),
+		}, true},
+		{"Invalid state", []string{lifecycleLabel("state", "zombie")}, false},
+		{"Invalid sunset date", []string{lifecycleLabel("sunset", "31/12/2026")}, false},
+		{"Unknown key", []string{lifecycleLabel("owner", "team")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newTestLifecyclePlugin(ModeStrict)
+
+			result := plugin.ValidateLabels(newTestSeg("seg", tt.labels))
+
+			if result.Valid != tt.valid {
+				t.Errorf("Expected valid=%v, got %v (errors: %v)", tt.valid, result.Valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestLifecycleValidateTaxonomy(t *testing.T) {
+	t.Run("Reports deprecated L1 with active child in strict mode", func(t *testing.T) {
+		plugin := newTestLifecyclePlugin(ModeStrict)
+		txy := newLifecycleTestTaxonomy([]string{lifecycleLabel("state", "deprecated")}, nil)
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "child app is active") {
+			t.Errorf("Expected active child finding, got %v", errs)
+		}
+	})
+
+	t.Run("Passes when children are deprecated with their parent", func(t *testing.T) {
+		plugin := newTestLifecyclePlugin(ModeStrict)
+		txy := newLifecycleTestTaxonomy(
+			[]string{lifecycleLabel("state", "deprecated")},
+			[]string{lifecycleLabel("state", "deprecated")},
+		)
+
+		if errs := plugin.ValidateTaxonomy(txy); len(errs) > 0 {
+			t.Errorf("Expected no findings, got %v", errs)
+		}
+	})
+
+	t.Run("Reports passed sunset date on segments that are not retired", func(t *testing.T) {
+		plugin := newTestLifecyclePlugin(ModeStrict)
+		txy := newLifecycleTestTaxonomy(
+			[]string{lifecycleLabel("sunset", "2026-01-01")},
+			[]string{lifecycleLabel("state", "retired"), lifecycleLabel("sunset", "2026-01-01")},
+		)
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "L1 segment legacy sunset date") {
+			t.Errorf("Expected a single L1 sunset finding, got %v", errs)
+		}
+	})
+
+	t.Run("Warn mode logs findings without failing", func(t *testing.T) {
+		plugin := newTestLifecyclePlugin(ModeWarn)
+		txy := newLifecycleTestTaxonomy([]string{lifecycleLabel("state", "retired")}, nil)
+
+		if errs := plugin.ValidateTaxonomy(txy); len(errs) > 0 {
+			t.Errorf("Expected no errors in warn mode, got %v", errs)
+		}
+	})
+
+	t.Run("Reports deprecated L2 with active child below it", func(t *testing.T) {
+		plugin := newTestLifecyclePlugin(ModeStrict)
+		txy := newLifecycleTestTaxonomy(nil, []string{lifecycleLabel("state", "deprecated")})
+		api := domain.Seg{ID: "api", L1Parents: []string{"app"}}
+		api.ParseLabels()
+		txy.Levels = map[string]map[string]domain.Seg{"3": {"api": api}}
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "L2 segment app is deprecated but child api is active") {
+			t.Errorf("Expected active L3 child finding, got %v", errs)
+		}
+	})
+}
+
+func TestLifecyclePresentation(t *testing.T) {
+	t.Run("Hides retired segments when configured", func(t *testing.T) {
+		plugin := NewLifecyclePlugin(&LifecycleConfig{HideRetired: true}, NsPrefix)
+		retired := newTestSeg("old", []string{lifecycleLabel("state", "retired")})
+
+		if plugin.Visible(*retired, "") {
+			t.Error("Expected retired segment to be hidden")
+		}
+		if !plugin.Visible(*newTestSeg("new", nil), "") {
+			t.Error("Expected active segment to be visible")
+		}
+	})
+
+	t.Run("Shows retired segments with suffix by default", func(t *testing.T) {
+		plugin := NewLifecyclePlugin(&LifecycleConfig{}, NsPrefix)
+		retired := newTestSeg("old", []string{lifecycleLabel("state", "retired")})
+
+		if !plugin.Visible(*retired, "") {
+			t.Error("Expected retired segment to be visible")
+		}
+		if plugin.DisplaySuffix(*retired) != " [retired]" {
+			t.Errorf("Expected ' [retired]' suffix, got %q", plugin.DisplaySuffix(*retired))
+		}
+	})
+
+	t.Run("Override state applies per parent", func(t *testing.T) {
+		plugin := NewLifecyclePlugin(&LifecycleConfig{HideRetired: true}, NsPrefix)
+		seg := domain.Seg{
+			ID:        "app",
+			L1Parents: []string{"prod", "staging"},
+			L1Overrides: map[string]domain.L1Overrides{
+				"staging": {Labels: []string{lifecycleLabel("state", "retired")}},
+			},
+		}
+		seg.ParseLabels()
+
+		if plugin.Visible(seg, "staging") {
+			t.Error("Expected app to be hidden under staging")
+		}
+		if !plugin.Visible(seg, "prod") {
+			t.Error("Expected app to be visible under prod")
+		}
+	})
+}
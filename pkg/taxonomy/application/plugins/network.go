@@ -0,0 +1,321 @@
+package plugins
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+// NetworkCidrsKey is the label key holding a comma separated list of CIDR allocations.
+// On L1 segments the CIDRs are the supernets available to children.
+// On L2 segments (or their l1_overrides) the CIDRs are allocations carved from the parent supernets.
+const NetworkCidrsKey = "cidrs"
+
+type NetworkConfig struct {
+	RequireL1Supernets bool `yaml:"require_l1_supernets"`
+}
+
+type NetworkPlugin struct {
+	Config    *NetworkConfig
+	Namespace string
+}
+
+// NetworkAllocation is a single CIDR owned by a segment.
+// L2ID is empty for L1 supernets.
+type NetworkAllocation struct {
+	L1ID   string
+	L2ID   string
+	Prefix netip.Prefix
+}
+
+func (a NetworkAllocation) String() string {
+	if a.L2ID == "" {
+		return fmt.Sprintf("%s (L1 %s)", a.Prefix, a.L1ID)
+	}
+	return fmt.Sprintf("%s (L2 %s in L1 %s)", a.Prefix, a.L2ID, a.L1ID)
+}
+
+func NewNetworkPlugin(config *NetworkConfig, prefix string) *NetworkPlugin {
+	return &NetworkPlugin{
+		Config:    config,
+		Namespace: prefix + "network",
+	}
+}
+
+// ParseCIDRs parses a comma separated list of CIDRs.
+// Each entry must be a network address (no host bits set) and entries must not overlap.
+func ParseCIDRs(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, raw := range strings.Split(value, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", raw, err)
+		}
+		if prefix != prefix.Masked() {
+			return nil, fmt.Errorf("CIDR %s has host bits set, expected %s", prefix, prefix.Masked())
+		}
+		for _, existing := range prefixes {
+			if existing.Overlaps(prefix) {
+				return nil, fmt.Errorf("CIDR %s overlaps %s in the same list", prefix, existing)
+			}
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	if len(prefixes) == 0 {
+		return nil, fmt.Errorf("no CIDRs found in %q", value)
+	}
+	return prefixes, nil
+}
+
+func (p NetworkPlugin) validateNamespaceLabels(labels map[string]string, ctx string, errs *[]error) {
+	for key, value := range labels {
+		if key != NetworkCidrsKey {
+			*errs = append(*errs, fmt.Errorf("%s has unsupported network label key %s", ctx, key))
+			continue
+		}
+		if _, err := ParseCIDRs(value); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s %w", ctx, err))
+		}
+	}
+}
+
+func (p NetworkPlugin) ValidateLabels(seg *domain.Seg) PluginValidationResult {
+	result := PluginValidationResult{Valid: false, Errors: []error{}}
+
+	p.validateNamespaceLabels(seg.LabelNamespaces[p.Namespace], "segment "+seg.ID, &result.Errors)
+
+	for parentID, override := range seg.L1Overrides {
+		if len(override.LabelNamespaces[p.Namespace]) > 0 {
+			p.validateNamespaceLabels(override.LabelNamespaces[p.Namespace], fmt.Sprintf("segment %s l1_override[%s]", seg.ID, parentID), &result.Errors)
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// segmentCIDRs returns the CIDRs of an L1 segment, or the effective CIDRs of an L2 segment
+// for the given parent (override > child base value). Unparseable values are ignored as
+// they are reported by ValidateLabels.
+func (p NetworkPlugin) segmentCIDRs(seg domain.Seg, parentID string) []netip.Prefix {
+	value := seg.LabelNamespaces[p.Namespace][NetworkCidrsKey]
+	if override, ok := seg.L1Overrides[parentID]; ok && parentID != "" {
+		if overrideValue, has := override.LabelNamespaces[p.Namespace][NetworkCidrsKey]; has {
+			value = overrideValue
+		}
+	}
+	if value == "" {
+		return nil
+	}
+	prefixes, err := ParseCIDRs(value)
+	if err != nil {
+		return nil
+	}
+	return prefixes
+}
+
+// Allocations returns all L1 supernets and effective L2 allocations, sorted by prefix.
+func (p NetworkPlugin) Allocations(txy *domain.Taxonomy) []NetworkAllocation {
+	var allocs []NetworkAllocation
+	for l1ID, seg := range txy.SegL1s {
+		for _, prefix := range p.segmentCIDRs(seg, "") {
+			allocs = append(allocs, NetworkAllocation{L1ID: l1ID, Prefix: prefix})
+		}
+	}
+	for l2ID, seg := range txy.SegsL2s {
+		for _, l1ID := range seg.L1Parents {
+			for _, prefix := range p.segmentCIDRs(seg, l1ID) {
+				allocs = append(allocs, NetworkAllocation{L1ID: l1ID, L2ID: l2ID, Prefix: prefix})
+			}
+		}
+	}
+	sortAllocations(allocs)
+	return allocs
+}
+
+func sortAllocations(allocs []NetworkAllocation) {
+	sort.Slice(allocs, func(i, j int) bool {
+		if allocs[i].Prefix.Addr() != allocs[j].Prefix.Addr() {
+			return allocs[i].Prefix.Addr().Less(allocs[j].Prefix.Addr())
+		}
+		if allocs[i].Prefix.Bits() != allocs[j].Prefix.Bits() {
+			return allocs[i].Prefix.Bits() < allocs[j].Prefix.Bits()
+		}
+		if allocs[i].L1ID != allocs[j].L1ID {
+			return allocs[i].L1ID < allocs[j].L1ID
+		}
+		return allocs[i].L2ID < allocs[j].L2ID
+	})
+}
+
+// ValidateTaxonomy checks allocations across segments:
+// - L1 supernets must not overlap each other
+// - L2 allocations must be nested within their parent L1 supernets
+// - L2 allocations must not overlap allocations of any other (L1, L2) pair
+// - segments below L2 must not set allocations, which are only tracked for L1 and L2
+func (p NetworkPlugin) ValidateTaxonomy(txy *domain.Taxonomy) []error {
+	var errs []error
+	for _, seg := range segmentsBelowL2(txy, p.Namespace, NetworkCidrsKey) {
+		errs = append(errs, fmt.Errorf("%s sets %s, network allocations are only supported on L1 and L2 segments", seg, NetworkCidrsKey))
+	}
+	var l1Allocs, l2Allocs []NetworkAllocation
+	for _, alloc := range p.Allocations(txy) {
+		if alloc.L2ID == "" {
+			l1Allocs = append(l1Allocs, alloc)
+		} else {
+			l2Allocs = append(l2Allocs, alloc)
+		}
+	}
+
+	supernets := make(map[string][]netip.Prefix)
+	for _, alloc := range l1Allocs {
+		supernets[alloc.L1ID] = append(supernets[alloc.L1ID], alloc.Prefix)
+	}
+
+	if p.Config.RequireL1Supernets {
+		l1IDs := make([]string, 0, len(txy.SegL1s))
+		for l1ID := range txy.SegL1s {
+			l1IDs = append(l1IDs, l1ID)
+		}
+		sort.Strings(l1IDs)
+		for _, l1ID := range l1IDs {
+			if len(supernets[l1ID]) == 0 {
+				errs = append(errs, fmt.Errorf("L1 segment %s has no %s defined", l1ID, NetworkCidrsKey))
+			}
+		}
+	}
+
+	errs = append(errs, overlapErrors(l1Allocs)...)
+
+	for _, alloc := range l2Allocs {
+		parentNets, hasNets := supernets[alloc.L1ID]
+		if !hasNets {
+			errs = append(errs, fmt.Errorf("%s but parent L1 %s has no supernets", alloc, alloc.L1ID))
+			continue
+		}
+		if !containedInAny(alloc.Prefix, parentNets) {
+			errs = append(errs, fmt.Errorf("%s is not within the supernets of parent L1 %s", alloc, alloc.L1ID))
+		}
+	}
+
+	errs = append(errs, overlapErrors(l2Allocs)...)
+	return errs
+}
+
+// overlapErrors reports each pair of allocations owned by different segments that overlap.
+// Input must be sorted.
+func overlapErrors(allocs []NetworkAllocation) []error {
+	var errs []error
+	for i := 0; i < len(allocs); i++ {
+		for j := i + 1; j < len(allocs); j++ {
+			if allocs[i].L1ID == allocs[j].L1ID && allocs[i].L2ID == allocs[j].L2ID {
+				continue
+			}
+			if allocs[i].Prefix.Overlaps(allocs[j].Prefix) {
+				errs = append(errs, fmt.Errorf("%s overlaps %s", allocs[i], allocs[j]))
+			}
+		}
+	}
+	return errs
+}
+
+func containedInAny(prefix netip.Prefix, nets []netip.Prefix) bool {
+	for _, n := range nets {
+		if n.Bits() <= prefix.Bits() && n.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the most specific allocation containing addr.
+// An L2 allocation is returned in preference to the L1 supernet containing it.
+func (p NetworkPlugin) Lookup(txy *domain.Taxonomy, addr netip.Addr) (NetworkAllocation, bool) {
+	var best NetworkAllocation
+	found := false
+	for _, alloc := range p.Allocations(txy) {
+		if !alloc.Prefix.Contains(addr) {
+			continue
+		}
+		if !found || alloc.Prefix.Bits() > best.Prefix.Bits() {
+			best = alloc
+			found = true
+		}
+	}
+	return best, found
+}
+
+// FreeSpace returns, per L1, the parts of its supernets not allocated to any L2 segment.
+// Returned prefixes are the largest aligned blocks, sorted by address.
+func (p NetworkPlugin) FreeSpace(txy *domain.Taxonomy) map[string][]netip.Prefix {
+	supernets := make(map[string][]netip.Prefix)
+	used := make(map[string][]netip.Prefix)
+	for _, alloc := range p.Allocations(txy) {
+		if alloc.L2ID == "" {
+			supernets[alloc.L1ID] = append(supernets[alloc.L1ID], alloc.Prefix)
+		} else {
+			used[alloc.L1ID] = append(used[alloc.L1ID], alloc.Prefix)
+		}
+	}
+
+	free := make(map[string][]netip.Prefix)
+	for l1ID, nets := range supernets {
+		free[l1ID] = []netip.Prefix{}
+		for _, n := range nets {
+			free[l1ID] = append(free[l1ID], subtractPrefixes(n, used[l1ID])...)
+		}
+	}
+	return free
+}
+
+// subtractPrefixes returns the blocks of super not covered by any of used.
+// Splits super in halves until each half is either fully free or fully used.
+func subtractPrefixes(super netip.Prefix, used []netip.Prefix) []netip.Prefix {
+	overlapping := false
+	for _, u := range used {
+		if !u.Overlaps(super) {
+			continue
+		}
+		if u.Bits() <= super.Bits() {
+			return nil
+		}
+		overlapping = true
+	}
+	if !overlapping {
+		return []netip.Prefix{super}
+	}
+	lower, upper := splitPrefix(super)
+	return append(subtractPrefixes(lower, used), subtractPrefixes(upper, used)...)
+}
+
+// splitPrefix splits a prefix into its two halves.
+func splitPrefix(prefix netip.Prefix) (netip.Prefix, netip.Prefix) {
+	bits := prefix.Bits() + 1
+	lower := netip.PrefixFrom(prefix.Addr(), bits)
+	addr := prefix.Addr().AsSlice()
+	bytePos := (bits - 1) / 8
+	addr[bytePos] |= 0x80 >> ((bits - 1) % 8)
+	upperAddr, _ := netip.AddrFromSlice(addr)
+	return lower, netip.PrefixFrom(upperAddr, bits)
+}
+
+// GetEnabled always returns false: network allocations are never inherited,
+// a child copying its parent's supernet would claim the entire range.
+func (p NetworkPlugin) GetEnabled() bool {
+	return false
+}
+
+func (p NetworkPlugin) GetNamespace() string {
+	return p.Namespace
+}
+
+func (p NetworkPlugin) GetImageData() []ImageGroupingData {
+	return []ImageGroupingData{}
+}
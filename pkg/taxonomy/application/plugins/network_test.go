@@ -0,0 +1,282 @@
+package plugins
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+const networkTestNs = "bunsceal.plugin.network"
+
+// Helper to build network cidrs label
+func cidrsLabel(value string) string {
+	return networkTestNs + "/" + NetworkCidrsKey + ":" + value
+}
+
+// newNetworkTestTaxonomy builds prod (10.0.0.0/16) and dev (10.1.0.0/16) with app in both
+func newNetworkTestTaxonomy() *domain.Taxonomy {
+	prod := newTestSeg("prod", []string{cidrsLabel("10.0.0.0/16")})
+	prod.Level = "1"
+	dev := newTestSeg("dev", []string{cidrsLabel("10.1.0.0/16")})
+	dev.Level = "1"
+
+	app := domain.Seg{
+		ID:        "app",
+		Level:     "2",
+		L1Parents: []string{"prod", "dev"},
+		L1Overrides: map[string]domain.L1Overrides{
+			"prod": {Labels: []string{cidrsLabel("10.0.0.0/24,10.0.1.0/24")}},
+			"dev":  {Labels: []string{cidrsLabel("10.1.0.0/24")}},
+		},
+	}
+	app.ParseLabels()
+
+	return &domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"prod": *prod, "dev": *dev},
+		SegsL2s: map[string]domain.Seg{"app": app},
+	}
+}
+
+func TestParseCIDRs(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    int
+		expectError bool
+	}{
+		{"Single IPv4", "10.0.0.0/16", 1, false},
+		{"List with spaces", "10.0.0.0/24, 10.0.1.0/24", 2, false},
+		{"IPv6", "fd00::/48", 1, false},
+		{"Host bits set", "10.0.0.1/16", 0, true},
+		{"Not a CIDR", "not-a-cidr", 0, true},
+		{"Overlap within list", "10.0.0.0/16,10.0.1.0/24", 0, true},
+		{"Empty", " , ", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefixes, err := ParseCIDRs(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q, got nil", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(prefixes) != tt.expected {
+				t.Errorf("Expected %d prefixes, got %d", tt.expected, len(prefixes))
+			}
+		})
+	}
+}
+
+func TestNetworkValidateLabels(t *testing.T) {
+	plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+
+	t.Run("Accepts valid cidrs label", func(t *testing.T) {
+		seg := newTestSeg("prod", []string{cidrsLabel("10.0.0.0/16")})
+
+		result := plugin.ValidateLabels(seg)
+
+		if !result.Valid {
+			t.Errorf("Expected valid result, got errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("Rejects unknown key", func(t *testing.T) {
+		seg := newTestSeg("prod", []string{networkTestNs + "/vlan:12"})
+
+		result := plugin.ValidateLabels(seg)
+
+		if result.Valid {
+			t.Error("Expected validation to fail for unknown network key")
+		}
+	})
+
+	t.Run("Rejects invalid override cidr", func(t *testing.T) {
+		seg := domain.Seg{
+			ID:        "app",
+			L1Parents: []string{"prod"},
+			L1Overrides: map[string]domain.L1Overrides{
+				"prod": {Labels: []string{cidrsLabel("10.0.0.5/24")}},
+			},
+		}
+		seg.ParseLabels()
+
+		result := plugin.ValidateLabels(&seg)
+
+		if result.Valid {
+			t.Error("Expected validation to fail for override cidr with host bits")
+		}
+	})
+}
+
+func TestNetworkValidateTaxonomy(t *testing.T) {
+	t.Run("Passes for nested, non-overlapping allocations", func(t *testing.T) {
+		plugin := NewNetworkPlugin(&NetworkConfig{RequireL1Supernets: true}, NsPrefix)
+
+		errs := plugin.ValidateTaxonomy(newNetworkTestTaxonomy())
+
+		if len(errs) > 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when overlapping L1 supernets", func(t *testing.T) {
+		plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+		txy := newNetworkTestTaxonomy()
+		staging := newTestSeg("staging", []string{cidrsLabel("10.0.128.0/17")})
+		txy.SegL1s["staging"] = *staging
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "overlaps") {
+			t.Errorf("Expected a single overlap error, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when L2 allocation is outside parent supernet", func(t *testing.T) {
+		plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+		txy := newNetworkTestTaxonomy()
+		db := domain.Seg{ID: "db", L1Parents: []string{"prod"}, Labels: []string{cidrsLabel("192.168.0.0/24")}}
+		db.ParseLabels()
+		txy.SegsL2s["db"] = db
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "not within the supernets") {
+			t.Errorf("Expected a nesting error, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when L2 allocations overlap across segments", func(t *testing.T) {
+		plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+		txy := newNetworkTestTaxonomy()
+		db := domain.Seg{ID: "db", L1Parents: []string{"prod"}, Labels: []string{cidrsLabel("10.0.1.128/25")}}
+		db.ParseLabels()
+		txy.SegsL2s["db"] = db
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "overlaps") {
+			t.Errorf("Expected a single overlap error, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when L1 supernets required but missing", func(t *testing.T) {
+		plugin := NewNetworkPlugin(&NetworkConfig{RequireL1Supernets: true}, NsPrefix)
+		txy := newNetworkTestTaxonomy()
+		txy.SegL1s["staging"] = *newTestSeg("staging", []string{})
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "staging") {
+			t.Errorf("Expected missing supernet error for staging, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when L2 has cidrs but parent has none", func(t *testing.T) {
+		plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+		txy := newNetworkTestTaxonomy()
+		txy.SegL1s["staging"] = *newTestSeg("staging", []string{})
+		web := domain.Seg{ID: "web", L1Parents: []string{"staging"}, Labels: []string{cidrsLabel("172.16.0.0/24")}}
+		web.ParseLabels()
+		txy.SegsL2s["web"] = web
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "has no supernets") {
+			t.Errorf("Expected missing parent supernet error, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when a segment below L2 sets allocations", func(t *testing.T) {
+		plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+		txy := newNetworkTestTaxonomy()
+		api := domain.Seg{
+			ID:          "api",
+			L1Parents:   []string{"app"},
+			L1Overrides: map[string]domain.L1Overrides{"app": {Labels: []string{cidrsLabel("10.0.0.0/28")}}},
+		}
+		api.ParseLabels()
+		txy.Levels = map[string]map[string]domain.Seg{"3": {"api": api}}
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "L3 segment api sets cidrs") {
+			t.Errorf("Expected error for L3 api allocation, got %v", errs)
+		}
+	})
+}
+
+func TestNetworkLookup(t *testing.T) {
+	plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+	txy := newNetworkTestTaxonomy()
+
+	t.Run("Resolves to most specific L2 allocation", func(t *testing.T) {
+		alloc, found := plugin.Lookup(txy, netip.MustParseAddr("10.0.1.4"))
+
+		if !found {
+			t.Fatal("Expected address to be found")
+		}
+		if alloc.L1ID != "prod" || alloc.L2ID != "app" {
+			t.Errorf("Expected (prod, app), got (%s, %s)", alloc.L1ID, alloc.L2ID)
+		}
+	})
+
+	t.Run("Falls back to L1 supernet", func(t *testing.T) {
+		alloc, found := plugin.Lookup(txy, netip.MustParseAddr("10.1.200.1"))
+
+		if !found {
+			t.Fatal("Expected address to be found")
+		}
+		if alloc.L1ID != "dev" || alloc.L2ID != "" {
+			t.Errorf("Expected (dev, ''), got (%s, %s)", alloc.L1ID, alloc.L2ID)
+		}
+	})
+
+	t.Run("Returns false for unallocated address", func(t *testing.T) {
+		if _, found := plugin.Lookup(txy, netip.MustParseAddr("192.168.1.1")); found {
+			t.Error("Expected address not to be found")
+		}
+	})
+}
+
+func TestNetworkFreeSpace(t *testing.T) {
+	plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+
+	free := plugin.FreeSpace(newNetworkTestTaxonomy())
+
+	// prod 10.0.0.0/16 minus 10.0.0.0/23
+	expectedProd := []string{
+		"10.0.2.0/23", "10.0.4.0/22", "10.0.8.0/21", "10.0.16.0/20",
+		"10.0.32.0/19", "10.0.64.0/18", "10.0.128.0/17",
+	}
+	if len(free["prod"]) != len(expectedProd) {
+		t.Fatalf("Expected %d free blocks in prod, got %v", len(expectedProd), free["prod"])
+	}
+	for i, prefix := range free["prod"] {
+		if prefix.String() != expectedProd[i] {
+			t.Errorf("Expected free block %s, got %s", expectedProd[i], prefix)
+		}
+	}
+
+	if len(free["dev"]) != 8 || free["dev"][0].String() != "10.1.1.0/24" {
+		t.Errorf("Expected 8 free blocks in dev starting at 10.1.1.0/24, got %v", free["dev"])
+	}
+}
+
+func TestNetworkGetEnabled(t *testing.T) {
+	plugin := NewNetworkPlugin(&NetworkConfig{}, NsPrefix)
+
+	if plugin.GetEnabled() {
+		t.Error("Expected network plugin to never inherit labels")
+	}
+	if plugin.GetNamespace() != networkTestNs {
+		t.Errorf("Expected namespace %s, got %s", networkTestNs, plugin.GetNamespace())
+	}
+}
@@ -2,6 +2,7 @@ package plugins
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/kvql/bunsceal/pkg/domain"
 	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
@@ -21,6 +22,22 @@ func GetAllPluginSchemas() []schemaValidation.ExternalSchema {
 			JSON: ComplianceConfigSchema,
 			ID:   "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-compliance.json",
 		},
+		{
+			JSON: NetworkConfigSchema,
+			ID:   "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-network.json",
+		},
+		{
+			JSON: CloudConfigSchema,
+			ID:   "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-cloud.json",
+		},
+		{
+			JSON: LifecycleConfigSchema,
+			ID:   "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-lifecycle.json",
+		},
+		{
+			JSON: ReviewConfigSchema,
+			ID:   "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-review.json",
+		},
 		{
 			JSON: PluginsConfigSchema,
 			ID:   "https://github.com/kvql/bunsceal/pkg/config/schemas/plugins.json",
@@ -37,6 +54,15 @@ type PluginsCommonSettings struct {
 type ConfigPlugins struct {
 	Classifications *ClassificationsConfig `yaml:"classifications"`
 	Compliance      *ComplianceConfig      `yaml:"compliance"`
+	Network         *NetworkConfig         `yaml:"network"`
+	Cloud           *CloudConfig           `yaml:"cloud"`
+	Lifecycle       *LifecycleConfig       `yaml:"lifecycle"`
+	Review          *ReviewConfig          `yaml:"review"`
+}
+
+// HasAny reports whether at least one plugin is configured.
+func (c ConfigPlugins) HasAny() bool {
+	return c.Classifications != nil || c.Compliance != nil || c.Network != nil || c.Cloud != nil || c.Lifecycle != nil || c.Review != nil
 }
 
 type PluginValidationResult struct {
@@ -63,6 +89,25 @@ type RelationalValidator interface {
 	ValidateRelationship(parent, child *domain.Seg) []error
 }
 
+// TaxonomyValidator is implemented by plugins whose rules span multiple segments
+// (e.g. allocations that must not collide). Runs after inheritance.
+type TaxonomyValidator interface {
+	ValidateTaxonomy(txy *domain.Taxonomy) []error
+}
+
+// SegmentPresenter is implemented by plugins that change how segments appear in diagrams.
+// parentID is the L1 the L2 is drawn under, empty for L1 segments.
+type SegmentPresenter interface {
+	Visible(seg domain.Seg, parentID string) bool
+	DisplaySuffix(seg domain.Seg) string
+}
+
+// MetadataSchemaProvider is implemented by plugins that accept structured metadata under their namespace.
+// MetadataSchema returns the JSON schema for the value of the plugin's metadata namespace.
+type MetadataSchemaProvider interface {
+	MetadataSchema() string
+}
+
 type Plugins map[string]Plugin
 
 func (p Plugins) LoadPlugins(cfg ConfigPlugins) error {
@@ -72,6 +117,18 @@ func (p Plugins) LoadPlugins(cfg ConfigPlugins) error {
 	if cfg.Compliance != nil {
 		p["compliance"] = NewCompliancePlugin(cfg.Compliance, NsPrefix)
 	}
+	if cfg.Network != nil {
+		p["network"] = NewNetworkPlugin(cfg.Network, NsPrefix)
+	}
+	if cfg.Cloud != nil {
+		p["cloud"] = NewCloudPlugin(cfg.Cloud, NsPrefix)
+	}
+	if cfg.Lifecycle != nil {
+		p["lifecycle"] = NewLifecyclePlugin(cfg.Lifecycle, NsPrefix)
+	}
+	if cfg.Review != nil {
+		p["review"] = NewReviewPlugin(cfg.Review, NsPrefix)
+	}
 	return nil
 }
 
@@ -79,10 +136,15 @@ func (p Plugins) LoadPlugins(cfg ConfigPlugins) error {
 // Skips segments with no labels for efficiency.
 // Returns a flat list of all validation errors across all segments and plugins.
 func (p Plugins) ValidateAllSegments(l1s, l2s map[string]domain.Seg) []error {
-	var allErrors []error
+	allErrors := p.ValidateLevelSegments("L1", l1s)
+	return append(allErrors, p.ValidateLevelSegments("L2", l2s)...)
+}
 
-	// Validate L1 segments
-	for id, seg := range l1s {
+// ValidateLevelSegments validates the segments of one level against all loaded plugins.
+// Skips segments with no labels for efficiency.
+func (p Plugins) ValidateLevelSegments(level string, segs map[string]domain.Seg) []error {
+	var allErrors []error
+	for id, seg := range segs {
 		// Skip segments with no labels (Option 3 optimization)
 		if len(seg.Labels) == 0 {
 			continue
@@ -92,58 +154,108 @@ func (p Plugins) ValidateAllSegments(l1s, l2s map[string]domain.Seg) []error {
 			result := plugin.ValidateLabels(&seg)
 			if !result.Valid {
 				for _, err := range result.Errors {
-					allErrors = append(allErrors, fmt.Errorf("L1 segment %s (plugin %s): %w", id, pluginName, err))
+					allErrors = append(allErrors, fmt.Errorf("%s segment %s (plugin %s): %w", level, id, pluginName, err))
 				}
 			}
 		}
 	}
+	return allErrors
+}
 
-	// Validate L2 segments
-	for id, seg := range l2s {
-		// Skip segments with no labels (Option 3 optimization)
-		if len(seg.Labels) == 0 {
-			continue
-		}
-
-		for pluginName, plugin := range p {
-			result := plugin.ValidateLabels(&seg)
-			if !result.Valid {
-				for _, err := range result.Errors {
-					allErrors = append(allErrors, fmt.Errorf("L2 segment %s (plugin %s): %w", id, pluginName, err))
-				}
+// ValidateLevelMetadata validates the metadata of one level's segments and their overrides.
+// Values under a plugin namespace must match the plugin's metadata schema; a plugin namespace
+// whose plugin is not loaded or takes no metadata is an error. Other namespaces are not checked.
+func (p Plugins) ValidateLevelMetadata(level string, segs map[string]domain.Seg) []error {
+	var allErrors []error
+	schemas := make(map[string]*schemaValidation.ValueSchema)
+	validate := func(ctx string, metadata domain.Metadata) {
+		for ns, value := range metadata {
+			if !strings.HasPrefix(ns, NsPrefix) {
+				continue
+			}
+			pluginName := strings.TrimPrefix(ns, NsPrefix)
+			schema, err := p.metadataSchema(pluginName, schemas)
+			if err == nil {
+				err = schema.Validate(value)
+			}
+			if err != nil {
+				allErrors = append(allErrors, fmt.Errorf("%s segment %s metadata (plugin %s): %w", level, ctx, pluginName, err))
 			}
 		}
 	}
 
+	for id, seg := range segs {
+		validate(id, seg.Metadata)
+		for parentID, override := range seg.L1Overrides {
+			validate(fmt.Sprintf("%s override[%s]", id, parentID), override.Metadata)
+		}
+	}
 	return allErrors
 }
 
-func (p Plugins) ApplyPluginInheritanceAndValidate(parent domain.Seg, child *domain.Seg) []error {
-	var allErrors []error
-
-	for _, plugin := range p {
-		if !plugin.GetEnabled() {
-			continue
-		}
+// metadataSchema returns the compiled metadata schema of a plugin, caching it in schemas.
+func (p Plugins) metadataSchema(pluginName string, schemas map[string]*schemaValidation.ValueSchema) (*schemaValidation.ValueSchema, error) {
+	if schema, ok := schemas[pluginName]; ok {
+		return schema, nil
+	}
+	provider, ok := p[pluginName].(MetadataSchemaProvider)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s is not configured or does not accept metadata", pluginName)
+	}
+	schema, err := schemaValidation.NewValueSchema("https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-"+pluginName+"-metadata.json", provider.MetadataSchema())
+	if err != nil {
+		return nil, err
+	}
+	schemas[pluginName] = schema
+	return schema, nil
+}
 
-		ns := plugin.GetNamespace()
+// ApplyPluginInheritanceAndValidate inherits labels from a single parent and validates the relationship.
+// Use ApplyPluginInheritance directly when a child has several parents so strategies can see all of them.
+func (p Plugins) ApplyPluginInheritanceAndValidate(parent domain.Seg, child *domain.Seg) []error {
+	allErrors := p.ApplyPluginInheritance([]domain.Seg{parent}, child)
+	return append(allErrors, p.ValidateRelationships(parent, child)...)
+}
 
-		if child.LabelNamespaces[ns] == nil {
-			child.LabelNamespaces[ns] = make(map[string]string)
+// segmentsBelowL2 returns the segments below L2 that set a label in namespace ns, in their labels or
+// an override, sorted by level and ID. An empty key matches any key of the namespace.
+// Used by plugins whose outputs only place segments at L1 or L2.
+func segmentsBelowL2(txy *domain.Taxonomy, ns, key string) []string {
+	sets := func(labels map[string]string) bool {
+		if key == "" {
+			return len(labels) > 0
 		}
-
-		// Inherit from parent when child is missing values (overrides are NOT inheritance sources)
-		if parentLabels, pHas := parent.LabelNamespaces[ns]; pHas {
-			for k, v := range parentLabels {
-				if _, childHas := child.LabelNamespaces[ns][k]; !childHas {
-					child.LabelNamespaces[ns][k] = v
-					child.ParsedLabels[ns+"/"+k] = v
-				}
+		_, has := labels[key]
+		return has
+	}
+	var found []string
+	for level := 3; level <= txy.Depth(); level++ {
+		segs := txy.Segs(level)
+		for _, id := range txy.SegIDs(level) {
+			seg := segs[id]
+			labelled := sets(seg.LabelNamespaces[ns])
+			for _, override := range seg.L1Overrides {
+				labelled = labelled || sets(override.LabelNamespaces[ns])
+			}
+			if labelled {
+				found = append(found, fmt.Sprintf("L%d segment %s", level, id))
 			}
 		}
+	}
+	return found
+}
 
-		if validator, ok := plugin.(RelationalValidator); ok {
-			allErrors = append(allErrors, validator.ValidateRelationship(&parent, child)...)
+// ValidateTaxonomy runs taxonomy-wide validation for every plugin implementing TaxonomyValidator.
+// Must be called after inheritance so effective per-parent values are available.
+func (p Plugins) ValidateTaxonomy(txy *domain.Taxonomy) []error {
+	var allErrors []error
+	for pluginName, plugin := range p {
+		validator, ok := plugin.(TaxonomyValidator)
+		if !ok {
+			continue
+		}
+		for _, err := range validator.ValidateTaxonomy(txy) {
+			allErrors = append(allErrors, fmt.Errorf("plugin %s: %w", pluginName, err))
 		}
 	}
 	return allErrors
@@ -1,9 +1,11 @@
 package plugins
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/kvql/bunsceal/pkg/domain"
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadPlugins(t *testing.T) {
@@ -273,3 +275,105 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestValidateTaxonomy(t *testing.T) {
+	t.Run("Collects errors from taxonomy validators only", func(t *testing.T) {
+		plugs := make(Plugins)
+		err := plugs.LoadPlugins(ConfigPlugins{
+			Classifications: newTestConfig(true, 10),
+			Network:         &NetworkConfig{RequireL1Supernets: true},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		txy := newNetworkTestTaxonomy()
+		txy.SegL1s["staging"] = *newTestSeg("staging", []string{})
+
+		errs := plugs.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got %v", errs)
+		}
+		if !strings.Contains(errs[0].Error(), "plugin network") {
+			t.Errorf("Expected error to be prefixed with plugin name, got %v", errs[0])
+		}
+	})
+}
+
+func TestValidateLevelMetadata(t *testing.T) {
+	plugs := make(Plugins)
+	if err := plugs.LoadPlugins(ConfigPlugins{
+		Classifications: newTestConfig(true, 10),
+		Review:          &ReviewConfig{MaxAgeDays: 365},
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	segWithMetadata := func(t *testing.T, metadata string) map[string]domain.Seg {
+		seg := domain.Seg{ID: "prod"}
+		if err := yaml.Unmarshal([]byte(metadata), &seg.Metadata); err != nil {
+			t.Fatal(err)
+		}
+		return map[string]domain.Seg{"prod": seg}
+	}
+
+	t.Run("Accepts metadata matching plugin schemas and free-form namespaces", func(t *testing.T) {
+		segs := segWithMetadata(t, `
+bunsceal.plugin.classifications:
+  sensitivity:
+    rationale: Holds customer records
+    links: [https://wiki.example.com/dpia]
+bunsceal.plugin.review:
+  contacts:
+    - name: Security Team
+      email: security@example.com
+team:
+  oncall: [alice, bob]
+  size: 4
+`)
+
+		if errs := plugs.ValidateLevelMetadata("L1", segs); len(errs) != 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Rejects values not matching the plugin schema", func(t *testing.T) {
+		segs := segWithMetadata(t, `
+bunsceal.plugin.classifications:
+  criticality:
+    rationale: Not a configured key
+`)
+
+		errs := plugs.ValidateLevelMetadata("L1", segs)
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "plugin classifications") {
+			t.Errorf("Expected classifications schema error, got %v", errs)
+		}
+	})
+
+	t.Run("Rejects plugin namespaces without a metadata schema", func(t *testing.T) {
+		segs := segWithMetadata(t, "bunsceal.plugin.network:\n  note: unsupported\n")
+
+		if errs := plugs.ValidateLevelMetadata("L1", segs); len(errs) != 1 {
+			t.Errorf("Expected error for network metadata, got %v", errs)
+		}
+	})
+
+	t.Run("Validates override metadata", func(t *testing.T) {
+		segs := map[string]domain.Seg{"app": {ID: "app", L1Overrides: map[string]domain.L1Overrides{
+			"prod": {Metadata: domain.Metadata{"bunsceal.plugin.review": map[string]interface{}{"owner": "x"}}},
+		}}}
+
+		errs := plugs.ValidateLevelMetadata("L2", segs)
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "app override[prod]") {
+			t.Errorf("Expected override error, got %v", errs)
+		}
+	})
+}
+
+func TestConfigPluginsHasAny(t *testing.T) {
+	if (ConfigPlugins{}).HasAny() {
+		t.Error("Expected HasAny to be false for empty config")
+	}
+	if !(ConfigPlugins{Network: &NetworkConfig{}}).HasAny() {
+		t.Error("Expected HasAny to be true when network configured")
+	}
+}
@@ -0,0 +1,286 @@
+package plugins
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+const (
+	reviewedByKey = "reviewed_by"
+	reviewedAtKey = "reviewed_at"
+
+	defaultReportWithinDays = 30
+)
+
+// ReviewConfig configures the review/attestation plugin.
+// Keys restricts which keys may carry their own attestation (e.g. classification keys); empty allows any.
+type ReviewConfig struct {
+	MaxAgeDays       int      `yaml:"max_age_days"`
+	Mode             string   `yaml:"mode,omitempty"`
+	RequireL1        bool     `yaml:"require_l1"`
+	ReportWithinDays int      `yaml:"report_within_days,omitempty"`
+	Keys             []string `yaml:"keys,omitempty"`
+}
+
+type ReviewPlugin struct {
+	Config    *ReviewConfig
+	Namespace string
+	keys      map[string]bool
+	now       func() time.Time
+}
+
+// Attestation is a single reviewed_by/reviewed_at pair on the segment ID of Level. Key is empty for a
+// segment-level attestation, Parent is the segment of the level above whose override it was recorded
+// in, empty when it was recorded on the segment itself.
+type Attestation struct {
+	Level      int       `json:"level"`
+	ID         string    `json:"id"`
+	Parent     string    `json:"parent,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	ReviewedBy string    `json:"reviewed_by"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (a Attestation) String() string {
+	target := fmt.Sprintf("L%d %s", a.Level, a.ID)
+	if a.Parent != "" {
+		target += fmt.Sprintf(" in L%d %s", a.Level-1, a.Parent)
+	}
+	if a.Key != "" {
+		target += " key " + a.Key
+	}
+	return fmt.Sprintf("%s reviewed by %s on %s, expires %s",
+		target, a.ReviewedBy, a.ReviewedAt.Format(labelDateFormat), a.ExpiresAt.Format(labelDateFormat))
+}
+
+func NewReviewPlugin(config *ReviewConfig, prefix string) *ReviewPlugin {
+	keys := make(map[string]bool)
+	for _, key := range config.Keys {
+		keys[key] = true
+	}
+	return &ReviewPlugin{
+		Config:    config,
+		Namespace: prefix + "review",
+		keys:      keys,
+		now:       time.Now,
+	}
+}
+
+// splitReviewKey splits a label key into the attested key (empty for segment level) and the field.
+func splitReviewKey(labelKey string) (key, field string, ok bool) {
+	for _, f := range []string{reviewedByKey, reviewedAtKey} {
+		if labelKey == f {
+			return "", f, true
+		}
+		if strings.HasSuffix(labelKey, "_"+f) {
+			return strings.TrimSuffix(labelKey, "_"+f), f, true
+		}
+	}
+	return "", "", false
+}
+
+// validateNamespaceLabels pairs reviewed_by with reviewed_at, following the classification rationale pairing.
+func (p ReviewPlugin) validateNamespaceLabels(labels map[string]string, ctx string, errs *[]error) {
+	for labelKey, value := range labels {
+		key, field, ok := splitReviewKey(labelKey)
+		if !ok {
+			*errs = append(*errs, fmt.Errorf("%s has unsupported review label key %s", ctx, labelKey))
+			continue
+		}
+		if key != "" && len(p.keys) > 0 && !p.keys[key] {
+			*errs = append(*errs, fmt.Errorf("%s has review label for unknown key %s", ctx, key))
+		}
+		prefix := ""
+		if key != "" {
+			prefix = key + "_"
+		}
+
+		switch field {
+		case reviewedByKey:
+			if _, hasAt := labels[prefix+reviewedAtKey]; !hasAt {
+				*errs = append(*errs, fmt.Errorf("%s has %s but missing %s", ctx, labelKey, prefix+reviewedAtKey))
+			}
+		case reviewedAtKey:
+			if _, hasBy := labels[prefix+reviewedByKey]; !hasBy {
+				*errs = append(*errs, fmt.Errorf("%s has %s but missing %s", ctx, labelKey, prefix+reviewedByKey))
+			}
+			if _, err := time.Parse(labelDateFormat, value); err != nil {
+				*errs = append(*errs, fmt.Errorf("%s invalid %s date %s (expected YYYY-MM-DD)", ctx, labelKey, value))
+			}
+		}
+	}
+}
+
+// MetadataSchema accepts review contacts and links to review records.
+func (p ReviewPlugin) MetadataSchema() string {
+	return ReviewMetadataSchema
+}
+
+func (p ReviewPlugin) ValidateLabels(seg *domain.Seg) PluginValidationResult {
+	result := PluginValidationResult{Valid: false, Errors: []error{}}
+
+	p.validateNamespaceLabels(seg.LabelNamespaces[p.Namespace], "segment "+seg.ID, &result.Errors)
+
+	for parentID, override := range seg.L1Overrides {
+		if len(override.LabelNamespaces[p.Namespace]) > 0 {
+			p.validateNamespaceLabels(override.LabelNamespaces[p.Namespace], fmt.Sprintf("segment %s l1_override[%s]", seg.ID, parentID), &result.Errors)
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// segmentAttestations returns the attestations of a segment: its own pairs once with no parent, unless
+// every parent overrides the key, then each override pair for the parent it applies in.
+// Unparseable pairs are skipped; ValidateLabels reports them.
+func (p ReviewPlugin) segmentAttestations(seg domain.Seg, level int) []Attestation {
+	overridden := make(map[string]int)
+	var attestations []Attestation
+	for _, parentID := range seg.L1Parents {
+		override := p.attestations(seg.L1Overrides[parentID].LabelNamespaces[p.Namespace], level, seg.ID, parentID)
+		for _, a := range override {
+			overridden[a.Key]++
+		}
+		attestations = append(attestations, override...)
+	}
+	for _, a := range p.attestations(seg.LabelNamespaces[p.Namespace], level, seg.ID, "") {
+		if len(seg.L1Parents) == 0 || overridden[a.Key] < len(seg.L1Parents) {
+			attestations = append(attestations, a)
+		}
+	}
+	return attestations
+}
+
+// attestations returns the reviewed_by/reviewed_at pairs in labels as attestations of id in parentID.
+func (p ReviewPlugin) attestations(labels map[string]string, level int, id, parentID string) []Attestation {
+	maxAge := time.Duration(p.Config.MaxAgeDays) * 24 * time.Hour
+	var attestations []Attestation
+	for labelKey, at := range labels {
+		key, field, ok := splitReviewKey(labelKey)
+		if !ok || field != reviewedAtKey {
+			continue
+		}
+		reviewedAt, err := time.Parse(labelDateFormat, at)
+		if err != nil {
+			continue
+		}
+		prefix := ""
+		if key != "" {
+			prefix = key + "_"
+		}
+		attestations = append(attestations, Attestation{
+			Level:      level,
+			ID:         id,
+			Parent:     parentID,
+			Key:        key,
+			ReviewedBy: labels[prefix+reviewedByKey],
+			ReviewedAt: reviewedAt,
+			ExpiresAt:  reviewedAt.Add(maxAge),
+		})
+	}
+	return attestations
+}
+
+// Attestations returns every attestation in the taxonomy, sorted by expiry then segment.
+// Per-parent rows are only returned for overrides, a segment's own attestation is listed once.
+func (p ReviewPlugin) Attestations(txy *domain.Taxonomy) []Attestation {
+	var all []Attestation
+	for level := 1; level <= txy.Depth(); level++ {
+		for _, seg := range txy.Segs(level) {
+			all = append(all, p.segmentAttestations(seg, level)...)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if !a.ExpiresAt.Equal(b.ExpiresAt) {
+			return a.ExpiresAt.Before(b.ExpiresAt)
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		if a.Parent != b.Parent {
+			return a.Parent < b.Parent
+		}
+		return a.Key < b.Key
+	})
+	return all
+}
+
+// UpcomingExpiries returns attestations that have expired or expire within ReportWithinDays (default 30).
+func (p ReviewPlugin) UpcomingExpiries(txy *domain.Taxonomy) []Attestation {
+	within := p.Config.ReportWithinDays
+	if within == 0 {
+		within = defaultReportWithinDays
+	}
+	cutoff := p.now().Add(time.Duration(within) * 24 * time.Hour)
+
+	var upcoming []Attestation
+	for _, a := range p.Attestations(txy) {
+		if a.ExpiresAt.Before(cutoff) {
+			upcoming = append(upcoming, a)
+		}
+	}
+	return upcoming
+}
+
+// ValidateTaxonomy reports stale attestations, attestations dated in the future and,
+// when RequireL1 is set, L1 segments without a segment-level attestation.
+// In warn mode (default) findings are logged and not returned.
+func (p ReviewPlugin) ValidateTaxonomy(txy *domain.Taxonomy) []error {
+	var findings []error
+	now := p.now()
+
+	if p.Config.RequireL1 {
+		l1IDs := make([]string, 0, len(txy.SegL1s))
+		for l1ID := range txy.SegL1s {
+			l1IDs = append(l1IDs, l1ID)
+		}
+		sort.Strings(l1IDs)
+		for _, l1ID := range l1IDs {
+			if _, has := txy.SegL1s[l1ID].LabelNamespaces[p.Namespace][reviewedAtKey]; !has {
+				findings = append(findings, fmt.Errorf("L1 segment %s has no %s attestation", l1ID, reviewedAtKey))
+			}
+		}
+	}
+
+	for _, a := range p.Attestations(txy) {
+		switch {
+		case a.ReviewedAt.After(now):
+			findings = append(findings, fmt.Errorf("attestation for %s is dated in the future", a))
+		case a.ExpiresAt.Before(now):
+			findings = append(findings, fmt.Errorf("attestation for %s is stale (max age %d days)", a, p.Config.MaxAgeDays))
+		}
+	}
+
+	if p.Config.Mode != ModeStrict {
+		for _, finding := range findings {
+			o11y.Log.Printf("WARNING: review: %v", finding)
+		}
+		return nil
+	}
+	return findings
+}
+
+// GetEnabled always returns false: an attestation covers the segment it is recorded on,
+// copying it to children would attest values nobody reviewed there.
+func (p ReviewPlugin) GetEnabled() bool {
+	return false
+}
+
+func (p ReviewPlugin) GetNamespace() string {
+	return p.Namespace
+}
+
+func (p ReviewPlugin) GetImageData() []ImageGroupingData {
+	return []ImageGroupingData{}
+}
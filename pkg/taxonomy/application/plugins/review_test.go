@@ -0,0 +1,218 @@
+package plugins
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+const reviewTestNs = "bunsceal.plugin.review"
+
+// Helper to build review label
+func reviewLabel(key, value string) string {
+	return reviewTestNs + "/" + key + ":" + value
+}
+
+func newTestReviewPlugin(mode string, keys []string) *ReviewPlugin {
+	plugin := NewReviewPlugin(&ReviewConfig{MaxAgeDays: 365, Mode: mode, Keys: keys}, NsPrefix)
+	plugin.now = func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+	return plugin
+}
+
+// newReviewTestTaxonomy builds an L1 reviewed on l1Date and an L2 with a sensitivity attestation on l2Date
+func newReviewTestTaxonomy(l1Date, l2Date string) *domain.Taxonomy {
+	prod := newTestSeg("prod", []string{
+		reviewLabel("reviewed_by", "security@example.com"),
+		reviewLabel("reviewed_at", l1Date),
+	})
+	app := domain.Seg{ID: "app", L1Parents: []string{"prod"}, Labels: []string{
+		reviewLabel("sensitivity_reviewed_by", "data-owner@example.com"),
+		reviewLabel("sensitivity_reviewed_at", l2Date),
+	}}
+	app.ParseLabels()
+	return &domain.Taxonomy{
+		SegL1s:  map[string]domain.Seg{"prod": *prod},
+		SegsL2s: map[string]domain.Seg{"app": app},
+	}
+}
+
+func TestReviewValidateLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		labels []string
+		valid  bool
+	}{
+		{"Valid segment attestation", nil, []string{
+			reviewLabel("reviewed_by", "security@example.com"),
+			reviewLabel("reviewed_at", "2026-01-15"),
+		}, true},
+		{"Valid key attestation", []string{"sensitivity"}, []string{
+			reviewLabel("sensitivity_reviewed_by", "alice"),
+			reviewLabel("sensitivity_reviewed_at", "2026-01-15"),
+		}, true},
+		{"Missing reviewed_at", nil, []string{reviewLabel("reviewed_by", "alice")}, false},
+		{"Missing reviewed_by", nil, []string{reviewLabel("reviewed_at", "2026-01-15")}, false},
+		{"Invalid date", nil, []string{
+			reviewLabel("reviewed_by", "alice"),
+			reviewLabel("reviewed_at", "15/01/2026"),
+		}, false},
+		{"Key not configured", []string{"sensitivity"}, []string{
+			reviewLabel("criticality_reviewed_by", "alice"),
+			reviewLabel("criticality_reviewed_at", "2026-01-15"),
+		}, false},
+		{"Unknown key", nil, []string{reviewLabel("approved", "yes")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newTestReviewPlugin(ModeStrict, tt.keys)
+
+			result := plugin.ValidateLabels(newTestSeg("seg", tt.labels))
+
+			if result.Valid != tt.valid {
+				t.Errorf("Expected valid=%v, got %v (errors: %v)", tt.valid, result.Valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestReviewValidateTaxonomy(t *testing.T) {
+	t.Run("Passes with fresh attestations", func(t *testing.T) {
+		plugin := newTestReviewPlugin(ModeStrict, nil)
+
+		errs := plugin.ValidateTaxonomy(newReviewTestTaxonomy("2026-01-15", "2026-03-01"))
+
+		if len(errs) > 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Reports stale key attestation in strict mode", func(t *testing.T) {
+		plugin := newTestReviewPlugin(ModeStrict, nil)
+
+		errs := plugin.ValidateTaxonomy(newReviewTestTaxonomy("2026-01-15", "2025-01-01"))
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "key sensitivity") || !strings.Contains(errs[0].Error(), "stale") {
+			t.Errorf("Expected stale sensitivity attestation error, got %v", errs)
+		}
+	})
+
+	t.Run("Reports attestation dated in the future", func(t *testing.T) {
+		plugin := newTestReviewPlugin(ModeStrict, nil)
+
+		errs := plugin.ValidateTaxonomy(newReviewTestTaxonomy("2027-01-01", "2026-03-01"))
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "future") {
+			t.Errorf("Expected future date error, got %v", errs)
+		}
+	})
+
+	t.Run("Warn mode returns no errors", func(t *testing.T) {
+		plugin := newTestReviewPlugin(ModeWarn, nil)
+
+		errs := plugin.ValidateTaxonomy(newReviewTestTaxonomy("2020-01-01", "2020-01-01"))
+
+		if len(errs) > 0 {
+			t.Errorf("Expected warnings only, got %v", errs)
+		}
+	})
+
+	t.Run("Requires L1 attestation when configured", func(t *testing.T) {
+		plugin := newTestReviewPlugin(ModeStrict, nil)
+		plugin.Config.RequireL1 = true
+		txy := newReviewTestTaxonomy("2026-01-15", "2026-03-01")
+		txy.SegL1s["dev"] = *newTestSeg("dev", nil)
+
+		errs := plugin.ValidateTaxonomy(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "dev") {
+			t.Errorf("Expected missing attestation error for dev, got %v", errs)
+		}
+	})
+}
+
+func TestReviewUpcomingExpiries(t *testing.T) {
+	plugin := newTestReviewPlugin(ModeWarn, nil)
+	// L1 expires 2026-06-16 (within 30 days), L2 expires 2027-03-01
+	txy := newReviewTestTaxonomy("2025-06-16", "2026-03-01")
+
+	upcoming := plugin.UpcomingExpiries(txy)
+
+	if len(upcoming) != 1 {
+		t.Fatalf("Expected 1 upcoming expiry, got %v", upcoming)
+	}
+	if upcoming[0].Level != 1 || upcoming[0].ID != "prod" || upcoming[0].Parent != "" || upcoming[0].Key != "" {
+		t.Errorf("Expected segment attestation for L1 prod, got %+v", upcoming[0])
+	}
+	if upcoming[0].ReviewedBy != "security@example.com" {
+		t.Errorf("Expected reviewer security@example.com, got %s", upcoming[0].ReviewedBy)
+	}
+
+	t.Run("Override attestation replaces base for its parent", func(t *testing.T) {
+		app := txy.SegsL2s["app"]
+		override := domain.L1Overrides{Labels: []string{
+			reviewLabel("sensitivity_reviewed_by", "prod-owner"),
+			reviewLabel("sensitivity_reviewed_at", "2025-06-20"),
+		}}
+		override.ParseLabels()
+		app.L1Overrides = map[string]domain.L1Overrides{"prod": override}
+		txy.SegsL2s["app"] = app
+
+		upcoming := plugin.UpcomingExpiries(txy)
+
+		if len(upcoming) != 2 || upcoming[1].ReviewedBy != "prod-owner" {
+			t.Errorf("Expected override attestation to be reported, got %v", upcoming)
+		}
+	})
+}
+
+func TestReviewAttestations(t *testing.T) {
+	plugin := newTestReviewPlugin(ModeWarn, nil)
+	txy := newReviewTestTaxonomy("2026-01-01", "2026-03-01")
+	app := txy.SegsL2s["app"]
+	app.L1Parents = []string{"prod", "staging"}
+	override := domain.L1Overrides{Labels: []string{
+		reviewLabel("sensitivity_reviewed_by", "staging-owner"),
+		reviewLabel("sensitivity_reviewed_at", "2026-02-01"),
+	}}
+	override.ParseLabels()
+	app.L1Overrides = map[string]domain.L1Overrides{"staging": override}
+	txy.SegsL2s["app"] = app
+
+	var rows []string
+	for _, a := range plugin.Attestations(txy) {
+		if a.Level == 2 {
+			rows = append(rows, a.Parent+":"+a.ReviewedBy)
+		}
+	}
+
+	// Base attestation once for the parents without an override, then the staging override
+	if strings.Join(rows, ",") != "staging:staging-owner,:data-owner@example.com" {
+		t.Errorf("Expected one base and one override attestation, got %v", rows)
+	}
+
+	t.Run("Base attestation is dropped when every parent overrides it", func(t *testing.T) {
+		app.L1Parents = []string{"staging"}
+		txy.SegsL2s["app"] = app
+
+		for _, a := range plugin.Attestations(txy) {
+			if a.Level == 2 && a.Parent == "" {
+				t.Errorf("Expected no base attestation, got %v", a)
+			}
+		}
+	})
+}
+
+func TestReviewGetEnabled(t *testing.T) {
+	plugin := newTestReviewPlugin(ModeWarn, nil)
+
+	if plugin.GetEnabled() {
+		t.Error("Expected review plugin to never inherit labels")
+	}
+	if plugin.GetNamespace() != reviewTestNs {
+		t.Errorf("Expected namespace %s, got %s", reviewTestNs, plugin.GetNamespace())
+	}
+}
@@ -6,6 +6,14 @@ const ClassificationsConfigSchema = `{
 	"$id": "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-classifications.json",
 	"title": "Classifications Plugin Configuration",
 	"type": "object",
+	"$defs": {
+		"inheritance": {
+			"type": "string",
+			"description": "How a child without its own value inherits from its parents",
+			"enum": ["fill", "strictest", "none", "required"],
+			"default": "fill"
+		}
+	},
 	"additionalProperties": false,
 	"properties": {
 		"common_settings": {
@@ -34,7 +42,8 @@ const ClassificationsConfigSchema = `{
 					"order": {
 						"type": "array",
 						"items": { "type": "string" }
-					}
+					},
+					"inheritance": { "$ref": "#/$defs/inheritance" }
 				}
 			}
 		}
@@ -68,9 +77,127 @@ const ComplianceConfigSchema = `{
 				"properties": {
 					"name": { "type": "string", "minLength": 1 },
 					"description": { "type": "string" },
-					"requirements_link": { "type": "string", "format": "uri" }
+					"requirements_link": { "type": "string", "format": "uri" },
+					"inheritance": { "$ref": "./plugin-classifications.json#/$defs/inheritance" },
+					"allowed_resource_types": {
+						"type": "array",
+						"description": "Resource types (e.g. Terraform types) segments in scope may deploy",
+						"items": { "type": "string", "minLength": 1 },
+						"uniqueItems": true
+					}
+				}
+			}
+		}
+	}
+}`
+
+// NetworkConfigSchema defines the JSON schema for network plugin config
+const NetworkConfigSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-network.json",
+	"title": "Network Plugin Configuration",
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"require_l1_supernets": { "type": "boolean" }
+	}
+}`
+
+// CloudConfigSchema defines the JSON schema for cloud account mapping plugin config
+const CloudConfigSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-cloud.json",
+	"title": "Cloud Account Mapping Plugin Configuration",
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"providers": {
+			"type": "array",
+			"items": { "type": "string", "enum": ["aws", "gcp", "azure"] },
+			"uniqueItems": true
+		}
+	}
+}`
+
+// LifecycleConfigSchema defines the JSON schema for lifecycle plugin config
+const LifecycleConfigSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-lifecycle.json",
+	"title": "Lifecycle Plugin Configuration",
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"mode": { "type": "string", "enum": ["warn", "strict"], "default": "warn" },
+		"hide_retired": { "type": "boolean" }
+	}
+}`
+
+// ReviewConfigSchema defines the JSON schema for review plugin config
+const ReviewConfigSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://github.com/kvql/bunsceal/pkg/config/schemas/plugin-review.json",
+	"title": "Review Plugin Configuration",
+	"type": "object",
+	"additionalProperties": false,
+	"required": ["max_age_days"],
+	"properties": {
+		"max_age_days": { "type": "integer", "minimum": 1 },
+		"mode": { "type": "string", "enum": ["warn", "strict"], "default": "warn" },
+		"require_l1": { "type": "boolean" },
+		"report_within_days": { "type": "integer", "minimum": 1, "default": 30 },
+		"keys": {
+			"type": "array",
+			"items": { "type": "string", "pattern": "^[a-z0-9_-]+$" },
+			"uniqueItems": true
+		}
+	}
+}`
+
+// ClassificationsMetadataSchema defines the JSON schema for classification metadata on segments:
+// supporting documents per classification key. %s is replaced with the configured keys.
+const ClassificationsMetadataSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Classifications Segment Metadata",
+	"type": "object",
+	"propertyNames": { "enum": %s },
+	"additionalProperties": {
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"rationale": { "type": "string", "minLength": 1 },
+			"links": {
+				"type": "array",
+				"items": { "type": "string", "format": "uri" },
+				"uniqueItems": true
+			}
+		}
+	}
+}`
+
+// ReviewMetadataSchema defines the JSON schema for review metadata on segments: who to contact for a review.
+const ReviewMetadataSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Review Segment Metadata",
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"contacts": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"required": ["name"],
+				"additionalProperties": false,
+				"properties": {
+					"name": { "type": "string", "minLength": 1 },
+					"email": { "type": "string", "format": "email" },
+					"role": { "type": "string" }
 				}
 			}
+		},
+		"links": {
+			"type": "array",
+			"items": { "type": "string", "format": "uri" },
+			"uniqueItems": true
 		}
 	}
 }`
@@ -84,6 +211,10 @@ const PluginsConfigSchema = `{
 	"additionalProperties": false,
 	"properties": {
 		"classifications": { "$ref": "./plugin-classifications.json" },
-		"compliance": { "$ref": "./plugin-compliance.json" }
+		"compliance": { "$ref": "./plugin-compliance.json" },
+		"network": { "$ref": "./plugin-network.json" },
+		"cloud": { "$ref": "./plugin-cloud.json" },
+		"lifecycle": { "$ref": "./plugin-lifecycle.json" },
+		"review": { "$ref": "./plugin-review.json" }
 	}
 }`
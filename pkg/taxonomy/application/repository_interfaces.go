@@ -9,3 +9,30 @@ type SegRepository interface {
 	// Does NOT perform business rule validation (uniqueness, etc)
 	LoadLevel(level string) ([]domain.Seg, error)
 }
+
+// FlowRepository defines the contract for loading Flow data from any source
+type FlowRepository interface {
+	// LoadFlows loads all flows, returning an empty slice when the source defines none
+	// Does NOT validate flows against the taxonomy
+	LoadFlows() ([]domain.Flow, error)
+}
+
+// GroupRepository defines the contract for loading L0 group definitions from any source
+type GroupRepository interface {
+	// LoadGroups loads all groups, returning an empty slice when the source defines none
+	LoadGroups() ([]domain.Group, error)
+}
+
+// TemplateRepository defines the contract for loading segment templates from any source
+type TemplateRepository interface {
+	// LoadTemplates loads all templates, returning an empty slice when the source defines none
+	LoadTemplates() ([]domain.Template, error)
+}
+
+// TaxonomyRepository is a source of segments, flows, groups and templates
+type TaxonomyRepository interface {
+	SegRepository
+	FlowRepository
+	GroupRepository
+	TemplateRepository
+}
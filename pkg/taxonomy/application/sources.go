@@ -0,0 +1,190 @@
+package application
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
+	"github.com/kvql/bunsceal/pkg/o11y"
+	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
+)
+
+// sourceRepository is the repository of one source, which may leave levels to other sources.
+type sourceRepository interface {
+	TaxonomyRepository
+	HasLevel(level string) bool
+}
+
+// taxonomySource is a configured source with its repository.
+type taxonomySource struct {
+	config     infrastructure.ConfigSource
+	repository sourceRepository
+}
+
+// SourcesRepository merges the taxonomies of several sources, such as a central repository owning the L1s
+// and team repositories owning their L2s. Each source may only define the levels and ID prefixes it is
+// configured with, and an ID defined by two sources is a conflict naming both.
+type SourcesRepository struct {
+	sources  []taxonomySource
+	cleanups []func()
+}
+
+// NewSourcesRepository creates the repository of every source in cfg.Repository.Sources.
+// Close must be called once loading is done to remove extracted tarballs.
+func NewSourcesRepository(schemaValidator *schemaValidation.SchemaValidator, cfg configdomain.Config) (*SourcesRepository, error) {
+	r := &SourcesRepository{}
+	for _, source := range cfg.Repository.Sources {
+		fsConfig := cfg.FsRepository
+		fsConfig.TaxonomyDir = source.Path
+
+		var repository sourceRepository
+		var err error
+		switch source.Type {
+		case infrastructure.SourceTypeFs:
+			repository = infrastructure.NewFileSegRepository(schemaValidator, fsConfig)
+		case infrastructure.SourceTypeGit:
+			ref := source.Ref
+			if ref == "" {
+				ref = "HEAD"
+			}
+			// The taxonomy dir is also the directory git runs in, so it must not be relative to it
+			var dir string
+			if dir, err = filepath.Abs(source.Path); err != nil {
+				break
+			}
+			fsConfig.TaxonomyDir = dir
+			repository, err = infrastructure.NewGitSegRepository(schemaValidator, fsConfig, dir, ref)
+		case infrastructure.SourceTypeFile:
+			repository, err = infrastructure.NewSingleFileSegRepository(schemaValidator, infrastructure.ConfigRepository{Path: source.Path}, cfg.FsRepository.Depth())
+		case infrastructure.SourceTypeTarball:
+			var cleanup func()
+			repository, cleanup, err = infrastructure.NewTarballSegRepository(schemaValidator, fsConfig, source.Path)
+			if err == nil {
+				r.cleanups = append(r.cleanups, cleanup)
+			}
+		default:
+			err = fmt.Errorf("unknown source type %q", source.Type)
+		}
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+		r.sources = append(r.sources, taxonomySource{config: source, repository: repository})
+	}
+	return r, nil
+}
+
+// Close removes the files extracted for the sources.
+func (r *SourcesRepository) Close() {
+	for _, cleanup := range r.cleanups {
+		cleanup()
+	}
+	r.cleanups = nil
+}
+
+// LoadLevel loads the segments of the level from every source defining it.
+// Sources are checked against their ownership rules and for IDs defined by another source.
+func (r *SourcesRepository) LoadLevel(level string) ([]domain.Seg, error) {
+	var segList []domain.Seg
+	var errs []error
+	owners := make(map[string]string)
+	for _, source := range r.sources {
+		if !source.repository.HasLevel(level) {
+			continue
+		}
+		segs, err := source.repository.LoadLevel(level)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("source %s: %w", source.config.Name, err))
+			continue
+		}
+		for _, seg := range segs {
+			if err := checkOwnership(source.config, level, seg.ID); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := claimID(owners, "L"+level+" segment", seg.ID, source.config.Name); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			segList = append(segList, seg)
+		}
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			o11y.Log.Println(err)
+		}
+		return nil, fmt.Errorf("failed to load L%s from %d source(s) with %d error(s)", level, len(r.sources), len(errs))
+	}
+	return segList, nil
+}
+
+// LoadFlows loads the flows of every source.
+func (r *SourcesRepository) LoadFlows() ([]domain.Flow, error) {
+	return loadFromSources(r, "flow", TaxonomyRepository.LoadFlows, func(flow domain.Flow) string { return flow.ID })
+}
+
+// LoadGroups loads the groups of every source.
+func (r *SourcesRepository) LoadGroups() ([]domain.Group, error) {
+	return loadFromSources(r, "group", TaxonomyRepository.LoadGroups, func(group domain.Group) string { return group.ID })
+}
+
+// LoadTemplates loads the templates of every source.
+func (r *SourcesRepository) LoadTemplates() ([]domain.Template, error) {
+	return loadFromSources(r, "template", TaxonomyRepository.LoadTemplates, func(template domain.Template) string { return template.ID })
+}
+
+// loadFromSources loads items of a kind from every source, reporting IDs defined by more than one source.
+func loadFromSources[T any](r *SourcesRepository, kind string, load func(TaxonomyRepository) ([]T, error), id func(T) string) ([]T, error) {
+	var items []T
+	var errs []error
+	owners := make(map[string]string)
+	for _, source := range r.sources {
+		loaded, err := load(source.repository)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("source %s: %w", source.config.Name, err))
+			continue
+		}
+		for _, item := range loaded {
+			if err := claimID(owners, kind, id(item), source.config.Name); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			o11y.Log.Println(err)
+		}
+		return nil, fmt.Errorf("failed to load %ss from %d source(s) with %d error(s)", kind, len(r.sources), len(errs))
+	}
+	return items, nil
+}
+
+// claimID records source as the owner of id, failing when another source already defines it.
+func claimID(owners map[string]string, kind, id, source string) error {
+	if owner, exists := owners[id]; exists {
+		return fmt.Errorf("%s %s is defined by sources %s and %s", kind, id, owner, source)
+	}
+	owners[id] = source
+	return nil
+}
+
+// checkOwnership checks the source may define a segment of the level with the ID.
+func checkOwnership(source infrastructure.ConfigSource, level, id string) error {
+	if len(source.Levels) > 0 {
+		n, _ := strconv.Atoi(level)
+		if !slices.Contains(source.Levels, n) {
+			return fmt.Errorf("source %s defines L%s segment %s but may only define levels %v", source.Name, level, id, source.Levels)
+		}
+	}
+	if len(source.IDPrefixes) > 0 && !slices.ContainsFunc(source.IDPrefixes, func(prefix string) bool { return strings.HasPrefix(id, prefix) }) {
+		return fmt.Errorf("source %s defines L%s segment %s but may only define IDs starting with %s", source.Name, level, id, strings.Join(source.IDPrefixes, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package application
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
+	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
+)
+
+const testSourceDescription = "Description long enough for the schema, which asks for at least seventy characters."
+
+func sourceSegDoc(kind, id, extra string) string {
+	return "---\nkind: " + kind + "\nname: " + id + "\nid: " + id + "\ndescription: " + testSourceDescription + "\n" + extra
+}
+
+// writeSourceFile writes a single-file source and returns its path.
+func writeSourceFile(t *testing.T, docs ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "taxonomy.yaml")
+	if err := os.WriteFile(path, []byte(strings.Join(docs, "")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeSourceTarball writes a .tar.gz archive of a taxonomy directory with the given files and returns its path.
+func writeSourceTarball(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "taxonomy.tar.gz")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(file)
+	archive := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, closer := range []interface{ Close() error }{archive, gz, file} {
+		if err := closer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+// writeSourceGitRepo commits the files to a new git repository at dir.
+func writeSourceGitRepo(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if !infrastructure.CheckGit() {
+		t.Skip("git not available")
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "taxonomy"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+}
+
+func newTestSourcesRepository(t *testing.T, sources ...infrastructure.ConfigSource) *SourcesRepository {
+	t.Helper()
+	cfg := configdomain.DefaultConfig()
+	cfg.Repository = infrastructure.ConfigRepository{Type: infrastructure.RepositoryTypeSources, Sources: sources}
+	repository, err := NewSourcesRepository(schemaValidation.MustCreateValidator(t), cfg)
+	if err != nil {
+		t.Fatalf("NewSourcesRepository: unexpected error: %v", err)
+	}
+	t.Cleanup(repository.Close)
+	return repository
+}
+
+func TestSourcesRepository(t *testing.T) {
+	central := infrastructure.ConfigSource{
+		Name:   "central",
+		Type:   infrastructure.SourceTypeFile,
+		Path:   writeSourceFile(t, sourceSegDoc("L1", "prod", ""), sourceSegDoc("L1", "staging", "")),
+		Levels: []int{1},
+	}
+	team := infrastructure.ConfigSource{
+		Name: "payments",
+		Type: infrastructure.SourceTypeTarball,
+		Path: writeSourceTarball(t, map[string]string{
+			"segments/payments-api.yaml": "name: API\nid: payments-api\ndescription: " + testSourceDescription + "\nl1_parents: [prod]\n",
+		}),
+		Levels:     []int{2},
+		IDPrefixes: []string{"payments-"},
+	}
+
+	t.Run("Merges levels from their sources", func(t *testing.T) {
+		repository := newTestSourcesRepository(t, central, team)
+
+		l1s, err := repository.LoadLevel("1")
+		if err != nil || len(l1s) != 2 {
+			t.Errorf("Expected 2 L1 segments from central, got %+v, %v", l1s, err)
+		}
+		l2s, err := repository.LoadLevel("2")
+		if err != nil || len(l2s) != 1 || l2s[0].ID != "payments-api" {
+			t.Errorf("Expected payments-api from the tarball, got %+v, %v", l2s, err)
+		}
+	})
+
+	t.Run("Rejects segments of levels the source doesn't own", func(t *testing.T) {
+		rogue := team
+		rogue.Type = infrastructure.SourceTypeFile
+		rogue.Path = writeSourceFile(t, sourceSegDoc("L1", "payments-prod", ""))
+		repository := newTestSourcesRepository(t, central, rogue)
+
+		if _, err := repository.LoadLevel("1"); err == nil {
+			t.Error("Expected ownership error for L1 defined by an L2 source")
+		}
+	})
+
+	t.Run("Rejects IDs without an allowed prefix", func(t *testing.T) {
+		rogue := team
+		rogue.Type = infrastructure.SourceTypeFile
+		rogue.Path = writeSourceFile(t, sourceSegDoc("L2", "checkout", "l1_parents: [prod]\n"))
+		repository := newTestSourcesRepository(t, central, rogue)
+
+		if _, err := repository.LoadLevel("2"); err == nil {
+			t.Error("Expected ownership error for ID without prefix")
+		}
+	})
+
+	t.Run("Reports IDs defined by two sources", func(t *testing.T) {
+		duplicate := infrastructure.ConfigSource{
+			Name: "legacy",
+			Type: infrastructure.SourceTypeFile,
+			Path: writeSourceFile(t, sourceSegDoc("L1", "prod", "")),
+		}
+		repository := newTestSourcesRepository(t, central, duplicate)
+
+		_, err := repository.LoadLevel("1")
+		if err == nil {
+			t.Fatal("Expected conflict error for prod")
+		}
+		if err := claimID(map[string]string{"prod": "central"}, "L1 segment", "prod", "legacy"); err == nil ||
+			err.Error() != "L1 segment prod is defined by sources central and legacy" {
+			t.Errorf("Expected conflict naming both sources, got %v", err)
+		}
+	})
+
+	t.Run("Loads git sources with a relative path", func(t *testing.T) {
+		root := t.TempDir()
+		writeSourceGitRepo(t, filepath.Join(root, "payments"), map[string]string{
+			"taxonomy/segments/payments-api.yaml": "name: API\nid: payments-api\ndescription: " + testSourceDescription + "\nl1_parents: [prod]\n",
+		})
+		if err := os.MkdirAll(filepath.Join(root, "central"), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		validator := schemaValidation.MustCreateValidator(t)
+		originalWd, err := os.Getwd()
+		if err != nil {
+			t.Fatalf("Failed to get working directory: %v", err)
+		}
+		if err := os.Chdir(filepath.Join(root, "central")); err != nil {
+			t.Fatalf("Failed to change to central: %v", err)
+		}
+
+		gitTeam := team
+		gitTeam.Type = infrastructure.SourceTypeGit
+		gitTeam.Path = filepath.Join("..", "payments", "taxonomy")
+		cfg := configdomain.DefaultConfig()
+		cfg.Repository = infrastructure.ConfigRepository{Type: infrastructure.RepositoryTypeSources, Sources: []infrastructure.ConfigSource{central, gitTeam}}
+		repository, err := NewSourcesRepository(validator, cfg)
+		// The path is resolved when the source is created, later loads don't depend on the working directory
+		if chdirErr := os.Chdir(originalWd); chdirErr != nil {
+			t.Fatalf("Failed to restore working directory: %v", chdirErr)
+		}
+		if err != nil {
+			t.Fatalf("NewSourcesRepository: unexpected error: %v", err)
+		}
+		defer repository.Close()
+
+		l2s, err := repository.LoadLevel("2")
+		if err != nil || len(l2s) != 1 || l2s[0].ID != "payments-api" {
+			t.Errorf("Expected payments-api from the git source, got %+v, %v", l2s, err)
+		}
+	})
+
+	t.Run("Rejects unknown source types", func(t *testing.T) {
+		cfg := configdomain.DefaultConfig()
+		cfg.Repository.Sources = []infrastructure.ConfigSource{{Name: "svn", Type: "svn", Path: "."}}
+		if _, err := NewSourcesRepository(schemaValidation.MustCreateValidator(t), cfg); err == nil {
+			t.Error("Expected error for unknown source type")
+		}
+	})
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
 	"github.com/kvql/bunsceal/pkg/domain"
@@ -18,38 +19,132 @@ import (
 // Fills in missing data based on inheritance rules.
 // cfg parameter provides terminology configuration for directory resolution.
 func LoadTaxonomy(cfg configdomain.Config) (domain.Taxonomy, error) {
-	txy := domain.Taxonomy{
-		ApiVersion: domain.ApiVersion,
+	schemaValidator, err := newTaxonomySchemaValidator(cfg)
+	if err != nil {
+		return domain.Taxonomy{}, err
 	}
-	var err error
+	switch cfg.Repository.Type {
+	case infrastructure.RepositoryTypeFile:
+		repository, err := infrastructure.NewSingleFileSegRepository(schemaValidator, cfg.Repository, cfg.FsRepository.Depth())
+		if err != nil {
+			o11y.Log.Println(err)
+			return domain.Taxonomy{}, err
+		}
+		return LoadTaxonomyFrom(cfg, repository)
+	case infrastructure.RepositoryTypeSources:
+		repository, err := NewSourcesRepository(schemaValidator, cfg)
+		if err != nil {
+			o11y.Log.Println(err)
+			return domain.Taxonomy{}, err
+		}
+		defer repository.Close()
+		return LoadTaxonomyFrom(cfg, repository)
+	}
+	return LoadTaxonomyFrom(cfg, infrastructure.NewFileSegRepository(schemaValidator, cfg.FsRepository))
+}
 
-	// Load L1 segments using configured directory name
+// LoadTaxonomyAtRef loads the taxonomy as it was at a git ref of the repository containing repoDir,
+// without checking it out. cfg is used as is, only the taxonomy files (or the single file of a file
+// repository) are read from the ref.
+// Returns the resolved commit hash with the taxonomy.
+func LoadTaxonomyAtRef(cfg configdomain.Config, repoDir, ref string) (domain.Taxonomy, string, error) {
+	schemaValidator, err := newTaxonomySchemaValidator(cfg)
+	if err != nil {
+		return domain.Taxonomy{}, "", err
+	}
+	if cfg.Repository.Type == infrastructure.RepositoryTypeSources {
+		return domain.Taxonomy{}, "", errors.New("a multi-source taxonomy can't be loaded at a git ref, set the ref of each git source instead")
+	}
+	repository, err := infrastructure.NewGitSegRepository(schemaValidator, cfg.FsRepository, repoDir, ref)
+	if err != nil {
+		o11y.Log.Println(err)
+		return domain.Taxonomy{}, "", err
+	}
+	if cfg.Repository.Type == infrastructure.RepositoryTypeFile {
+		if cfg.Repository.Path == infrastructure.StdinPath {
+			return domain.Taxonomy{}, "", errors.New("a taxonomy read from stdin has no git history")
+		}
+		data, err := repository.ReadFile(cfg.Repository.Path)
+		if err != nil {
+			o11y.Log.Println(err)
+			return domain.Taxonomy{}, "", err
+		}
+		name := repository.Commit()[:7] + ":" + cfg.Repository.Path
+		singleFile, err := infrastructure.NewSingleFileSegRepositoryFromData(schemaValidator, data, name, cfg.FsRepository.Depth())
+		if err != nil {
+			o11y.Log.Println(err)
+			return domain.Taxonomy{}, "", err
+		}
+		txy, err := LoadTaxonomyFrom(cfg, singleFile)
+		return txy, repository.Commit(), err
+	}
+	txy, err := LoadTaxonomyFrom(cfg, repository)
+	return txy, repository.Commit(), err
+}
 
+func newTaxonomySchemaValidator(cfg configdomain.Config) (*schemaValidation.SchemaValidator, error) {
 	schemaValidator, err := schemaValidation.NewSchemaValidator(cfg.SchemaPath, schemaValidation.SchemaBaseURL)
 	if err != nil {
 		o11y.Log.Printf("Error initialising schema validator: %v\n", err)
-		return domain.Taxonomy{}, errors.New("failed to initialise schema validator")
+		return nil, errors.New("failed to initialise schema validator")
 	}
+	return schemaValidator, nil
+}
 
-	// Load L2 segments using configured directory name
-	FsRepository := infrastructure.NewFileSegRepository(schemaValidator, cfg.FsRepository)
-	FsService := NewSegService(FsRepository)
+// LoadTaxonomyFrom loads, resolves and validates the taxonomy held by repository.
+func LoadTaxonomyFrom(cfg configdomain.Config, repository TaxonomyRepository) (domain.Taxonomy, error) {
+	txy := domain.Taxonomy{
+		ApiVersion: domain.ApiVersion,
+	}
+	var err error
 
-	txy.SegL1s, err = FsService.LoadLevel("1")
+	segService := NewSegService(repository)
+
+	txy.SegL1s, err = segService.LoadLevel("1")
 	if err != nil {
 		o11y.Log.Printf("Error loading L1 files. %s", err)
 		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
 	}
 
-	txy.SegsL2s, err = FsService.LoadLevel("2")
+	txy.SegsL2s, err = segService.LoadLevel("2")
 	if err != nil {
 		o11y.Log.Printf("Error loading L2 files. %s", err)
 		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
 	}
 
+	for level := 3; level <= cfg.FsRepository.Depth(); level++ {
+		segs, err := segService.LoadLevel(strconv.Itoa(level))
+		if err != nil {
+			o11y.Log.Printf("Error loading L%d files. %s", level, err)
+			return domain.Taxonomy{}, errors.New("invalid Taxonomy")
+		}
+		if txy.Levels == nil {
+			txy.Levels = make(map[string]map[string]domain.Seg)
+		}
+		txy.Levels[strconv.Itoa(level)] = segs
+	}
+
+	txy.Flows, err = LoadFlows(repository)
+	if err != nil {
+		o11y.Log.Printf("Error loading flow files. %s", err)
+		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
+	}
+
+	txy.Groups, err = LoadGroups(repository)
+	if err != nil {
+		o11y.Log.Printf("Error loading group files. %s", err)
+		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
+	}
+
+	txy.Templates, err = LoadTemplates(repository)
+	if err != nil {
+		o11y.Log.Printf("Error loading template files. %s", err)
+		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
+	}
+
 	// Load plugins from config
 	pluginsList := make(plugins.Plugins)
-	if cfg.Plugins.Classifications != nil || cfg.Plugins.Compliance != nil {
+	if cfg.Plugins.HasAny() {
 		err = pluginsList.LoadPlugins(cfg.Plugins)
 		if err != nil {
 			o11y.Log.Printf("error loading plugins: %s", err)
@@ -76,6 +171,34 @@ func LoadTaxonomy(cfg configdomain.Config) (domain.Taxonomy, error) {
 		o11y.Log.Println("Taxonomy is invalid: Validate L2 definitions after inheritance")
 		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
 	}
+	if valid, _ = validation.ValidateLevelDefinitions(&txy); !valid {
+		o11y.Log.Println("Taxonomy is invalid: Validate definitions of levels below L2 after inheritance")
+		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
+	}
+
+	// Validate plugin rules spanning multiple segments
+	if err = ValidatePluginTaxonomy(&txy, pluginsList); err != nil {
+		o11y.Log.Println("Taxonomy is invalid: plugin taxonomy validation failed")
+		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
+	}
+
+	// Validate flows reference existing segments
+	if errs := validation.ValidateFlows(&txy); len(errs) > 0 {
+		for _, err := range errs {
+			o11y.Log.Println(err)
+		}
+		o11y.Log.Println("Taxonomy is invalid: flow validation failed")
+		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
+	}
+
+	// Validate L1 groups exist
+	if errs := validation.ValidateGroups(&txy); len(errs) > 0 {
+		for _, err := range errs {
+			o11y.Log.Println(err)
+		}
+		o11y.Log.Println("Taxonomy is invalid: group validation failed")
+		return domain.Taxonomy{}, errors.New("invalid Taxonomy")
+	}
 
 	// Validate business logic rules
 	valid = ValidateCoreLogic(&txy, cfg, pluginsList)
@@ -107,13 +230,26 @@ func ValidateCoreLogic(txy *domain.Taxonomy, cfg configdomain.Config, pluginMap
 
 // ValidatePluginLabels validates all segment labels against loaded plugins.
 // Must be called BEFORE ApplyInheritance to catch malformed labels (missing rationale pairs).
-// L1 segments must have all classification definitions. L2/overrides only need valid pairs.
+// L1 segments must have all classification definitions. Lower levels/overrides only need valid pairs.
 func ValidatePluginLabels(txy *domain.Taxonomy, pluginsList plugins.Plugins) error {
 	if pluginsList == nil {
 		return nil
 	}
 
 	errs := pluginsList.ValidateAllSegments(txy.SegL1s, txy.SegsL2s)
+	for level := 3; level <= txy.Depth(); level++ {
+		errs = append(errs, pluginsList.ValidateLevelSegments(fmt.Sprintf("L%d", level), txy.Segs(level))...)
+	}
+	for level := 1; level <= txy.Depth(); level++ {
+		errs = append(errs, pluginsList.ValidateLevelMetadata(fmt.Sprintf("L%d", level), txy.Segs(level))...)
+	}
+	// Templates are checked as segments so values they contribute are validated once, at the source
+	templateSegs, err := templatesAsSegs(txy.Templates)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, pluginsList.ValidateLevelSegments("Template", templateSegs)...)
+	errs = append(errs, pluginsList.ValidateLevelMetadata("Template", templateSegs)...)
 	if len(errs) > 0 {
 		for _, err := range errs {
 			o11y.Log.Println(err)
@@ -123,3 +259,34 @@ func ValidatePluginLabels(txy *domain.Taxonomy, pluginsList plugins.Plugins) err
 
 	return nil
 }
+
+// templatesAsSegs returns each template's own labels, overrides and metadata as a segment for validation.
+func templatesAsSegs(templates map[string]domain.Template) (map[string]domain.Seg, error) {
+	segs := make(map[string]domain.Seg, len(templates))
+	for id, template := range templates {
+		seg := domain.Seg{ID: id, Labels: template.Labels, L1Overrides: template.Overrides, Metadata: template.Metadata}
+		if err := seg.ParseLabels(); err != nil {
+			return nil, fmt.Errorf("template %w", err)
+		}
+		segs[id] = seg
+	}
+	return segs, nil
+}
+
+// ValidatePluginTaxonomy runs plugin validation that spans multiple segments.
+// Must be called AFTER ApplyInheritance so effective per-parent labels are resolved.
+func ValidatePluginTaxonomy(txy *domain.Taxonomy, pluginsList plugins.Plugins) error {
+	if pluginsList == nil {
+		return nil
+	}
+
+	errs := pluginsList.ValidateTaxonomy(txy)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			o11y.Log.Println(err)
+		}
+		return fmt.Errorf("plugin taxonomy validation failed with %d error(s)", len(errs))
+	}
+
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	"github.com/kvql/bunsceal/pkg/config"
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
 	"github.com/kvql/bunsceal/pkg/domain"
 	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+	"github.com/kvql/bunsceal/pkg/taxonomy/infrastructure"
 )
 
 func TestValidatePluginLabels(t *testing.T) {
@@ -119,3 +121,70 @@ func TestLoadExampleTaxonomy(t *testing.T) {
 		}
 	})
 }
+
+type stubTaxonomyRepository struct {
+	stubFlowRepository
+	stubGroupRepository
+	stubTemplateRepository
+	levels map[string][]domain.Seg
+}
+
+func (r stubTaxonomyRepository) LoadLevel(level string) ([]domain.Seg, error) {
+	segs := make([]domain.Seg, 0, len(r.levels[level]))
+	for _, seg := range r.levels[level] {
+		if err := seg.PostLoad(level); err != nil {
+			return nil, err
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+func TestLoadTaxonomyFrom_Levels(t *testing.T) {
+	cfg := configdomain.Config{FsRepository: infrastructure.ConfigFsReposistory{LevelDirs: []string{"tiers"}}}
+	repository := func(parent string) stubTaxonomyRepository {
+		return stubTaxonomyRepository{levels: map[string][]domain.Seg{
+			"1": {{ID: "prod", Name: "Production"}},
+			"2": {{ID: "app", Name: "App", L1Parents: []string{"prod"}}},
+			"3": {{ID: "web", Name: "Web", Parents: []string{parent}}},
+		}}
+	}
+
+	txy, err := LoadTaxonomyFrom(cfg, repository("app"))
+	if err != nil {
+		t.Fatalf("Expected three level taxonomy to load, got %v", err)
+	}
+	if txy.Depth() != 3 || txy.Segs(3)["web"].L1Parents[0] != "app" {
+		t.Errorf("Expected L3 web under app, got %+v", txy.Levels)
+	}
+
+	if _, err = LoadTaxonomyFrom(cfg, repository("prod")); err == nil {
+		t.Error("Expected L3 segment with an L1 parent to fail")
+	}
+}
+
+func TestLoadTaxonomyFrom_ParentSelectors(t *testing.T) {
+	repository := stubTaxonomyRepository{levels: map[string][]domain.Seg{
+		"1": {
+			{ID: "prod", Name: "Production", Labels: []string{"tier:gold"}},
+			{ID: "staging", Name: "Staging"},
+		},
+		"2": {
+			{ID: "mon", Name: "Monitoring", L1Parents: []string{"*"}, L1Overrides: map[string]domain.L1Overrides{
+				"default": {Labels: []string{"owner:platform"}},
+			}},
+			{ID: "vault", Name: "Vault", L1Parents: []string{"tier:gold"}},
+		},
+	}}
+
+	txy, err := LoadTaxonomyFrom(configdomain.Config{}, repository)
+	if err != nil {
+		t.Fatalf("Expected taxonomy with parent selectors to load, got %v", err)
+	}
+	if mon := txy.SegsL2s["mon"]; len(mon.L1Parents) != 2 || mon.L1Overrides["staging"].ParsedLabels["owner"] != "platform" {
+		t.Errorf("Expected mon under every L1 with the default override, got %+v", mon)
+	}
+	if vault := txy.SegsL2s["vault"]; len(vault.L1Parents) != 1 || vault.L1Parents[0] != "prod" {
+		t.Errorf("Expected vault under prod only, got %v", vault.L1Parents)
+	}
+}
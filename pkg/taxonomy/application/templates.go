@@ -0,0 +1,138 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+// LoadTemplates loads templates from the repository and returns them indexed by ID.
+// Template IDs must be unique across all template documents.
+func LoadTemplates(repository TemplateRepository) (map[string]domain.Template, error) {
+	templateList, err := repository.LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+	if len(templateList) == 0 {
+		return nil, nil
+	}
+
+	templates := make(map[string]domain.Template, len(templateList))
+	duplicates := 0
+	for _, template := range templateList {
+		if _, exists := templates[template.ID]; exists {
+			o11y.Log.Printf("Template ID %s is not unique", template.ID)
+			duplicates++
+			continue
+		}
+		templates[template.ID] = template
+	}
+	if duplicates > 0 {
+		return nil, fmt.Errorf("template validation failed: %d duplicate ID(s)", duplicates)
+	}
+	return templates, nil
+}
+
+// ResolveTemplates applies the templates each segment below L1 extends to the segment's own labels,
+// overrides and metadata. Template extends chains are flattened first; unknown templates and cycles are
+// reported for every segment and template affected. Segment parents must already be expanded.
+// Segments are updated in place.
+func ResolveTemplates(txy *domain.Taxonomy) []error {
+	resolver, errs := newTemplateResolver(txy.Templates)
+	for level := 2; level <= txy.Depth(); level++ {
+		errs = append(errs, resolver.applyLevel(txy, level)...)
+	}
+	return errs
+}
+
+// newTemplateResolver returns a resolver for templates with every extends chain flattened,
+// and the errors of chains that can't be.
+func newTemplateResolver(templates map[string]domain.Template) (templateResolver, []error) {
+	resolver := templateResolver{templates: templates, resolved: make(map[string]domain.Template)}
+	var errs []error
+
+	ids := make([]string, 0, len(templates))
+	for id := range templates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if _, err := resolver.resolve(id, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return resolver, errs
+}
+
+// applyLevel applies the templates the segments of a level extend, in segment ID order.
+func (r templateResolver) applyLevel(txy *domain.Taxonomy, level int) []error {
+	var errs []error
+	segs := txy.Segs(level)
+	for _, id := range txy.SegIDs(level) {
+		seg := segs[id]
+		if len(seg.Extends) == 0 {
+			continue
+		}
+		base, err := r.merge(seg.Extends, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("L%d segment %s: %w", level, id, err))
+			continue
+		}
+		if err := base.ApplyTo(&seg); err != nil {
+			errs = append(errs, fmt.Errorf("L%d segment %s: %w", level, id, err))
+			continue
+		}
+		segs[id] = seg
+	}
+	return errs
+}
+
+// templateResolver flattens template extends chains, caching each resolved template.
+type templateResolver struct {
+	templates map[string]domain.Template
+	resolved  map[string]domain.Template
+}
+
+// resolve returns the template with its extends chain applied beneath it. stack holds the chain of
+// templates being resolved, used to report cycles.
+func (r templateResolver) resolve(id string, stack []string) (domain.Template, error) {
+	if template, ok := r.resolved[id]; ok {
+		return template, nil
+	}
+	for i, visiting := range stack {
+		if visiting == id {
+			return domain.Template{}, fmt.Errorf("template cycle: %s", strings.Join(append(stack[i:], id), " -> "))
+		}
+	}
+	template, ok := r.templates[id]
+	if !ok {
+		if len(stack) > 0 {
+			return domain.Template{}, fmt.Errorf("template %s extends unknown template %s", stack[len(stack)-1], id)
+		}
+		return domain.Template{}, fmt.Errorf("unknown template %s", id)
+	}
+
+	base, err := r.merge(template.Extends, append(stack, id))
+	if err != nil {
+		return domain.Template{}, err
+	}
+	resolved := base.Merge(template)
+	r.resolved[id] = resolved
+	return resolved, nil
+}
+
+// merge resolves and merges the templates in order, later templates winning.
+func (r templateResolver) merge(ids []string, stack []string) (domain.Template, error) {
+	var result domain.Template
+	for _, id := range ids {
+		template, err := r.resolve(id, stack)
+		if err != nil {
+			return domain.Template{}, err
+		}
+		result = result.Merge(template)
+	}
+	return result, nil
+}
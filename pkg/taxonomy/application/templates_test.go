@@ -0,0 +1,137 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+type stubTemplateRepository struct {
+	templates []domain.Template
+}
+
+func (r stubTemplateRepository) LoadTemplates() ([]domain.Template, error) {
+	return r.templates, nil
+}
+
+func TestLoadTemplates(t *testing.T) {
+	t.Run("Indexes templates by ID", func(t *testing.T) {
+		templates, err := LoadTemplates(stubTemplateRepository{templates: []domain.Template{{ID: "a"}, {ID: "b"}}})
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(templates) != 2 || templates["b"].ID != "b" {
+			t.Errorf("Expected templates a and b, got %v", templates)
+		}
+	})
+
+	t.Run("Rejects duplicate IDs", func(t *testing.T) {
+		_, err := LoadTemplates(stubTemplateRepository{templates: []domain.Template{{ID: "a"}, {ID: "a"}}})
+
+		if err == nil {
+			t.Error("Expected duplicate ID error")
+		}
+	})
+}
+
+func TestResolveTemplates(t *testing.T) {
+	newTemplateTaxonomy := func(templates map[string]domain.Template, extends ...string) *domain.Taxonomy {
+		seg := newTestSegWithLabels("app", []string{label("criticality", "2"), label("criticality_rationale", "App specific")})
+		seg.L1Parents = []string{"staging"}
+		seg.Extends = extends
+		return &domain.Taxonomy{
+			SegL1s:    map[string]domain.Seg{"staging": {ID: "staging"}},
+			SegsL2s:   map[string]domain.Seg{"app": seg},
+			Templates: templates,
+		}
+	}
+	stagingTemplate := domain.Template{
+		ID:     "staging-defaults",
+		Labels: []string{label("criticality", "5"), label("criticality_rationale", "Staging default")},
+		Overrides: map[string]domain.L1Overrides{
+			"staging":    {Labels: []string{label("sensitivity", "D"), label("sensitivity_rationale", "Staging data")}},
+			"production": {Labels: []string{label("sensitivity", "A"), label("sensitivity_rationale", "Production data")}},
+		},
+		Metadata: domain.Metadata{"team": "platform"},
+	}
+
+	t.Run("Applies template under the segment's own values", func(t *testing.T) {
+		txy := newTemplateTaxonomy(map[string]domain.Template{"staging-defaults": stagingTemplate}, "staging-defaults")
+
+		if errs := ResolveTemplates(txy); len(errs) != 0 {
+			t.Fatalf("Unexpected errors: %v", errs)
+		}
+		app := txy.SegsL2s["app"]
+		if app.LabelNamespaces[testNs]["criticality"] != "2" {
+			t.Errorf("Expected segment criticality to win, got %v", app.LabelNamespaces[testNs])
+		}
+		if app.L1Overrides["staging"].LabelNamespaces[testNs]["sensitivity"] != "D" {
+			t.Errorf("Expected staging override from template, got %+v", app.L1Overrides)
+		}
+		if _, ok := app.L1Overrides["production"]; ok {
+			t.Error("Expected template override for a parent the segment lacks to be ignored")
+		}
+		if app.Metadata["team"] != "platform" {
+			t.Errorf("Expected template metadata, got %v", app.Metadata)
+		}
+	})
+
+	t.Run("Applies the template default to parents it has no override for", func(t *testing.T) {
+		txy := newTemplateTaxonomy(map[string]domain.Template{"tooling": {
+			ID: "tooling",
+			Overrides: map[string]domain.L1Overrides{
+				domain.DefaultOverride: {Labels: []string{label("sensitivity", "D"), label("sensitivity_rationale", "Tooling data")}},
+			},
+		}}, "tooling")
+
+		if errs := ResolveTemplates(txy); len(errs) != 0 {
+			t.Fatalf("Unexpected errors: %v", errs)
+		}
+		app := txy.SegsL2s["app"]
+		if app.L1Overrides["staging"].LabelNamespaces[testNs]["sensitivity"] != "D" {
+			t.Errorf("Expected staging override from the template default, got %+v", app.L1Overrides)
+		}
+		if _, ok := app.L1Overrides[domain.DefaultOverride]; ok {
+			t.Error("Expected no default override entry on the segment")
+		}
+	})
+
+	t.Run("Later templates and extended templates resolve in order", func(t *testing.T) {
+		txy := newTemplateTaxonomy(map[string]domain.Template{
+			"base":  {ID: "base", Labels: []string{label("sensitivity", "D"), label("owner", "base")}},
+			"child": {ID: "child", Extends: []string{"base"}, Labels: []string{label("sensitivity", "C")}},
+			"last":  {ID: "last", Labels: []string{label("owner", "last")}},
+		}, "child", "last")
+
+		if errs := ResolveTemplates(txy); len(errs) != 0 {
+			t.Fatalf("Unexpected errors: %v", errs)
+		}
+		labels := txy.SegsL2s["app"].LabelNamespaces[testNs]
+		if labels["sensitivity"] != "C" || labels["owner"] != "last" {
+			t.Errorf("Expected sensitivity from child and owner from last, got %v", labels)
+		}
+	})
+
+	t.Run("Reports cycles", func(t *testing.T) {
+		txy := newTemplateTaxonomy(map[string]domain.Template{
+			"a": {ID: "a", Extends: []string{"b"}},
+			"b": {ID: "b", Extends: []string{"a"}},
+		}, "a")
+
+		errs := ResolveTemplates(txy)
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), "template cycle: a -> b -> a") {
+			t.Errorf("Expected cycle diagnostic, got %v", errs)
+		}
+	})
+
+	t.Run("Reports unknown templates", func(t *testing.T) {
+		txy := newTemplateTaxonomy(nil, "missing")
+
+		errs := ResolveTemplates(txy)
+		if len(errs) != 1 || errs[0].Error() != "L2 segment app: unknown template missing" {
+			t.Errorf("Expected unknown template diagnostic, got %v", errs)
+		}
+	})
+}
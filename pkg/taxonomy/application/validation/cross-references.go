@@ -12,8 +12,20 @@ func ValidateL2Definition(txy *domain.Taxonomy, pluginMap plugins.Plugins) (bool
 
 	// Loop through Segs and validate L1 parent references
 	for _, secDomain := range txy.SegsL2s {
+		// Selectors and the default override are expanded on load, so none should remain
+		if _, hasDefault := secDomain.L1Overrides[domain.DefaultOverride]; hasDefault {
+			o11y.Log.Printf("ERROR: Seg '%s' default override was not applied to its parents\n", secDomain.Name)
+			failures++
+			valid = false
+		}
 		// REFACTORED: Iterate over L1Parents instead of L1Overrides keys
 		for _, l1ID := range secDomain.L1Parents {
+			if domain.IsParentSelector(l1ID) {
+				o11y.Log.Printf("ERROR: Seg '%s' parent selector %s was not expanded\n", secDomain.Name, l1ID)
+				failures++
+				valid = false
+				continue
+			}
 			// Validate parent L1 exists in taxonomy
 			if _, ok := txy.SegL1s[l1ID]; !ok {
 				o11y.Log.Printf("Invalid L1 parent for Seg %s: %s\n", secDomain.Name, l1ID)
@@ -37,3 +49,25 @@ func ValidateL2Definition(txy *domain.Taxonomy, pluginMap plugins.Plugins) (bool
 	}
 	return valid, failures
 }
+
+// ValidateLevelDefinitions validates that segments below L2 reference existing parents in the level above.
+func ValidateLevelDefinitions(txy *domain.Taxonomy) (bool, int) {
+	failures := 0
+	for level := 3; level <= txy.Depth(); level++ {
+		parents := txy.Segs(level - 1)
+		for _, seg := range txy.Segs(level) {
+			for _, parentID := range seg.L1Parents {
+				if _, ok := parents[parentID]; !ok {
+					o11y.Log.Printf("Invalid L%d parent for L%d segment %s: %s\n", level-1, level, seg.Name, parentID)
+					failures++
+					continue
+				}
+				if _, exists := seg.L1Overrides[parentID]; !exists {
+					o11y.Log.Printf("ERROR: Seg '%s' has parent '%s' but no override data after inheritance\n", seg.Name, parentID)
+					failures++
+				}
+			}
+		}
+	}
+	return failures == 0, failures
+}
@@ -43,6 +43,19 @@ func TestValidateL2Definition(t *testing.T) {
 		AssertMinFailures(t, failures, 1, "Invalid L1 parent reference")
 	})
 
+	t.Run("Unexpanded selector and default override fail", func(t *testing.T) {
+		pluginMap := make(plugins.Plugins)
+		seg := NewSeg("app", "Application", map[string]domain.L1Overrides{
+			domain.DefaultOverride: NewL1Override("A", "1", nil),
+		})
+		seg.L1Parents = []string{domain.WildcardParent}
+		txy := WithSeg(WithSegL1(NewTestTaxonomy(), "prod", NewSegL1("prod", "Production", "A", "1", nil)), "app", seg)
+
+		valid, failures := ValidateL2Definition(txy, pluginMap)
+		AssertValidationFails(t, valid, "Unexpanded selector")
+		AssertFailureCount(t, failures, 2, "Unexpanded selector")
+	})
+
 	t.Run("Multiple validation failures counted", func(t *testing.T) {
 		pluginMap := make(plugins.Plugins)
 		txy := WithSeg(
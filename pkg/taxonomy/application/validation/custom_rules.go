@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"fmt"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// customRuleIdents are the variables available to custom rule expressions.
+// An L2 is evaluated once per parent, with parent set and labels resolved for that parent.
+var customRuleIdents = map[string]bool{
+	"id":          true,
+	"name":        true,
+	"description": true,
+	"level":       true,
+	"parent":      true,
+	"l1_parents":  true,
+	"labels":      true,
+}
+
+// LogicRuleCustom validates a configured expression against every selected segment.
+type LogicRuleCustom struct {
+	config     configdomain.CustomRuleConfig
+	selector   exprNode
+	expression exprNode
+	compileErr error
+}
+
+// NewLogicRuleCustom compiles the rule's selector and expression.
+// Compile errors are reported by Validate so they surface with other validation errors.
+func NewLogicRuleCustom(config configdomain.CustomRuleConfig) *LogicRuleCustom {
+	rule := &LogicRuleCustom{config: config}
+	if config.Selector != "" {
+		selector, err := compileExpression(config.Selector, customRuleIdents)
+		if err != nil {
+			rule.compileErr = fmt.Errorf("custom rule %s: invalid selector: %w", config.ID, err)
+			return rule
+		}
+		rule.selector = selector
+	}
+	expression, err := compileExpression(config.Expression, customRuleIdents)
+	if err != nil {
+		rule.compileErr = fmt.Errorf("custom rule %s: invalid expression: %w", config.ID, err)
+		return rule
+	}
+	rule.expression = expression
+	return rule
+}
+
+// customRuleEnv builds the expression variables for seg, resolving labels for parentID (empty for L1s).
+// l1_parents holds the segment's parents in the level above, as the field does.
+func customRuleEnv(seg domain.Seg, level int64, parentID string) exprEnv {
+	parents := make([]any, 0, len(seg.L1Parents))
+	for _, p := range seg.L1Parents {
+		parents = append(parents, p)
+	}
+	return exprEnv{
+		"id":          seg.ID,
+		"name":        seg.Name,
+		"description": seg.Description,
+		"level":       level,
+		"parent":      parentID,
+		"l1_parents":  parents,
+		"labels":      seg.EffectiveLabels(parentID),
+	}
+}
+
+// check evaluates the rule for one segment/parent pair. Segments not selected pass.
+func (r *LogicRuleCustom) check(env exprEnv) (bool, error) {
+	if r.selector != nil {
+		selected, err := evalBool(r.selector, env)
+		if err != nil {
+			return false, fmt.Errorf("selector: %w", err)
+		}
+		if !selected {
+			return true, nil
+		}
+	}
+	return evalBool(r.expression, env)
+}
+
+// Validate evaluates the rule against every L1, and every segment of the levels below once per parent.
+// Rules with severity warning log failures without returning them; evaluation errors are always returned.
+func (r *LogicRuleCustom) Validate(taxonomy *domain.Taxonomy) []error {
+	if r.compileErr != nil {
+		o11y.Log.Printf("%v", r.compileErr)
+		return []error{r.compileErr}
+	}
+
+	reason := r.config.Description
+	if reason == "" {
+		reason = r.config.Expression
+	}
+
+	var errs []error
+	evaluate := func(env exprEnv, target string) {
+		passed, err := r.check(env)
+		switch {
+		case err != nil:
+			err = fmt.Errorf("custom rule %s: %s: %w", r.config.ID, target, err)
+		case passed:
+			return
+		case r.config.Severity == SeverityWarning:
+			o11y.Log.Printf("WARNING: custom rule %s failed for %s: %s", r.config.ID, target, reason)
+			return
+		default:
+			err = fmt.Errorf("custom rule %s failed for %s: %s", r.config.ID, target, reason)
+		}
+		o11y.Log.Printf("%v", err)
+		errs = append(errs, err)
+	}
+
+	for level := 1; level <= taxonomy.Depth(); level++ {
+		segs := taxonomy.Segs(level)
+		for _, id := range taxonomy.SegIDs(level) {
+			seg := segs[id]
+			if level == 1 {
+				evaluate(customRuleEnv(seg, 1, ""), "L1 "+id)
+				continue
+			}
+			for _, parentID := range seg.L1Parents {
+				evaluate(customRuleEnv(seg, int64(level), parentID), fmt.Sprintf("L%d %s in L%d %s", level, id, level-1, parentID))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+// newCustomRuleTaxonomy builds production and staging L1s and an L2 under both,
+// in-scope for req1 with criticality 3 under production via an override
+func newCustomRuleTaxonomy() *domain.Taxonomy {
+	app := newSegWithClassification("app", "App", "B", "2", []string{"req1"})
+	app.L1Parents = []string{"production", "staging"}
+	override := domain.L1Overrides{Labels: []string{testNs + "/criticality:3"}}
+	override.ParseLabels()
+	app.L1Overrides = map[string]domain.L1Overrides{"production": override}
+
+	return &domain.Taxonomy{
+		SegL1s: map[string]domain.Seg{
+			"production": newSegWithClassification("production", "Production", "A", "1", []string{"req1"}),
+			"staging":    newSegWithClassification("staging", "Staging", "C", "4", nil),
+		},
+		SegsL2s: map[string]domain.Seg{"app": app},
+	}
+}
+
+var pciCriticalityRule = configdomain.CustomRuleConfig{
+	ID:          "prod-req1-criticality",
+	Description: "production L2s in scope for req1 must have criticality 2 or higher",
+	Selector:    `level == 2 && parent == "production" && label("compliance", "req1") == "in-scope"`,
+	Expression:  `int(label("classifications", "criticality")) <= 2`,
+}
+
+func TestLogicRuleCustom_Validate(t *testing.T) {
+	t.Run("Fails using labels resolved for the selected parent", func(t *testing.T) {
+		rule := NewLogicRuleCustom(pciCriticalityRule)
+
+		errs := rule.Validate(newCustomRuleTaxonomy())
+
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got %v", errs)
+		}
+		if !strings.Contains(errs[0].Error(), "L2 app in L1 production") || !strings.Contains(errs[0].Error(), "criticality 2 or higher") {
+			t.Errorf("Expected error for app under production with description, got %v", errs[0])
+		}
+	})
+
+	t.Run("Passes when selected segments satisfy expression", func(t *testing.T) {
+		txy := newCustomRuleTaxonomy()
+		app := txy.SegsL2s["app"]
+		app.L1Overrides = nil
+		txy.SegsL2s["app"] = app
+
+		errs := NewLogicRuleCustom(pciCriticalityRule).Validate(txy)
+
+		if len(errs) > 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Warning severity logs without returning errors", func(t *testing.T) {
+		config := pciCriticalityRule
+		config.Severity = SeverityWarning
+
+		errs := NewLogicRuleCustom(config).Validate(newCustomRuleTaxonomy())
+
+		if len(errs) > 0 {
+			t.Errorf("Expected no errors for warning rule, got %v", errs)
+		}
+	})
+
+	t.Run("Empty selector applies to L1s and L2s", func(t *testing.T) {
+		rule := NewLogicRuleCustom(configdomain.CustomRuleConfig{ID: "names", Expression: `name != "Staging"`})
+
+		errs := rule.Validate(newCustomRuleTaxonomy())
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "L1 staging") {
+			t.Errorf("Expected error for L1 staging, got %v", errs)
+		}
+	})
+
+	t.Run("Reports compile errors", func(t *testing.T) {
+		rule := NewLogicRuleCustom(configdomain.CustomRuleConfig{ID: "broken", Expression: `owner == "x"`})
+
+		errs := rule.Validate(newCustomRuleTaxonomy())
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "invalid expression") {
+			t.Errorf("Expected compile error, got %v", errs)
+		}
+	})
+
+	t.Run("Reports evaluation errors even for warnings", func(t *testing.T) {
+		rule := NewLogicRuleCustom(configdomain.CustomRuleConfig{
+			ID: "bad-int", Severity: SeverityWarning, Expression: `int(name) > 0`,
+		})
+
+		errs := rule.Validate(newCustomRuleTaxonomy())
+
+		if len(errs) != 4 {
+			t.Errorf("Expected an evaluation error per L1 and L2/parent pair, got %v", errs)
+		}
+	})
+
+	t.Run("Evaluates segments below L2 once per parent", func(t *testing.T) {
+		txy := newCustomRuleTaxonomy()
+		txy.Levels = map[string]map[string]domain.Seg{"3": {
+			"api": {ID: "api", Name: "Staging", L1Parents: []string{"app"}},
+		}}
+		rule := NewLogicRuleCustom(configdomain.CustomRuleConfig{ID: "names", Selector: `level == 3`, Expression: `name != "Staging"`})
+
+		errs := rule.Validate(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "L3 api in L2 app") {
+			t.Errorf("Expected error for L3 api in L2 app, got %v", errs)
+		}
+	})
+}
+
+func TestNewLogicRuleSet_CustomRules(t *testing.T) {
+	config := configdomain.Config{Rules: configdomain.LogicRulesConfig{Custom: []configdomain.CustomRuleConfig{
+		pciCriticalityRule,
+		{ID: "always", Expression: "true"},
+		{ID: "always", Expression: "true"},
+	}}}
+
+	rs := NewLogicRuleSet(config, createMockPluginMap())
+
+	if len(rs.LogicRules) != 2 {
+		t.Fatalf("Expected 2 custom rules registered, got %d", len(rs.LogicRules))
+	}
+	errs := rs.LogicRules["Custom:always"].Validate(newCustomRuleTaxonomy())
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "more than once") {
+		t.Errorf("Expected duplicate id error, got %v", errs)
+	}
+}
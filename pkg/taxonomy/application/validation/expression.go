@@ -0,0 +1,735 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+// Expression language for custom rules: a small, CEL-like subset evaluated in-process.
+//
+//	literals:   "str", 'str', 42, true, false, ["a", "b"]
+//	operators:  ! - && || == != < <= > >= in, parentheses
+//	indexing:   labels["ns/key"] (missing keys yield ""), list[0]
+//	functions:  int(x), string(x), size(x), label(plugin, key)
+//	methods:    s.startsWith(p), s.endsWith(p), s.contains(p), s.matches(re)
+//
+// Values are string, int64, bool, []any or map[string]string. Comparisons never coerce types.
+
+type exprEnv map[string]any
+
+type exprNode interface {
+	eval(env exprEnv) (any, error)
+}
+
+// exprFunc implements a global function; env gives access to variables such as labels.
+type exprFunc func(env exprEnv, args []any) (any, error)
+
+var exprFuncs = map[string]exprFunc{
+	"int":    exprInt,
+	"string": exprString,
+	"size":   exprSize,
+	"label":  exprLabel,
+}
+
+var exprMethods = map[string]func(recv, arg string) (bool, error){
+	"startsWith": func(recv, arg string) (bool, error) { return strings.HasPrefix(recv, arg), nil },
+	"endsWith":   func(recv, arg string) (bool, error) { return strings.HasSuffix(recv, arg), nil },
+	"contains":   func(recv, arg string) (bool, error) { return strings.Contains(recv, arg), nil },
+	"matches": func(recv, arg string) (bool, error) {
+		re, err := compileMatches(arg)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(recv), nil
+	},
+}
+
+func compileMatches(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// ###################
+// Tokeniser
+
+const (
+	tokEOF = iota
+	tokIdent
+	tokString
+	tokInt
+	tokOp
+)
+
+type exprToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+var exprOps = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ",", ".", "-"}
+
+// tokenise splits src into tokens, decoding it as UTF-8 so string literals and identifiers can hold
+// non-ASCII characters. Positions are byte offsets into src.
+func tokenise(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	i := 0
+	for i < len(src) {
+		c, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case c == utf8.RuneError && size == 1:
+			return nil, fmt.Errorf("invalid UTF-8 at position %d", i)
+		case unicode.IsSpace(c):
+			i += size
+		case c == '"' || c == '\'':
+			start := i
+			var sb strings.Builder
+			i += size
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("unterminated string at position %d", start)
+				}
+				r, rSize := utf8.DecodeRuneInString(src[i:])
+				if r == c {
+					i += rSize
+					break
+				}
+				if r == '\\' && i+rSize < len(src) {
+					i += rSize
+					r, rSize = utf8.DecodeRuneInString(src[i:])
+				}
+				if r == utf8.RuneError && rSize == 1 {
+					return nil, fmt.Errorf("invalid UTF-8 at position %d", i)
+				}
+				sb.WriteRune(r)
+				i += rSize
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: sb.String(), pos: start})
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokInt, text: src[start:i], pos: start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(src) {
+				r, rSize := utf8.DecodeRuneInString(src[i:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+				i += rSize
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range exprOps {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, exprToken{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+		}
+	}
+	return append(tokens, exprToken{kind: tokEOF, pos: len(src)}), nil
+}
+
+// ###################
+// Parser
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	idents map[string]bool
+}
+
+// compileExpression parses src, rejecting identifiers not in idents and unknown functions.
+func compileExpression(src string, idents map[string]bool) (exprNode, error) {
+	tokens, err := tokenise(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens, idents: idents}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return node, nil
+}
+
+func (p *exprParser) peek() exprToken { return p.tokens[p.pos] }
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) acceptOp(op string) bool {
+	if tok := p.peek(); tok.kind == tokOp && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expectOp(op string) error {
+	if !p.acceptOp(op) {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at position %d", op, tok.pos)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseRelation()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("&&") {
+		right, err := p.parseRelation()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseRelation() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	op := ""
+	switch {
+	case tok.kind == tokOp && (tok.text == "==" || tok.text == "!=" || tok.text == "<" || tok.text == "<=" || tok.text == ">" || tok.text == ">="):
+		op = tok.text
+	case tok.kind == tokIdent && tok.text == "in":
+		op = "in"
+	default:
+		return left, nil
+	}
+	p.next()
+	right, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return relationNode{op: op, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.acceptOp("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	if p.acceptOp("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.acceptOp("["):
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp("]"); err != nil {
+				return nil, err
+			}
+			node = indexNode{target: node, index: index}
+		case p.acceptOp("."):
+			tok := p.next()
+			if tok.kind != tokIdent {
+				return nil, fmt.Errorf("expected method name at position %d", tok.pos)
+			}
+			if _, ok := exprMethods[tok.text]; !ok {
+				return nil, fmt.Errorf("unknown method %s at position %d", tok.text, tok.pos)
+			}
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			if len(args) != 1 {
+				return nil, fmt.Errorf("method %s takes 1 argument, got %d", tok.text, len(args))
+			}
+			method := methodNode{name: tok.text, target: node, arg: args[0]}
+			// Literal patterns are compiled once here rather than on every evaluation
+			if literal, ok := args[0].(literalNode); ok && tok.text == "matches" {
+				if pattern, ok := literal.value.(string); ok {
+					if method.re, err = compileMatches(pattern); err != nil {
+						return nil, fmt.Errorf("%w at position %d", err, tok.pos)
+					}
+				}
+			}
+			node = method
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *exprParser) parseArgs() ([]exprNode, error) {
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+	var args []exprNode
+	if p.acceptOp(")") {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.acceptOp(")") {
+			return args, nil
+		}
+		if err := p.expectOp(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokString:
+		return literalNode{value: tok.text}, nil
+	case tokInt:
+		value, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %s at position %d", tok.text, tok.pos)
+		}
+		return literalNode{value: value}, nil
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		}
+		if next := p.peek(); next.kind == tokOp && next.text == "(" {
+			fn, ok := exprFuncs[tok.text]
+			if !ok {
+				return nil, fmt.Errorf("unknown function %s at position %d", tok.text, tok.pos)
+			}
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return callNode{name: tok.text, fn: fn, args: args}, nil
+		}
+		if !p.idents[tok.text] {
+			return nil, fmt.Errorf("unknown identifier %s at position %d", tok.text, tok.pos)
+		}
+		return identNode{name: tok.text}, nil
+	case tokOp:
+		switch tok.text {
+		case "(":
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expectOp(")")
+		case "[":
+			var items []exprNode
+			if p.acceptOp("]") {
+				return listNode{}, nil
+			}
+			for {
+				item, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+				if p.acceptOp("]") {
+					return listNode{items: items}, nil
+				}
+				if err := p.expectOp(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case tokEOF:
+		return nil, errors.New("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+// ###################
+// Evaluation
+
+type literalNode struct{ value any }
+
+func (n literalNode) eval(exprEnv) (any, error) { return n.value, nil }
+
+type identNode struct{ name string }
+
+func (n identNode) eval(env exprEnv) (any, error) {
+	value, ok := env[n.name]
+	if !ok {
+		return nil, fmt.Errorf("identifier %s is not set", n.name)
+	}
+	return value, nil
+}
+
+type listNode struct{ items []exprNode }
+
+func (n listNode) eval(env exprEnv) (any, error) {
+	values := make([]any, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func evalBool(node exprNode, env exprEnv) (bool, error) {
+	value, err := node.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected bool, got %T", value)
+	}
+	return b, nil
+}
+
+type logicalNode struct {
+	or          bool
+	left, right exprNode
+}
+
+// eval short-circuits so guards like `"k" in labels && labels["k"] == "v"` are safe.
+func (n logicalNode) eval(env exprEnv) (any, error) {
+	left, err := evalBool(n.left, env)
+	if err != nil {
+		return nil, err
+	}
+	if left == n.or {
+		return left, nil
+	}
+	return evalBool(n.right, env)
+}
+
+type notNode struct{ operand exprNode }
+
+func (n notNode) eval(env exprEnv) (any, error) {
+	value, err := evalBool(n.operand, env)
+	if err != nil {
+		return nil, err
+	}
+	return !value, nil
+}
+
+type negNode struct{ operand exprNode }
+
+func (n negNode) eval(env exprEnv) (any, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	i, ok := value.(int64)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %T", value)
+	}
+	return -i, nil
+}
+
+type relationNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n relationNode) eval(env exprEnv) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=":
+		equal, err := exprEqual(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return equal == (n.op == "=="), nil
+	case "in":
+		return exprIn(left, right)
+	}
+
+	cmp, err := exprCompare(left, right)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func exprEqual(left, right any) (bool, error) {
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok {
+			return l == r, nil
+		}
+	case int64:
+		if r, ok := right.(int64); ok {
+			return l == r, nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			return l == r, nil
+		}
+	}
+	return false, fmt.Errorf("cannot compare %T with %T", left, right)
+}
+
+func exprCompare(left, right any) (int, error) {
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	case int64:
+		if r, ok := right.(int64); ok {
+			switch {
+			case l < r:
+				return -1, nil
+			case l > r:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, fmt.Errorf("cannot order %T and %T", left, right)
+}
+
+func exprIn(left, right any) (bool, error) {
+	switch r := right.(type) {
+	case []any:
+		for _, item := range r {
+			if equal, err := exprEqual(left, item); err == nil && equal {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]string:
+		key, ok := left.(string)
+		if !ok {
+			return false, fmt.Errorf("map keys are strings, got %T", left)
+		}
+		_, has := r[key]
+		return has, nil
+	}
+	return false, fmt.Errorf("cannot use in with %T", right)
+}
+
+type indexNode struct{ target, index exprNode }
+
+func (n indexNode) eval(env exprEnv) (any, error) {
+	target, err := n.target.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case map[string]string:
+		key, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("map keys are strings, got %T", index)
+		}
+		return t[key], nil
+	case []any:
+		i, ok := index.(int64)
+		if !ok {
+			return nil, fmt.Errorf("list index must be int, got %T", index)
+		}
+		if i < 0 || i >= int64(len(t)) {
+			return nil, fmt.Errorf("list index %d out of range", i)
+		}
+		return t[i], nil
+	}
+	return nil, fmt.Errorf("cannot index %T", target)
+}
+
+type callNode struct {
+	name string
+	fn   exprFunc
+	args []exprNode
+}
+
+func (n callNode) eval(env exprEnv) (any, error) {
+	args := make([]any, 0, len(n.args))
+	for _, arg := range n.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+	value, err := n.fn(env, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", n.name, err)
+	}
+	return value, nil
+}
+
+type methodNode struct {
+	name        string
+	target, arg exprNode
+	re          *regexp.Regexp // matches() pattern compiled at parse time when it is a literal
+}
+
+func (n methodNode) eval(env exprEnv) (any, error) {
+	target, err := n.target.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	arg, err := n.arg.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	recv, ok := target.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s() needs a string receiver, got %T", n.name, target)
+	}
+	s, ok := arg.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s() needs a string argument, got %T", n.name, arg)
+	}
+	if n.re != nil {
+		return n.re.MatchString(recv), nil
+	}
+	return exprMethods[n.name](recv, s)
+}
+
+func exprInt(_ exprEnv, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("takes 1 argument, got %d", len(args))
+	}
+	switch v := args[0].(type) {
+	case int64:
+		return v, nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to int", v)
+		}
+		return i, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to int", args[0])
+}
+
+func exprString(_ exprEnv, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("takes 1 argument, got %d", len(args))
+	}
+	switch v := args[0].(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to string", args[0])
+}
+
+func exprSize(_ exprEnv, args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("takes 1 argument, got %d", len(args))
+	}
+	switch v := args[0].(type) {
+	case string:
+		return int64(utf8.RuneCountInString(v)), nil
+	case []any:
+		return int64(len(v)), nil
+	case map[string]string:
+		return int64(len(v)), nil
+	}
+	return nil, fmt.Errorf("cannot take size of %T", args[0])
+}
+
+// exprLabel returns labels["bunsceal.plugin.<plugin>/<key>"], or "" when unset.
+func exprLabel(env exprEnv, args []any) (any, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("takes 2 arguments, got %d", len(args))
+	}
+	pluginName, okPlugin := args[0].(string)
+	key, okKey := args[1].(string)
+	if !okPlugin || !okKey {
+		return nil, errors.New("arguments must be strings")
+	}
+	labels, _ := env["labels"].(map[string]string)
+	return labels[plugins.NsPrefix+pluginName+"/"+key], nil
+}
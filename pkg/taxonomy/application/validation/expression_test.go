@@ -0,0 +1,123 @@
+package validation
+
+import (
+	"testing"
+)
+
+func TestCompileExpression(t *testing.T) {
+	env := exprEnv{
+		"id":         "app",
+		"level":      int64(2),
+		"parent":     "production",
+		"l1_parents": []any{"production", "staging"},
+		"team":       "équipe données",
+		"région":     "eu-west",
+		"labels": map[string]string{
+			"bunsceal.plugin.classifications/criticality": "2",
+			"bunsceal.plugin.compliance/pci-dss":          "in-scope",
+		},
+	}
+	idents := map[string]bool{"id": true, "level": true, "parent": true, "l1_parents": true, "labels": true, "team": true, "région": true}
+
+	tests := []struct {
+		name     string
+		src      string
+		expected bool
+	}{
+		{"String equality", `parent == "production"`, true},
+		{"Single quoted string", `id != 'db'`, true},
+		{"Integer comparison", `level >= 2`, true},
+		{"Int conversion of label", `int(label("classifications", "criticality")) <= 2`, true},
+		{"Map indexing", `labels["bunsceal.plugin.compliance/pci-dss"] == "in-scope"`, true},
+		{"Missing label is empty", `label("compliance", "soc2") == ""`, true},
+		{"Map membership", `"bunsceal.plugin.compliance/soc2" in labels`, false},
+		{"List membership", `"staging" in l1_parents`, true},
+		{"List literal", `parent in ["production", "dr"]`, true},
+		{"Precedence of && over ||", `false && false || true`, true},
+		{"Negation", `!(level == 1)`, true},
+		{"Negative integer", `-1 < 0`, true},
+		{"Methods", `id.startsWith("a") && id.endsWith("p") && id.contains("pp") && id.matches("^a.p$")`, true},
+		{"Size of list", `size(l1_parents) == 2`, true},
+		{"Size of string counts characters", `size("é") == 1`, true},
+		{"String conversion", `string(level) == "2"`, true},
+		{"Short circuit skips invalid right side", `level == 1 && int(id) > 0`, false},
+		{"Non-ASCII string literal", `team == "équipe données"`, true},
+		{"Non-ASCII identifier", `région == 'eu-west'`, true},
+		{"Escaped quote beside non-ASCII", `'l\'été' == "l'été"`, true},
+		{"Non-ASCII pattern", `team.matches("^équipe\\s")`, true},
+		{"Pattern from a variable", `team.matches(team)`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := compileExpression(tt.src, idents)
+			if err != nil {
+				t.Fatalf("Compile failed: %v", err)
+			}
+			result, err := evalBool(node, env)
+			if err != nil {
+				t.Fatalf("Evaluation failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCompileExpression_Errors(t *testing.T) {
+	idents := map[string]bool{"id": true}
+
+	compileErrors := []struct {
+		name string
+		src  string
+	}{
+		{"Unknown identifier", `owner == "x"`},
+		{"Unknown function", `lower(id) == "x"`},
+		{"Unknown method", `id.upper("x")`},
+		{"Unterminated string", `id == "x`},
+		{"Trailing tokens", `id == "x" "y"`},
+		{"Missing closing paren", `(id == "x"`},
+		{"Unexpected character", `id == $x`},
+		{"Empty expression", ``},
+		{"Invalid literal regular expression", `id.matches("(")`},
+		{"Invalid UTF-8", "id == \"\xff\""},
+	}
+	for _, tt := range compileErrors {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compileExpression(tt.src, idents); err == nil {
+				t.Error("Expected compile error")
+			}
+		})
+	}
+
+	evalErrors := []struct {
+		name string
+		src  string
+	}{
+		{"Compare string with int", `id == 1`},
+		{"Non-bool result", `id`},
+		{"Invalid int conversion", `int(id) > 0`},
+	}
+	for _, tt := range evalErrors {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := compileExpression(tt.src, idents)
+			if err != nil {
+				t.Fatalf("Compile failed: %v", err)
+			}
+			if _, err := evalBool(node, exprEnv{"id": "app"}); err == nil {
+				t.Error("Expected evaluation error")
+			}
+		})
+	}
+}
+
+func TestCompileExpression_PrecompilesLiteralPatterns(t *testing.T) {
+	node, err := compileExpression(`id.matches("^a")`, map[string]bool{"id": true})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	if method, ok := node.(methodNode); !ok || method.re == nil {
+		t.Errorf("Expected the literal pattern to be compiled at parse time, got %#v", node)
+	}
+}
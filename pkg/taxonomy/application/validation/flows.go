@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/o11y"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+// ValidateFlows checks each flow's fields and that its source and destination exist at the flow's level.
+// Flows must use canonical IDs, not segment aliases.
+func ValidateFlows(txy *domain.Taxonomy) []error {
+	var errs []error
+
+	ids := make([]string, 0, len(txy.Flows))
+	for id := range txy.Flows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		flow := txy.Flows[id]
+		if err := flow.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		segs := txy.FlowSegments(flow)
+		for _, segID := range []string{flow.Source, flow.Destination} {
+			if _, exists := segs[segID]; exists {
+				continue
+			}
+			if seg, aliased, _ := domain.ResolveSeg(segs, segID); aliased {
+				errs = append(errs, fmt.Errorf("flow %s: L%d segment %s is an alias, use %s", flow.ID, flow.Level, segID, seg.ID))
+				continue
+			}
+			errs = append(errs, fmt.Errorf("flow %s: L%d segment %s not found", flow.ID, flow.Level, segID))
+		}
+	}
+	return errs
+}
+
+// LogicRuleFlowClassification rejects flows from a less to a more strictly classified segment
+// unless the flow records an exception. Bidirectional flows are checked in both directions.
+type LogicRuleFlowClassification struct {
+	config               configdomain.FlowClassificationConfig
+	classificationPlugin plugins.Plugin
+}
+
+// NewLogicRuleFlowClassification creates a new FlowClassification validation rule.
+// Without keys the rule compares sensitivity.
+func NewLogicRuleFlowClassification(config configdomain.FlowClassificationConfig, classificationPlugin plugins.Plugin) *LogicRuleFlowClassification {
+	if len(config.Keys) == 0 {
+		config.Keys = []string{"sensitivity"}
+	}
+	return &LogicRuleFlowClassification{
+		config:               config,
+		classificationPlugin: classificationPlugin,
+	}
+}
+
+// segmentRanks returns the ranks of a segment's values for key; segments below L1 have one per parent.
+func segmentRanks(cp *plugins.ClassificationsPlugin, txy *domain.Taxonomy, flow domain.Flow, segID, key string) []int {
+	seg := txy.FlowSegments(flow)[segID]
+	parents := []string{""}
+	if flow.Level > 1 {
+		parents = seg.L1Parents
+	}
+	var ranks []int
+	for _, parentID := range parents {
+		value, _ := seg.GetNamespacedValue(parentID, cp.GetNamespace(), key)
+		if rank, ok := cp.StrictnessRank(key, value); ok {
+			ranks = append(ranks, rank)
+		}
+	}
+	return ranks
+}
+
+// raisesClassification reports whether any source rank is less strict (higher) than any destination rank.
+func raisesClassification(from, to []int) bool {
+	for _, f := range from {
+		for _, t := range to {
+			if f > t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate checks every flow against the configured classification keys.
+// Returns a slice of errors if validation fails, or an empty slice if valid.
+func (r *LogicRuleFlowClassification) Validate(taxonomy *domain.Taxonomy) []error {
+	if len(taxonomy.Flows) == 0 {
+		return nil
+	}
+
+	var errs []error
+	report := func(err error) {
+		o11y.Log.Printf("%v", err)
+		errs = append(errs, err)
+	}
+
+	cp, ok := r.classificationPlugin.(*plugins.ClassificationsPlugin)
+	if !ok {
+		report(errors.New("flow classification rule requires the classifications plugin"))
+		return errs
+	}
+	for _, key := range r.config.Keys {
+		if _, defined := cp.Config.Definitions[key]; !defined {
+			report(fmt.Errorf("flow classification rule key %s is not a classification definition", key))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	ids := make([]string, 0, len(taxonomy.Flows))
+	for id := range taxonomy.Flows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		flow := taxonomy.Flows[id]
+		if flow.Exception != "" {
+			continue
+		}
+		for _, key := range r.config.Keys {
+			src := segmentRanks(cp, taxonomy, flow, flow.Source, key)
+			dst := segmentRanks(cp, taxonomy, flow, flow.Destination, key)
+			if raisesClassification(src, dst) {
+				report(fmt.Errorf("flow %s from %s to %s goes from lower to higher %s without an exception", flow.ID, flow.Source, flow.Destination, key))
+			} else if flow.Direction == domain.FlowBidirectional && raisesClassification(dst, src) {
+				report(fmt.Errorf("bidirectional flow %s lets %s reach higher %s %s without an exception", flow.ID, flow.Destination, key, flow.Source))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
+)
+
+func newFlow(id, source, destination string) domain.Flow {
+	return domain.Flow{
+		ID:          id,
+		Level:       1,
+		Source:      source,
+		Destination: destination,
+		Protocol:    domain.FlowProtocolTCP,
+		Ports:       []string{"443"},
+		Direction:   domain.FlowOneWay,
+		Rationale:   "Test flow rationale",
+	}
+}
+
+func newFlowTaxonomy(flows ...domain.Flow) *domain.Taxonomy {
+	txy := newCustomRuleTaxonomy()
+	txy.Flows = make(map[string]domain.Flow, len(flows))
+	for _, f := range flows {
+		txy.Flows[f.ID] = f
+	}
+	return txy
+}
+
+func TestValidateFlows(t *testing.T) {
+	t.Run("Passes for flows between existing segments", func(t *testing.T) {
+		errs := ValidateFlows(newFlowTaxonomy(newFlow("prod-to-staging", "production", "staging")))
+
+		if len(errs) > 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Fails for segments missing at the flow level", func(t *testing.T) {
+		l2Flow := newFlow("app-to-staging", "app", "staging")
+		l2Flow.Level = 2
+
+		errs := ValidateFlows(newFlowTaxonomy(newFlow("to-dr", "production", "dr"), l2Flow))
+
+		if len(errs) != 2 {
+			t.Fatalf("Expected 2 errors, got %v", errs)
+		}
+		if !strings.Contains(errs[0].Error(), "app-to-staging") || !strings.Contains(errs[1].Error(), "dr") {
+			t.Errorf("Expected errors sorted by flow id naming missing segments, got %v", errs)
+		}
+	})
+
+	t.Run("Fails for segment aliases naming the canonical ID", func(t *testing.T) {
+		txy := newFlowTaxonomy(newFlow("prod-to-stg", "production", "stg"))
+		staging := txy.SegL1s["staging"]
+		staging.Aliases = []string{"stg"}
+		txy.SegL1s["staging"] = staging
+
+		errs := ValidateFlows(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "stg is an alias, use staging") {
+			t.Errorf("Expected alias error, got %v", errs)
+		}
+	})
+
+	t.Run("Fails for invalid flow fields", func(t *testing.T) {
+		f := newFlow("icmp-ports", "production", "staging")
+		f.Protocol = domain.FlowProtocolICMP
+
+		errs := ValidateFlows(newFlowTaxonomy(f))
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "ports cannot be set") {
+			t.Errorf("Expected ports error, got %v", errs)
+		}
+	})
+
+	t.Run("Checks flows below L2 against their level", func(t *testing.T) {
+		l3Flow := newFlow("api-to-worker", "api", "worker")
+		l3Flow.Level = 3
+		txy := newFlowTaxonomy(l3Flow)
+		txy.Levels = map[string]map[string]domain.Seg{"3": {"api": {ID: "api", L1Parents: []string{"app"}}}}
+
+		errs := ValidateFlows(txy)
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "L3 segment worker not found") {
+			t.Errorf("Expected missing L3 worker error, got %v", errs)
+		}
+	})
+}
+
+func TestLogicRuleFlowClassification_Validate(t *testing.T) {
+	config := configdomain.FlowClassificationConfig{Enabled: true, Keys: []string{"sensitivity"}}
+	rule := NewLogicRuleFlowClassification(config, createMockClassificationPlugin())
+
+	t.Run("Allows flows from higher to lower sensitivity", func(t *testing.T) {
+		errs := rule.Validate(newFlowTaxonomy(newFlow("prod-to-staging", "production", "staging")))
+
+		if len(errs) > 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Rejects flows from lower to higher sensitivity", func(t *testing.T) {
+		errs := rule.Validate(newFlowTaxonomy(newFlow("staging-to-prod", "staging", "production")))
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "lower to higher sensitivity") {
+			t.Errorf("Expected classification error, got %v", errs)
+		}
+	})
+
+	t.Run("Allows raising flows with an exception", func(t *testing.T) {
+		f := newFlow("staging-to-prod", "staging", "production")
+		f.Exception = "Approved deployment pipeline access"
+
+		errs := rule.Validate(newFlowTaxonomy(f))
+
+		if len(errs) > 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Checks both directions of bidirectional flows", func(t *testing.T) {
+		f := newFlow("prod-staging-sync", "production", "staging")
+		f.Direction = domain.FlowBidirectional
+
+		errs := rule.Validate(newFlowTaxonomy(f))
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "bidirectional") {
+			t.Errorf("Expected bidirectional error, got %v", errs)
+		}
+	})
+
+	t.Run("Rejects unknown classification keys", func(t *testing.T) {
+		bad := NewLogicRuleFlowClassification(configdomain.FlowClassificationConfig{Enabled: true, Keys: []string{"owner"}}, createMockClassificationPlugin())
+
+		errs := bad.Validate(newFlowTaxonomy(newFlow("prod-to-staging", "production", "staging")))
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "owner") {
+			t.Errorf("Expected unknown key error, got %v", errs)
+		}
+	})
+
+	t.Run("Compares sensitivity when no keys are configured", func(t *testing.T) {
+		rule := NewLogicRuleFlowClassification(configdomain.FlowClassificationConfig{Enabled: true}, createMockClassificationPlugin())
+
+		errs := rule.Validate(newFlowTaxonomy(newFlow("staging-to-prod", "staging", "production")))
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "lower to higher sensitivity") {
+			t.Errorf("Expected sensitivity error, got %v", errs)
+		}
+	})
+
+	t.Run("Is not enabled by default", func(t *testing.T) {
+		ruleSet := NewLogicRuleSet(configdomain.DefaultConfig(), map[string]plugins.Plugin{})
+
+		if _, ok := ruleSet.LogicRules["FlowClassification"]; ok {
+			t.Error("Expected FlowClassification rule to be opt-in")
+		}
+		for _, result := range ruleSet.ValidateAll(newFlowTaxonomy(newFlow("staging-to-prod", "staging", "production"))) {
+			if result.RuleName == "FlowClassification" {
+				t.Errorf("Expected flows to validate without the classifications plugin, got %v", result.Errors)
+			}
+		}
+	})
+}
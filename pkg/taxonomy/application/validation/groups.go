@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+// ValidateGroups checks that every L1 group field references a defined group.
+func ValidateGroups(txy *domain.Taxonomy) []error {
+	var errs []error
+
+	ids := make([]string, 0, len(txy.SegL1s))
+	for id := range txy.SegL1s {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		group := txy.SegL1s[id].Group
+		if group == "" {
+			continue
+		}
+		if _, exists := txy.Groups[group]; !exists {
+			errs = append(errs, fmt.Errorf("L1 segment %s: group %s not found", id, group))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+func TestValidateGroups(t *testing.T) {
+	txy := &domain.Taxonomy{
+		Groups: map[string]domain.Group{"eu": {ID: "eu", Name: "Europe"}},
+		SegL1s: map[string]domain.Seg{
+			"prod-eu": {ID: "prod-eu", Group: "eu"},
+			"prod-us": {ID: "prod-us", Group: "us"},
+			"shared":  {ID: "shared"},
+		},
+	}
+
+	errs := ValidateGroups(txy)
+
+	if len(errs) != 1 || errs[0].Error() != "L1 segment prod-us: group us not found" {
+		t.Errorf("Expected only prod-us to fail, got %v", errs)
+	}
+}
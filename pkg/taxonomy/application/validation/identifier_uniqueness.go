@@ -6,7 +6,8 @@ import (
 	"github.com/kvql/bunsceal/pkg/domain"
 )
 
-// IdentifierUniquenessValidation validates that IDs and Names are unique across a collection of objects
+// IdentifierUniquenessValidation validates that IDs and aliases are unique across a collection of objects,
+// so every identifier resolves to exactly one object.
 // Returns a slice of error messages if validation fails, empty slice if all validations pass
 func IdentifierUniquenessValidation[T domain.UnqSegKeys](objects []T) []string {
 	idMap := make(map[string]bool)
@@ -23,5 +24,17 @@ func IdentifierUniquenessValidation[T domain.UnqSegKeys](objects []T) []string {
 		}
 	}
 
+	// Aliases are checked after all IDs so a clash is reported on the alias regardless of order
+	for _, item := range objects {
+		identifiers := item.GetIdentities()
+		for _, alias := range identifiers.Aliases {
+			if _, exists := idMap[alias]; exists {
+				validations = append(validations, fmt.Sprintf("alias for %s is not unique: %s", identifiers.Name, alias))
+			} else {
+				idMap[alias] = true
+			}
+		}
+	}
+
 	return validations
 }
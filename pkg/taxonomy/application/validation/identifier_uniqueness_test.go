@@ -38,3 +38,27 @@ func TestUniquenessValidator_DuplicateID(t *testing.T) {
 		t.Errorf("Expected validation message to contain '%s', got: %s", expectedSubstring, validations[0])
 	}
 }
+
+func TestUniquenessValidator_Aliases(t *testing.T) {
+	segments := []domain.Seg{
+		{ID: "test-1", Name: "Test 1", Aliases: []string{"old-1"}},
+		{ID: "test-2", Name: "Test 2", Aliases: []string{"old-2"}},
+	}
+	if validations := IdentifierUniquenessValidation(segments); len(validations) != 0 {
+		t.Errorf("Expected no validations for unique aliases, got %v", validations)
+	}
+
+	segments = []domain.Seg{
+		{ID: "test-1", Name: "Test 1", Aliases: []string{"old-1"}},
+		{ID: "test-2", Name: "Test 2", Aliases: []string{"old-1", "test-1"}},
+	}
+	validations := IdentifierUniquenessValidation(segments)
+	if len(validations) != 2 {
+		t.Fatalf("Expected 2 validation errors, got %d: %v", len(validations), validations)
+	}
+	for i, expected := range []string{"alias for Test 2 is not unique: old-1", "alias for Test 2 is not unique: test-1"} {
+		if validations[i] != expected {
+			t.Errorf("Expected %q, got %q", expected, validations[i])
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
 	"github.com/kvql/bunsceal/pkg/domain"
@@ -36,10 +39,27 @@ func NewLogicRuleSet(config configdomain.Config, pluginMap plugins.Plugins) *Log
 		rs.LogicRules["SharedService"] = NewLogicRuleSharedService(config.Rules.SharedService, classificationPlugin, compliancePlugin)
 	}
 
+	if config.Rules.StrictestSegment.Enabled {
+		rs.LogicRules["StrictestSegment"] = NewLogicRuleStrictestSegment(config.Rules.StrictestSegment, pluginMap["classifications"], pluginMap["compliance"])
+	}
+
 	if config.Rules.Uniqueness.Enabled {
 		rs.LogicRules["Uniqueness"] = NewLogicRuleUniqueness(config.Rules.Uniqueness)
 	}
 
+	if config.Rules.FlowClassification.Enabled {
+		rs.LogicRules["FlowClassification"] = NewLogicRuleFlowClassification(config.Rules.FlowClassification, pluginMap["classifications"])
+	}
+
+	for _, custom := range config.Rules.Custom {
+		name := "Custom:" + custom.ID
+		rule := NewLogicRuleCustom(custom)
+		if _, exists := rs.LogicRules[name]; exists {
+			rule.compileErr = fmt.Errorf("custom rule id %s is defined more than once", custom.ID)
+		}
+		rs.LogicRules[name] = rule
+	}
+
 	return rs
 }
 
@@ -60,92 +80,138 @@ func (rs *LogicRuleSet) ValidateAll(taxonomy *domain.Taxonomy) []ValidationResul
 	return results
 }
 
-// LogicRuleSharedService validates the shared-services environment.
-// This rule ensures the shared-services environment meets the strictest requirements.
-type LogicRuleSharedService struct {
-	config               configdomain.GeneralBooleanConfig
+// LogicRuleStrictestSegment validates that the configured segments sit at the top of every
+// configured classification order and, optionally, are in-scope for every compliance requirement.
+type LogicRuleStrictestSegment struct {
+	config               configdomain.StrictestSegmentConfig
 	classificationPlugin plugins.Plugin
 	compliancePlugin     plugins.Plugin
+	legacy               bool // one error per check and environment, as the SharedService rule reported
 }
 
-// NewLogicRuleSharedService creates a new SharedService validation rule.
-func NewLogicRuleSharedService(config configdomain.GeneralBooleanConfig, classificationPlugin plugins.Plugin, compliancePlugin plugins.Plugin) *LogicRuleSharedService {
-	return &LogicRuleSharedService{
+// NewLogicRuleStrictestSegment creates a new StrictestSegment validation rule.
+func NewLogicRuleStrictestSegment(config configdomain.StrictestSegmentConfig, classificationPlugin plugins.Plugin, compliancePlugin plugins.Plugin) *LogicRuleStrictestSegment {
+	return &LogicRuleStrictestSegment{
 		config:               config,
 		classificationPlugin: classificationPlugin,
 		compliancePlugin:     compliancePlugin,
 	}
 }
 
-// Validate checks that the shared-service environment meets all requirements.
+// NewLogicRuleSharedService creates the legacy SharedService rule: the L1 "shared-service" must have the
+// strictest sensitivity and criticality and be in-scope for all compliance requirements.
+// Its errors keep the legacy granularity: one for the classifications and one for compliance.
+func NewLogicRuleSharedService(config configdomain.GeneralBooleanConfig, classificationPlugin plugins.Plugin, compliancePlugin plugins.Plugin) *LogicRuleStrictestSegment {
+	rule := NewLogicRuleStrictestSegment(configdomain.StrictestSegmentConfig{
+		Enabled:              config.Enabled,
+		L1IDs:                []string{"shared-service"},
+		Keys:                 []string{"sensitivity", "criticality"},
+		RequireAllCompliance: true,
+	}, classificationPlugin, compliancePlugin)
+	rule.legacy = true
+	return rule
+}
+
+// Validate checks each configured segment, reporting every key that is not at its strictest value.
+// L2 segments are checked against their effective values for each parent.
 // Returns a slice of errors if validation fails, or an empty slice if valid.
-func (r *LogicRuleSharedService) Validate(taxonomy *domain.Taxonomy) []error {
+func (r *LogicRuleStrictestSegment) Validate(taxonomy *domain.Taxonomy) []error {
 	var errs []error
-	envName := "shared-service"
-	ns := "bunsceal.plugin.classifications"
-
-	if _, ok := taxonomy.SegL1s[envName]; !ok {
-		err := fmt.Errorf("%s environment not found", envName)
+	report := func(err error) {
 		o11y.Log.Printf("%v", err)
 		errs = append(errs, err)
-		return errs
 	}
 
-	sharedSeg := taxonomy.SegL1s[envName]
-
-	// Helper to read classification from plugin labels
-	getSensitivity := func(seg domain.Seg) string {
-		if val, exists := seg.LabelNamespaces[ns]["sensitivity"]; exists {
-			return val
-		}
-		return ""
-	}
-	getCriticality := func(seg domain.Seg) string {
-		if val, exists := seg.LabelNamespaces[ns]["criticality"]; exists {
-			return val
-		}
-		return ""
+	classifications, ok := r.classificationPlugin.(*plugins.ClassificationsPlugin)
+	if !ok && len(r.config.Keys) > 0 {
+		report(errors.New("strictest segment rule requires the classifications plugin"))
+		return errs
 	}
 
-	// Get expected highest values from classifications plugin
-	imageData := r.classificationPlugin.GetImageData()
-	expectedSens := ""
-	expectedCrit := ""
-	for _, data := range imageData {
-		if data.Key == "sensitivity" && len(data.OrderedValues) > 0 {
-			expectedSens = data.OrderedValues[0]
+	// Resolve the expected (first in order) value per key; no keys configured means all definitions
+	keys := r.config.Keys
+	if len(keys) == 0 && classifications != nil {
+		for key := range classifications.Config.Definitions {
+			keys = append(keys, key)
 		}
-		if data.Key == "criticality" && len(data.OrderedValues) > 0 {
-			expectedCrit = data.OrderedValues[0]
+		sort.Strings(keys)
+	}
+	expected := make(map[string]string, len(keys))
+	for _, key := range keys {
+		def, defined := classifications.Config.Definitions[key]
+		if !defined || len(def.Order) == 0 {
+			report(fmt.Errorf("strictest segment rule key %s has no classification order defined", key))
+			continue
 		}
+		expected[key] = def.Order[0]
 	}
 
-	if getSensitivity(sharedSeg) != expectedSens || getCriticality(sharedSeg) != expectedCrit {
-		err := fmt.Errorf("%s environment does not have the highest sensitivity or criticality", envName)
-		o11y.Log.Printf("%v", err)
-		errs = append(errs, err)
+	var compliance *plugins.CompliancePlugin
+	if r.config.RequireAllCompliance {
+		compliance, _ = r.compliancePlugin.(*plugins.CompliancePlugin)
 	}
 
-	// Check if shared-service has all compliance requirements defined via labels
-	if r.compliancePlugin != nil {
-		if cp, ok := r.compliancePlugin.(*plugins.CompliancePlugin); ok {
-			ns := cp.GetNamespace()
-			totalCompReqs := len(cp.Config.Definitions)
-			inScopeCount := 0
-
-			// Count how many compliance requirements are in-scope
-			for reqID := range cp.Config.Definitions {
-				if scope, exists := sharedSeg.LabelNamespaces[ns][reqID]; exists && scope == plugins.ScopeInScope {
-					inScopeCount++
+	check := func(seg domain.Seg, parentID, target string) {
+		keysFailed := false
+		for _, key := range keys {
+			want, ok := expected[key]
+			if !ok {
+				continue
+			}
+			got, _ := seg.GetNamespacedValue(parentID, classifications.GetNamespace(), key)
+			if got != want {
+				keysFailed = true
+				if !r.legacy {
+					report(fmt.Errorf("%s does not have the strictest %s: got %q, expected %q", target, key, got, want))
 				}
 			}
-
-			if inScopeCount != totalCompReqs {
-				err := fmt.Errorf("%s environment does not have all compliance requirements in-scope", envName)
-				o11y.Log.Printf("%v", err)
-				errs = append(errs, err)
+		}
+		if keysFailed && r.legacy {
+			report(fmt.Errorf("%s does not have the highest %s", target, strings.Join(keys, " or ")))
+		}
+		if compliance == nil {
+			return
+		}
+		reqIDs := make([]string, 0, len(compliance.Config.Definitions))
+		for reqID := range compliance.Config.Definitions {
+			reqIDs = append(reqIDs, reqID)
+		}
+		sort.Strings(reqIDs)
+		complianceFailed := false
+		for _, reqID := range reqIDs {
+			if scope, _ := seg.GetNamespacedValue(parentID, compliance.GetNamespace(), reqID); scope != plugins.ScopeInScope {
+				complianceFailed = true
+				if !r.legacy {
+					report(fmt.Errorf("%s is not in-scope for compliance requirement %s", target, reqID))
+				}
 			}
 		}
+		if complianceFailed && r.legacy {
+			report(fmt.Errorf("%s does not have all compliance requirements in-scope", target))
+		}
+	}
+
+	for _, l1ID := range r.config.L1IDs {
+		target := "L1 segment " + l1ID
+		if r.legacy {
+			target = l1ID + " environment"
+		}
+		seg, exists := taxonomy.SegL1s[l1ID]
+		if !exists {
+			report(fmt.Errorf("%s not found", target))
+			continue
+		}
+		check(seg, "", target)
+	}
+	for _, l2ID := range r.config.L2IDs {
+		seg, exists := taxonomy.SegsL2s[l2ID]
+		if !exists {
+			report(fmt.Errorf("L2 segment %s not found", l2ID))
+			continue
+		}
+		for _, l1ID := range seg.L1Parents {
+			check(seg, l1ID, fmt.Sprintf("L2 segment %s in L1 %s", l2ID, l1ID))
+		}
 	}
 
 	return errs
@@ -161,43 +227,54 @@ func NewLogicRuleUniqueness(config configdomain.UniquenessConfig) *LogicRuleUniq
 	return &LogicRuleUniqueness{config: config}
 }
 
-// Validate checks that the configured keys are unique across L1 and L2 segments.
+// Validate checks that the configured keys are unique among the segments of each level,
+// or only within each L0 group when the rule is scoped to groups.
 // Returns a slice of errors if validation fails, or an empty slice if valid.
 func (r *LogicRuleUniqueness) Validate(taxonomy *domain.Taxonomy) []error {
 	var errs []error
 
 	// Track which keys to check
 	for _, key := range r.config.CheckKeys {
-		// Check L1 names
-		l1Values := make(map[string]bool)
-		for _, seg := range taxonomy.SegL1s {
-			val, err := seg.GetKeyString(key)
-			if err != nil {
-				return []error{err}
-			}
-			if l1Values[val] {
-				err := fmt.Errorf("duplicate L1 name found: %s", val)
-				o11y.Log.Printf("%v", err)
-				errs = append(errs, err)
-			}
-			l1Values[val] = true
-		}
-
-		// Check L2 names
-		l2Values := make(map[string]bool)
-		for _, seg := range taxonomy.SegsL2s {
-			val, err := seg.GetKeyString(key)
-			if err != nil {
-				return []error{err}
-			}
-			if l2Values[val] {
-				err := fmt.Errorf("duplicate L2 name found: %s", val)
-				o11y.Log.Printf("%v", err)
-				errs = append(errs, err)
+		// Levels and segments in order, so duplicates are reported the same way on every run
+		for level := 1; level <= taxonomy.Depth(); level++ {
+			segs := taxonomy.Segs(level)
+			seen := make(map[string]bool)
+			for _, id := range taxonomy.SegIDs(level) {
+				seg := segs[id]
+				val, err := seg.GetKeyString(key)
+				if err != nil {
+					return []error{err}
+				}
+				for _, scope := range r.scopes(taxonomy, level, seg) {
+					if seen[scope+"/"+val] {
+						err := fmt.Errorf("duplicate L%d name found: %s%s", level, val, scopeSuffix(scope))
+						o11y.Log.Printf("%v", err)
+						errs = append(errs, err)
+					}
+					seen[scope+"/"+val] = true
+				}
 			}
-			l2Values[val] = true
 		}
 	}
 
 	return errs
 }
+
+// scopes returns the uniqueness scopes a segment belongs to. With group scope these are the
+// segment's groups, ungrouped segments share a single scope.
+func (r *LogicRuleUniqueness) scopes(taxonomy *domain.Taxonomy, level int, seg domain.Seg) []string {
+	if r.config.Scope != configdomain.UniquenessScopeGroup {
+		return []string{""}
+	}
+	if groups := taxonomy.SegGroups(level, seg); len(groups) > 0 {
+		return groups
+	}
+	return []string{""}
+}
+
+func scopeSuffix(scope string) string {
+	if scope == "" {
+		return ""
+	}
+	return " in group " + scope
+}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	configdomain "github.com/kvql/bunsceal/pkg/config/domain"
@@ -318,6 +319,102 @@ func TestLogicRuleSharedService_Validate(t *testing.T) {
 			t.Errorf("Expected 2 errors, got %d", len(errs))
 		}
 	})
+
+	t.Run("Keeps the legacy error messages", func(t *testing.T) {
+		txy := &domain.Taxonomy{
+			SegL1s: map[string]domain.Seg{
+				"shared-service": newSegWithClassification("shared-service", "Shared Service", "A", "2", []string{"req1"}),
+			},
+		}
+
+		mockPluginMap := createMockPluginMap()
+		rule := NewLogicRuleSharedService(configdomain.GeneralBooleanConfig{Enabled: true}, mockPluginMap["classifications"], mockPluginMap["compliance"])
+		errs := rule.Validate(txy)
+
+		if len(errs) != 2 ||
+			errs[0].Error() != "shared-service environment does not have the highest sensitivity or criticality" ||
+			errs[1].Error() != "shared-service environment does not have all compliance requirements in-scope" {
+			t.Errorf("Expected the legacy messages, got %v", errs)
+		}
+	})
+}
+
+func TestLogicRuleStrictestSegment_Validate(t *testing.T) {
+	mockPluginMap := createMockPluginMap()
+	newTaxonomy := func() *domain.Taxonomy {
+		hub := newSegWithClassification("hub", "Hub", "A", "1", []string{"req1"})
+		hub.L1Parents = []string{"core", "edge"}
+		override := domain.L1Overrides{Labels: []string{testNs + "/criticality:2"}}
+		override.ParseLabels()
+		hub.L1Overrides = map[string]domain.L1Overrides{"edge": override}
+		return &domain.Taxonomy{
+			SegL1s: map[string]domain.Seg{
+				"core": newSegWithClassification("core", "Core", "A", "1", []string{"req1", "req2"}),
+				"edge": newSegWithClassification("edge", "Edge", "B", "1", nil),
+			},
+			SegsL2s: map[string]domain.Seg{"hub": hub},
+		}
+	}
+
+	t.Run("Passes for configured L1 at top of order", func(t *testing.T) {
+		rule := NewLogicRuleStrictestSegment(configdomain.StrictestSegmentConfig{
+			Enabled: true, L1IDs: []string{"core"}, RequireAllCompliance: true,
+		}, mockPluginMap["classifications"], mockPluginMap["compliance"])
+
+		if errs := rule.Validate(newTaxonomy()); len(errs) != 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+
+	t.Run("Reports failed key and expected value", func(t *testing.T) {
+		rule := NewLogicRuleStrictestSegment(configdomain.StrictestSegmentConfig{
+			Enabled: true, L1IDs: []string{"edge"}, Keys: []string{"sensitivity"},
+		}, mockPluginMap["classifications"], mockPluginMap["compliance"])
+
+		errs := rule.Validate(newTaxonomy())
+
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got %v", errs)
+		}
+		msg := errs[0].Error()
+		if !strings.Contains(msg, "sensitivity") || !strings.Contains(msg, `got "B", expected "A"`) {
+			t.Errorf("Expected key and expected value in error, got %q", msg)
+		}
+	})
+
+	t.Run("Checks L2 effective values per parent", func(t *testing.T) {
+		rule := NewLogicRuleStrictestSegment(configdomain.StrictestSegmentConfig{
+			Enabled: true, L2IDs: []string{"hub"}, Keys: []string{"criticality"},
+		}, mockPluginMap["classifications"], mockPluginMap["compliance"])
+
+		errs := rule.Validate(newTaxonomy())
+
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "L2 segment hub in L1 edge") {
+			t.Errorf("Expected criticality error for hub under edge, got %v", errs)
+		}
+	})
+
+	t.Run("Reports each missing compliance requirement", func(t *testing.T) {
+		rule := NewLogicRuleStrictestSegment(configdomain.StrictestSegmentConfig{
+			Enabled: true, L2IDs: []string{"hub"}, Keys: []string{"sensitivity"}, RequireAllCompliance: true,
+		}, mockPluginMap["classifications"], mockPluginMap["compliance"])
+
+		errs := rule.Validate(newTaxonomy())
+
+		if len(errs) != 2 || !strings.Contains(errs[0].Error(), "req2") {
+			t.Errorf("Expected req2 error for each parent, got %v", errs)
+		}
+	})
+
+	t.Run("Reports unknown segments and keys", func(t *testing.T) {
+		rule := NewLogicRuleStrictestSegment(configdomain.StrictestSegmentConfig{
+			Enabled: true, L1IDs: []string{"missing"}, Keys: []string{"owner"},
+		}, mockPluginMap["classifications"], mockPluginMap["compliance"])
+
+		if errs := rule.Validate(newTaxonomy()); len(errs) != 2 {
+			t.Errorf("Expected errors for unknown key and segment, got %v", errs)
+		}
+	})
 }
 
 func TestLogicRuleUniqueness_Validate(t *testing.T) {
@@ -392,6 +489,67 @@ func TestLogicRuleUniqueness_Validate(t *testing.T) {
 			t.Errorf("Expected no errors (L1 and L2 can share names), got %d: %v", len(errs), errs)
 		}
 	})
+
+	t.Run("Reports duplicates in level order", func(t *testing.T) {
+		txy := &domain.Taxonomy{
+			SegL1s: map[string]domain.Seg{
+				"prod":    {ID: "prod", Name: "Production"},
+				"staging": {ID: "staging", Name: "Production"},
+			},
+			SegsL2s: map[string]domain.Seg{
+				"app1": {ID: "app1", Name: "Application"},
+				"app2": {ID: "app2", Name: "Application"},
+			},
+		}
+
+		rule := NewLogicRuleUniqueness(configdomain.UniquenessConfig{Enabled: true, CheckKeys: []string{"name"}})
+		for i := 0; i < 10; i++ {
+			errs := rule.Validate(txy)
+			if len(errs) != 2 ||
+				errs[0].Error() != "duplicate L1 name found: Production" ||
+				errs[1].Error() != "duplicate L2 name found: Application" {
+				t.Fatalf("Expected L1 duplicate before L2 duplicate, got %v", errs)
+			}
+		}
+	})
+
+	t.Run("Group scope only rejects duplicates within a group", func(t *testing.T) {
+		txy := &domain.Taxonomy{
+			SegL1s: map[string]domain.Seg{
+				"prod-eu": {ID: "prod-eu", Name: "Production EU", Group: "eu"},
+				"prod-us": {ID: "prod-us", Name: "Production US", Group: "us"},
+			},
+			SegsL2s: map[string]domain.Seg{
+				"app-eu":  {ID: "app-eu", Name: "Application", L1Parents: []string{"prod-eu"}},
+				"app-us":  {ID: "app-us", Name: "Application", L1Parents: []string{"prod-us"}},
+				"app-eu2": {ID: "app-eu2", Name: "Application", L1Parents: []string{"prod-eu"}},
+			},
+		}
+
+		rule := NewLogicRuleUniqueness(configdomain.UniquenessConfig{Enabled: true, CheckKeys: []string{"name"}, Scope: configdomain.UniquenessScopeGroup})
+		errs := rule.Validate(txy)
+
+		if len(errs) != 1 || errs[0].Error() != "duplicate L2 name found: Application in group eu" {
+			t.Errorf("Expected one duplicate in group eu, got %v", errs)
+		}
+	})
+
+	t.Run("Fails when duplicate names found below L2", func(t *testing.T) {
+		txy := &domain.Taxonomy{
+			SegL1s:  map[string]domain.Seg{"prod": {ID: "prod", Name: "Production"}},
+			SegsL2s: map[string]domain.Seg{"app": {ID: "app", Name: "Application", L1Parents: []string{"prod"}}},
+			Levels: map[string]map[string]domain.Seg{"3": {
+				"api":  {ID: "api", Name: "API", L1Parents: []string{"app"}},
+				"api2": {ID: "api2", Name: "API", L1Parents: []string{"app"}},
+			}},
+		}
+
+		errs := NewLogicRuleUniqueness(configdomain.UniquenessConfig{Enabled: true, CheckKeys: []string{"name"}}).Validate(txy)
+
+		if len(errs) != 1 || errs[0].Error() != "duplicate L3 name found: API" {
+			t.Errorf("Expected duplicate L3 name, got %v", errs)
+		}
+	})
 }
 
 func TestDefaultConfig_Rules(t *testing.T) {
@@ -0,0 +1,217 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+const (
+	InventoryFormatJSON = "json"
+	InventoryFormatCSV  = "csv"
+)
+
+// LoadInventory reads resources from a JSON or CSV inventory file.
+// The format is taken from the file extension when format is empty.
+func LoadInventory(path, format string) ([]domain.Resource, error) {
+	// #nosec G304 -- path comes from a CLI flag
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory %s: %w", path, err)
+	}
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch format {
+	case InventoryFormatJSON:
+		return ParseInventoryJSON(data)
+	case InventoryFormatCSV:
+		return ParseInventoryCSV(data)
+	default:
+		return nil, fmt.Errorf("unsupported inventory format %q, expected json or csv", format)
+	}
+}
+
+// ParseInventoryCSV parses a CSV inventory with a header row. The id column is required,
+// type is optional and every other column is a tag; empty cells are not tags.
+func ParseInventoryCSV(data []byte) ([]domain.Resource, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV inventory: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV inventory has no header row")
+	}
+	header := records[0]
+	idCol, typeCol := -1, -1
+	for i, name := range header {
+		switch name {
+		case "id":
+			idCol = i
+		case "type":
+			typeCol = i
+		}
+	}
+	if idCol < 0 {
+		return nil, errors.New("CSV inventory header has no id column")
+	}
+
+	resources := make([]domain.Resource, 0, len(records)-1)
+	for _, record := range records[1:] {
+		res := domain.Resource{ID: record[idCol], Tags: map[string]string{}}
+		for i, value := range record {
+			switch {
+			case i == idCol:
+			case i == typeCol:
+				res.Type = value
+			case value != "":
+				res.Tags[header[i]] = value
+			}
+		}
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
+
+// ParseInventoryJSON parses a Terraform plan JSON (terraform show -json), an AWS resource
+// tagging API export (ResourceTagMappingList) or a JSON array of resources.
+// Array entries are identified by id, arn or name, typed by type or assetType, and tagged by
+// tags or labels, either as a map or as a list of Key/Value pairs.
+func ParseInventoryJSON(data []byte) ([]domain.Resource, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON inventory: %w", err)
+	}
+
+	switch v := doc.(type) {
+	case []any:
+		return parseResourceList(v)
+	case map[string]any:
+		if planned, ok := v["planned_values"].(map[string]any); ok {
+			return planResources(planned), nil
+		}
+		if list, ok := v["ResourceTagMappingList"].([]any); ok {
+			return parseResourceList(list)
+		}
+	}
+	return nil, errors.New("unrecognised JSON inventory, expected a Terraform plan, a ResourceTagMappingList or an array of resources")
+}
+
+// ParseTerraformPlan parses the planned resources of a Terraform plan JSON (terraform show -json).
+// Data sources are skipped; resources are identified by address and typed by their Terraform type.
+func ParseTerraformPlan(data []byte) ([]domain.Resource, error) {
+	var plan struct {
+		PlannedValues map[string]any `json:"planned_values"`
+	}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("invalid Terraform plan JSON: %w", err)
+	}
+	if plan.PlannedValues == nil {
+		return nil, errors.New("invalid Terraform plan JSON: no planned_values, use the output of terraform show -json <planfile>")
+	}
+	return planResources(plan.PlannedValues), nil
+}
+
+func planResources(planned map[string]any) []domain.Resource {
+	resources := []domain.Resource{}
+	collectPlanModule(planned["root_module"], &resources)
+	return resources
+}
+
+// collectPlanModule appends the resources of a Terraform plan module and its child modules.
+// tags_all is used where present as it includes provider default tags.
+func collectPlanModule(module any, resources *[]domain.Resource) {
+	m, ok := module.(map[string]any)
+	if !ok {
+		return
+	}
+	list, _ := m["resources"].([]any)
+	for _, item := range list {
+		r, ok := item.(map[string]any)
+		if !ok || r["mode"] == "data" {
+			continue
+		}
+		values, _ := r["values"].(map[string]any)
+		tags := parseTags(values["tags_all"])
+		if len(tags) == 0 {
+			tags = parseTags(values["tags"])
+		}
+		if len(tags) == 0 {
+			tags = parseTags(values["labels"])
+		}
+		address, _ := r["address"].(string)
+		resType, _ := r["type"].(string)
+		*resources = append(*resources, domain.Resource{ID: address, Type: resType, Tags: tags})
+	}
+	children, _ := m["child_modules"].([]any)
+	for _, child := range children {
+		collectPlanModule(child, resources)
+	}
+}
+
+func parseResourceList(list []any) ([]domain.Resource, error) {
+	resources := make([]domain.Resource, 0, len(list))
+	for i, item := range list {
+		r, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("inventory entry %d is not an object", i)
+		}
+		res := domain.Resource{
+			ID:   firstString(r, "id", "arn", "ResourceARN", "name"),
+			Type: firstString(r, "type", "assetType"),
+			Tags: map[string]string{},
+		}
+		if res.ID == "" {
+			return nil, fmt.Errorf("inventory entry %d has no id, arn or name", i)
+		}
+		for _, key := range []string{"tags", "Tags", "labels"} {
+			for k, v := range parseTags(r[key]) {
+				res.Tags[k] = v
+			}
+		}
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
+
+// parseTags reads tags from a map or a list of {Key, Value} objects.
+func parseTags(raw any) map[string]string {
+	tags := map[string]string{}
+	switch v := raw.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if s, ok := value.(string); ok {
+				tags[k] = s
+			}
+		}
+	case []any:
+		for _, item := range v {
+			pair, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			key := firstString(pair, "Key", "key")
+			if key != "" {
+				tags[key] = firstString(pair, "Value", "value")
+			}
+		}
+	}
+	return tags
+}
+
+// firstString returns the first non-empty string value among keys.
+func firstString(m map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := m[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
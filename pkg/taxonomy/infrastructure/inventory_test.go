@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseInventoryCSV(t *testing.T) {
+	t.Run("Reads tags from non id/type columns", func(t *testing.T) {
+		data := "id,type,env,segment\ni-1,aws_instance,prod,app\ni-2,aws_instance,prod,\n"
+
+		resources, err := ParseInventoryCSV([]byte(data))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(resources) != 2 || resources[0].Type != "aws_instance" || resources[0].Tags["segment"] != "app" {
+			t.Errorf("Unexpected resources: %+v", resources)
+		}
+		if _, ok := resources[1].Tags["segment"]; ok {
+			t.Error("Expected empty cells not to be tags")
+		}
+	})
+
+	t.Run("Requires an id column", func(t *testing.T) {
+		if _, err := ParseInventoryCSV([]byte("name,env\na,prod\n")); err == nil {
+			t.Error("Expected error for missing id column")
+		}
+	})
+}
+
+func TestParseInventoryJSON(t *testing.T) {
+	t.Run("Reads Terraform plan resources including child modules", func(t *testing.T) {
+		data := `{"planned_values": {"root_module": {
+			"resources": [{"address": "aws_instance.web", "type": "aws_instance", "values": {"tags": {"env": "dev"}, "tags_all": {"env": "prod", "owner": "team"}}}],
+			"child_modules": [{"resources": [{"address": "module.db.google_sql_database_instance.db", "type": "google_sql_database_instance", "values": {"labels": {"env": "prod"}}}]}]
+		}}}`
+
+		resources, err := ParseInventoryJSON([]byte(data))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(resources) != 2 {
+			t.Fatalf("Expected 2 resources, got %+v", resources)
+		}
+		if resources[0].ID != "aws_instance.web" || resources[0].Tags["env"] != "prod" || resources[0].Tags["owner"] != "team" {
+			t.Errorf("Expected tags_all to be used, got %+v", resources[0])
+		}
+		if resources[1].Tags["env"] != "prod" {
+			t.Errorf("Expected labels from child module, got %+v", resources[1])
+		}
+	})
+
+	t.Run("Reads AWS tagging API export", func(t *testing.T) {
+		data := `{"ResourceTagMappingList": [{"ResourceARN": "arn:aws:s3:::bucket", "Tags": [{"Key": "env", "Value": "prod"}]}]}`
+
+		resources, err := ParseInventoryJSON([]byte(data))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(resources) != 1 || resources[0].ID != "arn:aws:s3:::bucket" || resources[0].Tags["env"] != "prod" {
+			t.Errorf("Unexpected resources: %+v", resources)
+		}
+	})
+
+	t.Run("Reads array of resources with map tags", func(t *testing.T) {
+		data := `[{"name": "projects/p/instances/vm", "assetType": "compute.googleapis.com/Instance", "labels": {"env": "prod"}}]`
+
+		resources, err := ParseInventoryJSON([]byte(data))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resources[0].Type != "compute.googleapis.com/Instance" || resources[0].Tags["env"] != "prod" {
+			t.Errorf("Unexpected resources: %+v", resources)
+		}
+	})
+
+	t.Run("Rejects entries without an identifier", func(t *testing.T) {
+		if _, err := ParseInventoryJSON([]byte(`[{"tags": {"env": "prod"}}]`)); err == nil {
+			t.Error("Expected error for entry without id")
+		}
+	})
+
+	t.Run("Rejects unrecognised documents", func(t *testing.T) {
+		if _, err := ParseInventoryJSON([]byte(`{"resources": []}`)); err == nil {
+			t.Error("Expected error for unrecognised document")
+		}
+	})
+}
+
+func TestLoadInventory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.txt")
+	if err := os.WriteFile(path, []byte("id\ni-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadInventory(path, ""); err == nil {
+		t.Error("Expected error for unknown extension")
+	}
+	resources, err := LoadInventory(path, InventoryFormatCSV)
+	if err != nil || len(resources) != 1 {
+		t.Errorf("Expected explicit format to override extension, got %v, %v", resources, err)
+	}
+}
+
+func TestParseTerraformPlan(t *testing.T) {
+	t.Run("Skips data sources", func(t *testing.T) {
+		data := `{"planned_values": {"root_module": {"resources": [
+			{"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "values": {"tags": {"env": "prod"}}},
+			{"address": "data.aws_ami.base", "mode": "data", "type": "aws_ami", "values": {}}
+		]}}}`
+
+		resources, err := ParseTerraformPlan([]byte(data))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(resources) != 1 || resources[0].ID != "aws_instance.web" {
+			t.Errorf("Expected only the managed resource, got %+v", resources)
+		}
+	})
+
+	t.Run("Rejects JSON that is not a plan", func(t *testing.T) {
+		if _, err := ParseTerraformPlan([]byte(`[{"id": "x"}]`)); err == nil {
+			t.Error("Expected error for non plan JSON")
+		}
+		if _, err := ParseTerraformPlan([]byte(`{"format_version": "1.2"}`)); err == nil {
+			t.Error("Expected error for plan without planned_values")
+		}
+	})
+}
@@ -2,18 +2,26 @@ package infrastructure
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/kvql/bunsceal/pkg/domain"
 	"github.com/kvql/bunsceal/pkg/o11y"
 )
 
-// GenLocalTaxonomy generates a local taxonomy file
-// TODO: Update JSON output to use ParsedLabels map instead of Labels array
-// See: Labels field should have json:"-" and ParsedLabels should have json:"labels,omitempty"
+const exportPrefix = "bunsceal-taxonomy-"
+
+// GenLocalTaxonomy generates a local taxonomy file.
+// Segments carry their declared labels and, as effective_labels, their labels after inheritance.
 func GenLocalTaxonomy(tx domain.Taxonomy, dir string) error {
+	return GenLocalTaxonomyFile(tx, dir, Version())
+}
+
+// GenLocalTaxonomyFile generates a local taxonomy file with the given file name.
+func GenLocalTaxonomyFile(tx domain.Taxonomy, dir, fileName string) error {
 	// Check if provided directory exists
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		// Create directory if it doesn't exist
@@ -28,7 +36,19 @@ func GenLocalTaxonomy(tx domain.Taxonomy, dir string) error {
 	if err != nil {
 		return err
 	}
-	filePath := dir + "/" + Version()
+	filePath := dir + "/" + fileName
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// WriteJSONFile writes v as indented JSON to filePath, creating the parent directory if needed.
+func WriteJSONFile(v any, filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
 	return os.WriteFile(filePath, data, 0600)
 }
 
@@ -39,7 +59,6 @@ func CheckGit() bool {
 }
 
 func Version() string {
-	prefix := "bunsceal-taxonomy"
 	gitCommit := os.Getenv("GITHUB_SHA")
 	if gitCommit == "" {
 		if CheckGit() {
@@ -54,7 +73,72 @@ func Version() string {
 			gitCommit = "unknown"
 		}
 	}
-	file := prefix + "-" + gitCommit[:7] + ".json"
+	file := ExportFileName(gitCommit)
 	o11y.Log.Println("Taxonomy version: ", file)
 	return file
 }
+
+// ExportFileName returns the export file name for a commit, using its abbreviated hash.
+func ExportFileName(commit string) string {
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return exportPrefix + commit + ".json"
+}
+
+// CommitFromExportFileName returns the abbreviated commit hash an export file name was generated from.
+func CommitFromExportFileName(name string) (string, bool) {
+	name = filepath.Base(name)
+	if !strings.HasPrefix(name, exportPrefix) || !strings.HasSuffix(name, ".json") {
+		return "", false
+	}
+	commit := strings.TrimSuffix(strings.TrimPrefix(name, exportPrefix), ".json")
+	if commit == "" || commit == "unknown" {
+		return "", false
+	}
+	return commit, true
+}
+
+// LoadTaxonomyExport reads a taxonomy exported by GenLocalTaxonomy, parsing segment labels.
+// Reports whether the export carries effective labels, see ParseTaxonomyExport.
+func LoadTaxonomyExport(filePath string) (domain.Taxonomy, bool, error) {
+	// #nosec G304 -- filePath comes from a CLI flag
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return domain.Taxonomy{}, false, fmt.Errorf("failed to read export %s: %w", filePath, err)
+	}
+	tx, resolved, err := ParseTaxonomyExport(data)
+	if err != nil {
+		return domain.Taxonomy{}, false, fmt.Errorf("invalid taxonomy export %s: %w", filePath, err)
+	}
+	return tx, resolved, nil
+}
+
+// ParseTaxonomyExport parses the JSON of a taxonomy exported by GenLocalTaxonomy, parsing segment labels.
+// Segments keep the effective labels the export carries. Exports written before they were published
+// only carry declared labels, resolved is false when a segment declares labels without effective
+// labels, so inheritance still has to be applied.
+func ParseTaxonomyExport(data []byte) (tx domain.Taxonomy, resolved bool, err error) {
+	if err = json.Unmarshal(data, &tx); err != nil {
+		return domain.Taxonomy{}, false, err
+	}
+	resolved = true
+	for level := 1; level <= tx.Depth(); level++ {
+		segs := tx.Segs(level)
+		for id, seg := range segs {
+			effective := seg.ParsedLabels
+			if err = seg.ParseLabels(); err != nil {
+				return domain.Taxonomy{}, false, err
+			}
+			if effective != nil {
+				if err = seg.SetEffectiveLabels(effective); err != nil {
+					return domain.Taxonomy{}, false, err
+				}
+			} else if len(seg.Labels) > 0 {
+				resolved = false
+			}
+			segs[id] = seg
+		}
+	}
+	return tx, resolved, nil
+}
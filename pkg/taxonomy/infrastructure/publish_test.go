@@ -0,0 +1,85 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+)
+
+func TestExportFileName(t *testing.T) {
+	name := ExportFileName("1d5b2c5e0a7c3f1b9d2e4a6c8b0d2f4a6c8e0a2b")
+	if name != "bunsceal-taxonomy-1d5b2c5.json" {
+		t.Errorf("Unexpected export file name %s", name)
+	}
+
+	commit, ok := CommitFromExportFileName("exports/" + name)
+	if !ok || commit != "1d5b2c5" {
+		t.Errorf("Expected commit 1d5b2c5, got %q, %v", commit, ok)
+	}
+	for _, invalid := range []string{"taxonomy.json", "bunsceal-taxonomy-unknown.json", "bunsceal-taxonomy-.json"} {
+		if _, ok := CommitFromExportFileName(invalid); ok {
+			t.Errorf("Expected %s not to name a commit", invalid)
+		}
+	}
+}
+
+func TestLoadTaxonomyExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+	data := `{"ApiVersion":"v1beta1","SegL1s":{"prod":{"id":"prod","name":"Production","labels":["bunsceal.plugin.classifications/sensitivity:high"]}}}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tx, _, err := LoadTaxonomyExport(path)
+	if err != nil {
+		t.Fatalf("Expected export to load, got %v", err)
+	}
+	if tx.ApiVersion != "v1beta1" {
+		t.Errorf("Expected api version v1beta1, got %s", tx.ApiVersion)
+	}
+	if got, err := tx.SegL1s["prod"].GetNamespacedValue("", "bunsceal.plugin.classifications", "sensitivity"); err != nil || got != "high" {
+		t.Errorf("Expected parsed labels, got sensitivity %q (%v)", got, err)
+	}
+	if _, _, err := LoadTaxonomyExport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected missing export to fail")
+	}
+}
+
+func TestGenLocalTaxonomyFile_EffectiveLabels(t *testing.T) {
+	l2 := domain.Seg{ID: "app", Name: "App", L1Parents: []string{"prod"}, Labels: []string{"team:payments"}}
+	if err := l2.ParseLabels(); err != nil {
+		t.Fatal(err)
+	}
+	// Inherited by the classifications plugin, not declared by the segment
+	l2.ParsedLabels["bunsceal.plugin.classifications/sensitivity"] = "A"
+	tx := domain.Taxonomy{
+		ApiVersion: "v1beta1",
+		SegL1s:     map[string]domain.Seg{"prod": {ID: "prod", Name: "Production"}},
+		SegsL2s:    map[string]domain.Seg{"app": l2},
+	}
+
+	dir := t.TempDir()
+	if err := GenLocalTaxonomyFile(tx, dir, "export.json"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "export.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, resolved, err := ParseTaxonomyExport(data)
+	if err != nil || !resolved {
+		t.Fatalf("Expected export with effective labels to be resolved, got %v %v", resolved, err)
+	}
+	if got, err := exported.SegsL2s["app"].GetNamespacedValue("prod", "bunsceal.plugin.classifications", "sensitivity"); err != nil || got != "A" {
+		t.Errorf("Expected inherited sensitivity in export, got %q (%v)", got, err)
+	}
+	if len(exported.SegsL2s["app"].Labels) != 1 {
+		t.Errorf("Expected declared labels unchanged, got %v", exported.SegsL2s["app"].Labels)
+	}
+
+	unresolved := `{"ApiVersion":"v1beta1","SegL1s":{"prod":{"id":"prod","name":"Production","labels":["team:platform"]}}}`
+	if _, resolved, err := ParseTaxonomyExport([]byte(unresolved)); err != nil || resolved {
+		t.Errorf("Expected export with declared labels only to be unresolved, got %v %v", resolved, err)
+	}
+}
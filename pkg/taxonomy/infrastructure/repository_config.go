@@ -0,0 +1,41 @@
+package infrastructure
+
+// Repository types selectable with the repository section of the config.
+const (
+	RepositoryTypeFs   = "fs"
+	RepositoryTypeFile = "file"
+	// RepositoryTypeSources merges the taxonomies of several sources.
+	RepositoryTypeSources = "sources"
+	// StdinPath as the repository path reads the taxonomy from stdin.
+	StdinPath = "-"
+)
+
+// Source types of a multi-source repository.
+const (
+	SourceTypeFs      = "fs"
+	SourceTypeGit     = "git"
+	SourceTypeFile    = "file"
+	SourceTypeTarball = "tarball"
+)
+
+// ConfigRepository selects where the taxonomy is read from: the fs_repository directories (fs, the default),
+// the single YAML file at Path (file), or the merged Sources (sources).
+// If relative path set for Path or a source path, config file path used as the base.
+type ConfigRepository struct {
+	Type    string         `yaml:"type,omitempty"`
+	Path    string         `yaml:"path,omitempty"`
+	Sources []ConfigSource `yaml:"sources,omitempty"`
+}
+
+// ConfigSource is one source of a multi-source repository.
+// Path is the taxonomy directory for fs and git sources (laid out as in fs_repository), the YAML file
+// for file sources and the .tar or .tar.gz archive of a taxonomy directory for tarball sources.
+// Levels and IDPrefixes restrict which segments the source may define; empty allows any.
+type ConfigSource struct {
+	Name       string   `yaml:"name"`
+	Type       string   `yaml:"type"`
+	Path       string   `yaml:"path"`
+	Ref        string   `yaml:"ref,omitempty"` // git sources only, default HEAD
+	Levels     []int    `yaml:"levels,omitempty"`
+	IDPrefixes []string `yaml:"id_prefixes,omitempty"`
+}
@@ -1,9 +1,13 @@
 package infrastructure
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/kvql/bunsceal/pkg/domain"
 	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
@@ -15,9 +19,50 @@ type LevelPaths map[string]string
 
 // ConfigFsReposistory If relative path set for TaxonomyDir, config file path used as the base.
 type ConfigFsReposistory struct {
-	TaxonomyDir string `yaml:"taxonomy_path,omitempty"`
-	L1Dir       string `yaml:"l1_dir"`
-	L2Dir       string `yaml:"l2_dir"`
+	TaxonomyDir  string `yaml:"taxonomy_path,omitempty"`
+	L1Dir        string `yaml:"l1_dir"`
+	L2Dir        string `yaml:"l2_dir"`
+	FlowsDir     string `yaml:"flows_dir"`
+	GroupsDir    string `yaml:"groups_dir,omitempty"`
+	TemplatesDir string `yaml:"templates_dir,omitempty"`
+	// LevelDirs names the directories of the levels below L2, starting at L3
+	LevelDirs []string `yaml:"level_dirs,omitempty"`
+	// FileGlobs limits the files read from taxonomy directories to names matching one of the globs.
+	// Unset defaults to *.yaml and *.yml, an explicit empty list ([]) reads every file
+	FileGlobs []string `yaml:"file_globs,omitempty"`
+	// FilenameMatchesID requires segment file names, without extension, to equal the segment ID
+	FilenameMatchesID bool `yaml:"filename_matches_id,omitempty"`
+}
+
+// MatchesFile reports whether the file at path is read as a taxonomy file, matching FileGlobs by base name.
+func (cfs *ConfigFsReposistory) MatchesFile(path string) bool {
+	if len(cfs.FileGlobs) == 0 {
+		return true
+	}
+	name := filepath.Base(path)
+	for _, glob := range cfs.FileGlobs {
+		if matched, _ := filepath.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFileName checks the file name of a segment matches its ID when FilenameMatchesID is set.
+func (cfs *ConfigFsReposistory) checkFileName(filePath string, seg domain.Seg) error {
+	if !cfs.FilenameMatchesID {
+		return nil
+	}
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	if name != seg.ID {
+		return fmt.Errorf("file name %s doesn't match segment id %s", filepath.Base(filePath), seg.ID)
+	}
+	return nil
+}
+
+// Depth returns the number of segment levels stored in the repository.
+func (cfs *ConfigFsReposistory) Depth() int {
+	return 2 + len(cfs.LevelDirs)
 }
 
 func (cfs *ConfigFsReposistory) GetLevelPath(level string) (string, error) {
@@ -28,7 +73,11 @@ func (cfs *ConfigFsReposistory) GetLevelPath(level string) (string, error) {
 	case "2":
 		path = filepath.Join(cfs.TaxonomyDir, cfs.L2Dir)
 	default:
-		return "", fmt.Errorf("no path found for level %s", level)
+		n, err := strconv.Atoi(level)
+		if err != nil || n < 3 || n > cfs.Depth() {
+			return "", fmt.Errorf("no path found for level %s", level)
+		}
+		path = filepath.Join(cfs.TaxonomyDir, cfs.LevelDirs[n-3])
 	}
 	return path, nil
 }
@@ -48,6 +97,16 @@ func NewFileSegRepository(schemaValidator *schemaValidation.SchemaValidator, cfg
 	}
 }
 
+// HasLevel reports whether the directory of the level exists.
+func (r *FileSegRepository) HasLevel(level string) bool {
+	path, err := r.config.GetLevelPath(level)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
 func (r *FileSegRepository) LoadLevel(level string) ([]domain.Seg, error) {
 	var segList []domain.Seg
 	var parseErrors []error
@@ -61,7 +120,7 @@ func (r *FileSegRepository) LoadLevel(level string) ([]domain.Seg, error) {
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() {
+		if !d.IsDir() && r.config.MatchesFile(path) {
 			seg, err := r.parseSegFile(path, level)
 			if err != nil {
 				o11y.Log.Printf("Error parsing file %s: %v\n", path, err)
@@ -90,20 +149,209 @@ func (r *FileSegRepository) parseSegFile(filePath string, level string) (domain.
 	if err != nil {
 		return domain.Seg{}, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
+	seg, err := parseSegData(r.schemaValidator, data, filePath, level)
+	if err != nil {
+		return domain.Seg{}, err
+	}
+	return seg, r.config.checkFileName(filePath, seg)
+}
 
-	if validationErr := r.schemaValidator.ValidateData(data, "seg-level.json"); validationErr != nil {
+// decodeStrict unmarshals a YAML document, rejecting keys without a matching field even where the
+// schema allows them. yaml.v3 rejects duplicate mapping keys on its own.
+func decodeStrict(data []byte, v interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// parseSegData validates and parses a segment document; filePath is only used in errors.
+func parseSegData(schemaValidator *schemaValidation.SchemaValidator, data []byte, filePath string, level string) (domain.Seg, error) {
+	if validationErr := schemaValidator.ValidateData(data, "seg-level.json"); validationErr != nil {
 		return domain.Seg{}, fmt.Errorf("schema validation failed for %s: %w", filePath, validationErr)
 	}
 
 	var seg domain.Seg
-	if err = yaml.Unmarshal(data, &seg); err != nil {
+	if err := decodeStrict(data, &seg); err != nil {
 		return domain.Seg{}, fmt.Errorf("failed to unmarshal file %s: %w", filePath, err)
 	}
 
 	// PostLoad handles defaults, validation, and label parsing
-	if err = seg.PostLoad(level); err != nil {
+	if err := seg.PostLoad(level); err != nil {
 		return domain.Seg{}, fmt.Errorf("PostLoad validation failed for %s: %w", filePath, err)
 	}
 
 	return seg, nil
 }
+
+// LoadFlows loads all flow documents from the flows directory.
+// A missing flows directory is not an error: taxonomies without flows load no flows.
+func (r *FileSegRepository) LoadFlows() ([]domain.Flow, error) {
+	if r.config.FlowsDir == "" {
+		return nil, nil
+	}
+	path := filepath.Join(r.config.TaxonomyDir, r.config.FlowsDir)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var flows []domain.Flow
+	var parseErrors []error
+	err := filepath.WalkDir(path, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && r.config.MatchesFile(path) {
+			doc, err := r.parseFlowsFile(path)
+			if err != nil {
+				o11y.Log.Printf("Error parsing file %s: %v\n", path, err)
+				parseErrors = append(parseErrors, err)
+				return nil // Continue walking despite parse error
+			}
+			flows = append(flows, doc.Flows...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d file(s) in directory: %s", len(parseErrors), path)
+	}
+	return flows, nil
+}
+
+func (r *FileSegRepository) parseFlowsFile(filePath string) (domain.FlowsDocument, error) {
+	// #nosec G304 -- filePath comes from config-specified taxonomy directory, not user input
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return domain.FlowsDocument{}, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	return parseFlowsData(r.schemaValidator, data, filePath)
+}
+
+// LoadGroups loads all group documents from the groups directory.
+// A missing groups directory is not an error: taxonomies without groups load no groups.
+func (r *FileSegRepository) LoadGroups() ([]domain.Group, error) {
+	if r.config.GroupsDir == "" {
+		return nil, nil
+	}
+	path := filepath.Join(r.config.TaxonomyDir, r.config.GroupsDir)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var groups []domain.Group
+	var parseErrors []error
+	err := filepath.WalkDir(path, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && r.config.MatchesFile(path) {
+			// #nosec G304 -- path comes from walking the configured groups directory
+			data, err := os.ReadFile(path)
+			if err == nil {
+				var doc domain.GroupsDocument
+				doc, err = parseGroupsData(r.schemaValidator, data, path)
+				groups = append(groups, doc.Groups...)
+			}
+			if err != nil {
+				o11y.Log.Printf("Error parsing file %s: %v\n", path, err)
+				parseErrors = append(parseErrors, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d file(s) in directory: %s", len(parseErrors), path)
+	}
+	return groups, nil
+}
+
+// LoadTemplates loads all segment templates from the templates directory, one template per file.
+// A missing templates directory is not an error: taxonomies without templates load no templates.
+func (r *FileSegRepository) LoadTemplates() ([]domain.Template, error) {
+	if r.config.TemplatesDir == "" {
+		return nil, nil
+	}
+	path := filepath.Join(r.config.TaxonomyDir, r.config.TemplatesDir)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var templates []domain.Template
+	var parseErrors []error
+	err := filepath.WalkDir(path, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && r.config.MatchesFile(path) {
+			// #nosec G304 -- path comes from walking the configured templates directory
+			data, err := os.ReadFile(path)
+			if err == nil {
+				var template domain.Template
+				template, err = parseTemplateData(r.schemaValidator, data, path)
+				templates = append(templates, template)
+			}
+			if err != nil {
+				o11y.Log.Printf("Error parsing file %s: %v\n", path, err)
+				parseErrors = append(parseErrors, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d file(s) in directory: %s", len(parseErrors), path)
+	}
+	return templates, nil
+}
+
+// parseTemplateData validates and parses a template document; filePath is only used in errors.
+func parseTemplateData(schemaValidator *schemaValidation.SchemaValidator, data []byte, filePath string) (domain.Template, error) {
+	if validationErr := schemaValidator.ValidateData(data, "template.json"); validationErr != nil {
+		return domain.Template{}, fmt.Errorf("schema validation failed for %s: %w", filePath, validationErr)
+	}
+
+	var template domain.Template
+	if err := decodeStrict(data, &template); err != nil {
+		return domain.Template{}, fmt.Errorf("failed to unmarshal file %s: %w", filePath, err)
+	}
+	return template, nil
+}
+
+// parseGroupsData validates and parses a groups document; filePath is only used in errors.
+func parseGroupsData(schemaValidator *schemaValidation.SchemaValidator, data []byte, filePath string) (domain.GroupsDocument, error) {
+	if validationErr := schemaValidator.ValidateData(data, "groups.json"); validationErr != nil {
+		return domain.GroupsDocument{}, fmt.Errorf("schema validation failed for %s: %w", filePath, validationErr)
+	}
+
+	var doc domain.GroupsDocument
+	if err := decodeStrict(data, &doc); err != nil {
+		return domain.GroupsDocument{}, fmt.Errorf("failed to unmarshal file %s: %w", filePath, err)
+	}
+	return doc, nil
+}
+
+// parseFlowsData validates and parses a flows document; filePath is only used in errors.
+func parseFlowsData(schemaValidator *schemaValidation.SchemaValidator, data []byte, filePath string) (domain.FlowsDocument, error) {
+	if validationErr := schemaValidator.ValidateData(data, "flows.json"); validationErr != nil {
+		return domain.FlowsDocument{}, fmt.Errorf("schema validation failed for %s: %w", filePath, validationErr)
+	}
+
+	var doc domain.FlowsDocument
+	if err := decodeStrict(data, &doc); err != nil {
+		return domain.FlowsDocument{}, fmt.Errorf("failed to unmarshal file %s: %w", filePath, err)
+	}
+	return doc, nil
+}
@@ -1,6 +1,9 @@
 package infrastructure
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/kvql/bunsceal/pkg/domain"
@@ -172,6 +175,25 @@ func TestFileSegRepository_parseSegFile(t *testing.T) {
 		}
 	})
 
+	t.Run("Parses aliases", func(t *testing.T) {
+		seg := testhelpers.NewSegL1("test", "Test Environment", "A", "1", nil)
+		seg.Aliases = []string{"old-test"}
+
+		files := NewTestFiles(t)
+		tmpDir := files.CreateSegFiles([]domain.Seg{seg})
+
+		validator := schemaValidation.MustCreateValidator(t)
+		repository := NewFileSegRepository(validator, testConfig(tmpDir))
+		result, err := repository.parseSegFile(tmpDir+"/seg-0.yaml", "1")
+
+		if err != nil {
+			t.Fatalf("parseSegFile: unexpected error: %v", err)
+		}
+		if len(result.Aliases) != 1 || result.Aliases[0] != "old-test" {
+			t.Errorf("Seg aliases: got %v, want [old-test]", result.Aliases)
+		}
+	})
+
 	t.Run("Fails validation for invalid data", func(t *testing.T) {
 		invalidYAML := `name: "Test"
 id: "test"
@@ -211,3 +233,255 @@ id: "test"
 		}
 	})
 }
+
+func TestFileSegRepository_LoadFlows(t *testing.T) {
+	validFlows := `version: "1.0"
+flows:
+  - id: app-to-db
+    level: 2
+    source: app
+    destination: db
+    protocol: tcp
+    ports: ["5432"]
+    direction: one-way
+    rationale: Application reads from its database
+`
+
+	writeFlows := func(t *testing.T, content string) string {
+		tmpDir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(tmpDir, "flows"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, "flows", "app.yaml"), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return tmpDir
+	}
+
+	t.Run("Returns no flows when directory is missing", func(t *testing.T) {
+		cfg := testConfig(t.TempDir())
+		cfg.FlowsDir = "flows"
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), cfg)
+
+		flows, err := repository.LoadFlows()
+		if err != nil || len(flows) != 0 {
+			t.Errorf("Expected no flows and no error, got %v, %v", flows, err)
+		}
+	})
+
+	t.Run("Returns no flows without a flows directory configured", func(t *testing.T) {
+		cfg := testConfig(writeFlows(t, validFlows))
+		cfg.FlowsDir = ""
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), cfg)
+
+		flows, err := repository.LoadFlows()
+		if err != nil || len(flows) != 0 {
+			t.Errorf("Expected no flows and no error, got %v, %v", flows, err)
+		}
+	})
+
+	t.Run("Loads flows from files", func(t *testing.T) {
+		cfg := testConfig(writeFlows(t, validFlows))
+		cfg.FlowsDir = "flows"
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), cfg)
+
+		flows, err := repository.LoadFlows()
+		if err != nil {
+			t.Fatalf("LoadFlows: unexpected error: %v", err)
+		}
+		if len(flows) != 1 || flows[0].ID != "app-to-db" || flows[0].Ports[0] != "5432" {
+			t.Errorf("Expected app-to-db flow, got %+v", flows)
+		}
+	})
+
+	t.Run("Fails schema validation without rationale", func(t *testing.T) {
+		cfg := testConfig(writeFlows(t, strings.Replace(validFlows, "    rationale: Application reads from its database\n", "", 1)))
+		cfg.FlowsDir = "flows"
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), cfg)
+
+		if _, err := repository.LoadFlows(); err == nil {
+			t.Error("Expected schema validation error")
+		}
+	})
+}
+
+func TestFileSegRepository_LoadGroups(t *testing.T) {
+	writeGroups := func(t *testing.T, content string) ConfigFsReposistory {
+		tmpDir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(tmpDir, "groups"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, "groups", "regions.yaml"), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg := testConfig(tmpDir)
+		cfg.GroupsDir = "groups"
+		return cfg
+	}
+
+	t.Run("Returns no groups when directory is missing", func(t *testing.T) {
+		cfg := testConfig(t.TempDir())
+		cfg.GroupsDir = "groups"
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), cfg)
+
+		groups, err := repository.LoadGroups()
+		if err != nil || len(groups) != 0 {
+			t.Errorf("Expected no groups and no error, got %v, %v", groups, err)
+		}
+	})
+
+	t.Run("Loads groups from files", func(t *testing.T) {
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), writeGroups(t, "groups:\n  - id: eu\n    name: Europe\n"))
+
+		groups, err := repository.LoadGroups()
+		if err != nil {
+			t.Fatalf("LoadGroups: unexpected error: %v", err)
+		}
+		if len(groups) != 1 || groups[0].ID != "eu" || groups[0].Name != "Europe" {
+			t.Errorf("Expected eu group, got %+v", groups)
+		}
+	})
+
+	t.Run("Fails schema validation without name", func(t *testing.T) {
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), writeGroups(t, "groups:\n  - id: eu\n"))
+
+		if _, err := repository.LoadGroups(); err == nil {
+			t.Error("Expected schema validation error")
+		}
+	})
+}
+
+func TestFileSegRepository_LoadTemplates(t *testing.T) {
+	writeTemplate := func(t *testing.T, content string) ConfigFsReposistory {
+		tmpDir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(tmpDir, "templates"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, "templates", "staging.yaml"), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg := testConfig(tmpDir)
+		cfg.TemplatesDir = "templates"
+		return cfg
+	}
+
+	t.Run("Loads templates from files", func(t *testing.T) {
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), writeTemplate(t, `id: staging
+extends: [base]
+overrides:
+  staging:
+    labels: ["ns/key:value"]
+`))
+
+		templates, err := repository.LoadTemplates()
+		if err != nil {
+			t.Fatalf("LoadTemplates: unexpected error: %v", err)
+		}
+		if len(templates) != 1 || templates[0].Extends[0] != "base" || templates[0].Overrides["staging"].Labels[0] != "ns/key:value" {
+			t.Errorf("Expected staging template, got %+v", templates)
+		}
+	})
+
+	t.Run("Fails schema validation with segment fields", func(t *testing.T) {
+		repository := NewFileSegRepository(schemaValidation.MustCreateValidator(t), writeTemplate(t, "id: staging\nl1_parents: [staging]\n"))
+
+		if _, err := repository.LoadTemplates(); err == nil {
+			t.Error("Expected schema validation error")
+		}
+	})
+}
+
+func TestDecodeStrict(t *testing.T) {
+	t.Run("Rejects unknown fields", func(t *testing.T) {
+		var seg domain.Seg
+		if err := decodeStrict([]byte("id: prod\nl1_overides: {}\n"), &seg); err == nil {
+			t.Error("Expected error for unknown field")
+		}
+	})
+
+	t.Run("Rejects duplicate keys", func(t *testing.T) {
+		var seg domain.Seg
+		if err := decodeStrict([]byte("id: prod\nname: Production\nid: staging\n"), &seg); err == nil {
+			t.Error("Expected error for duplicate key")
+		}
+	})
+
+	t.Run("Accepts the schema version", func(t *testing.T) {
+		var seg domain.Seg
+		if err := decodeStrict([]byte("version: \"1.0\"\nid: prod\n"), &seg); err != nil || seg.ID != "prod" {
+			t.Errorf("Expected prod, got %+v, %v", seg, err)
+		}
+	})
+}
+
+func TestFileSegRepository_FileSelection(t *testing.T) {
+	const l1 = "name: Production\nid: prod\ndescription: Description long enough for the schema, which asks for at least seventy characters.\n"
+	writeFiles := func(t *testing.T, files map[string]string) string {
+		tmpDir := t.TempDir()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return tmpDir
+	}
+
+	t.Run("Ignores files not matching the globs", func(t *testing.T) {
+		tmpDir := writeFiles(t, map[string]string{
+			"prod.yaml":       l1,
+			"README.md":       "# Environments\n",
+			".prod.yaml.swp":  "binary",
+			"prod.yaml~":      l1,
+			"staging.yml.bak": l1,
+		})
+		cfg := testConfig(tmpDir)
+		cfg.FileGlobs = []string{"*.yaml", "*.yml"}
+
+		segs, err := NewFileSegRepository(schemaValidation.MustCreateValidator(t), cfg).LoadLevel("1")
+		if err != nil || len(segs) != 1 {
+			t.Errorf("Expected only prod.yaml to load, got %+v, %v", segs, err)
+		}
+	})
+
+	t.Run("Reads every file without globs", func(t *testing.T) {
+		tmpDir := writeFiles(t, map[string]string{"prod.yaml": l1, "README.md": "# Environments\n"})
+
+		if _, err := NewFileSegRepository(schemaValidation.MustCreateValidator(t), testConfig(tmpDir)).LoadLevel("1"); err == nil {
+			t.Error("Expected README.md to fail parsing")
+		}
+	})
+
+	t.Run("Requires file names to match IDs when enabled", func(t *testing.T) {
+		cfg := testConfig(writeFiles(t, map[string]string{"production.yaml": l1}))
+		validator := schemaValidation.MustCreateValidator(t)
+
+		if _, err := NewFileSegRepository(validator, cfg).LoadLevel("1"); err != nil {
+			t.Errorf("Expected mismatched name to load with the rule disabled, got %v", err)
+		}
+		cfg.FilenameMatchesID = true
+		if _, err := NewFileSegRepository(validator, cfg).LoadLevel("1"); err == nil {
+			t.Error("Expected error for file name not matching id")
+		}
+		cfg = testConfig(writeFiles(t, map[string]string{"prod.yaml": l1}))
+		cfg.FilenameMatchesID = true
+		if segs, err := NewFileSegRepository(validator, cfg).LoadLevel("1"); err != nil || len(segs) != 1 {
+			t.Errorf("Expected prod.yaml to load, got %+v, %v", segs, err)
+		}
+	})
+}
+
+func TestConfigFsReposistory_GetLevelPath(t *testing.T) {
+	cfg := ConfigFsReposistory{TaxonomyDir: "tax", L1Dir: "envs", L2Dir: "zones", LevelDirs: []string{"tiers"}}
+
+	for level, expected := range map[string]string{"1": "tax/envs", "2": "tax/zones", "3": "tax/tiers"} {
+		path, err := cfg.GetLevelPath(level)
+		if err != nil || path != expected {
+			t.Errorf("GetLevelPath(%s) = %q, %v; want %q", level, path, err, expected)
+		}
+	}
+	for _, level := range []string{"0", "4", "x"} {
+		if _, err := cfg.GetLevelPath(level); err == nil {
+			t.Errorf("Expected no path for level %s", level)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
+	"github.com/kvql/bunsceal/pkg/o11y"
+)
+
+// GitSegRepository implements taxonomy.SegRepository for a commit in a local git repository.
+// Files are read from the object database with git cat-file, so no checkout is needed.
+// Taxonomy paths in the config are resolved as they would be in the working tree, relative
+// paths against the process working directory.
+type GitSegRepository struct {
+	schemaValidator *schemaValidation.SchemaValidator
+	config          ConfigFsReposistory
+	repoDir         string
+	topLevel        string
+	commit          string
+}
+
+// NewGitSegRepository creates a repository reading the taxonomy at ref from the git repository containing repoDir.
+func NewGitSegRepository(schemaValidator *schemaValidation.SchemaValidator, cfg ConfigFsReposistory, repoDir, ref string) (*GitSegRepository, error) {
+	r := &GitSegRepository{schemaValidator: schemaValidator, config: cfg, repoDir: repoDir}
+	commit, err := r.git("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown git ref %q", ref)
+	}
+	r.commit = strings.TrimSpace(string(commit))
+	top, err := r.git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	r.topLevel = strings.TrimSpace(string(top))
+	return r, nil
+}
+
+// Commit returns the full hash of the commit the repository reads from.
+func (r *GitSegRepository) Commit() string {
+	return r.commit
+}
+
+func (r *GitSegRepository) git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.repoDir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// treePath converts a working tree path to its path from the repository root.
+// Relative paths are resolved against the process working directory.
+func (r *GitSegRepository) treePath(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(r.topLevel, resolveSymlinks(abs))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the git repository", p)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// resolveSymlinks resolves the symlinks of the longest existing prefix of the absolute path p,
+// as git reports the top level with symlinks resolved. Files only in the commit may not exist.
+func resolveSymlinks(p string) string {
+	rest := ""
+	for dir := p; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		if dir == filepath.Dir(dir) {
+			return p
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+	}
+}
+
+// listFiles returns the taxonomy files under the repository root path treeDir at the commit.
+func (r *GitSegRepository) listFiles(treeDir string) ([]string, error) {
+	pathspec := treeDir + "/"
+	if treeDir == "." {
+		pathspec = "."
+	}
+	out, err := r.git("ls-tree", "-r", "-z", "--name-only", "--full-tree", "--full-name", r.commit, "--", pathspec)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" && r.config.MatchesFile(name) {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// readDir returns the contents of every file under dir at the commit, keyed by path.
+// A directory missing from the commit has no files.
+func (r *GitSegRepository) readDir(dir string) ([]string, map[string][]byte, error) {
+	treeDir, err := r.treePath(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	files, err := r.listFiles(treeDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, nil
+	}
+
+	var input bytes.Buffer
+	for _, name := range files {
+		input.WriteString(r.commit + ":" + name + "\n")
+	}
+	cmd := exec.Command("git", "-C", r.repoDir, "cat-file", "--batch")
+	cmd.Stdin = &input
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("git cat-file: %w", err)
+	}
+
+	contents := make(map[string][]byte, len(files))
+	reader := bufio.NewReader(bytes.NewReader(out))
+	for _, name := range files {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, nil, fmt.Errorf("git cat-file: reading %s: %w", name, err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 || fields[1] != "blob" {
+			return nil, nil, fmt.Errorf("git cat-file: unexpected object for %s: %s", name, strings.TrimSpace(header))
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, nil, fmt.Errorf("git cat-file: invalid size for %s", name)
+		}
+		data := make([]byte, size+1) // content is followed by a newline
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, nil, fmt.Errorf("git cat-file: reading %s: %w", name, err)
+		}
+		contents[name] = data[:size]
+	}
+	return files, contents, nil
+}
+
+// HasLevel reports whether the directory of the level has taxonomy files at the commit.
+func (r *GitSegRepository) HasLevel(level string) bool {
+	dir, err := r.config.GetLevelPath(level)
+	if err != nil {
+		return false
+	}
+	treeDir, err := r.treePath(dir)
+	if err != nil {
+		return false
+	}
+	files, err := r.listFiles(treeDir)
+	return err == nil && len(files) > 0
+}
+
+func (r *GitSegRepository) LoadLevel(level string) ([]domain.Seg, error) {
+	dir, err := r.config.GetLevelPath(level)
+	if err != nil {
+		return nil, err
+	}
+	files, contents, err := r.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("directory %s not found at commit %s", dir, r.commit)
+	}
+
+	var segList []domain.Seg
+	var parseErrors []error
+	for _, name := range files {
+		seg, err := parseSegData(r.schemaValidator, contents[name], r.commit[:7]+":"+name, level)
+		if err == nil {
+			err = r.config.checkFileName(name, seg)
+		}
+		if err != nil {
+			o11y.Log.Printf("Error parsing file %s: %v\n", name, err)
+			parseErrors = append(parseErrors, err)
+			continue
+		}
+		segList = append(segList, seg)
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d file(s) in directory: %s at commit %s", len(parseErrors), dir, r.commit)
+	}
+	return segList, nil
+}
+
+// LoadFlows loads all flow documents from the flows directory at the commit.
+// A missing flows directory is not an error, matching FileSegRepository.
+func (r *GitSegRepository) LoadFlows() ([]domain.Flow, error) {
+	if r.config.FlowsDir == "" {
+		return nil, nil
+	}
+	dir := filepath.Join(r.config.TaxonomyDir, r.config.FlowsDir)
+	files, contents, err := r.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var flows []domain.Flow
+	var parseErrors []error
+	for _, name := range files {
+		doc, err := parseFlowsData(r.schemaValidator, contents[name], r.commit[:7]+":"+name)
+		if err != nil {
+			o11y.Log.Printf("Error parsing file %s: %v\n", name, err)
+			parseErrors = append(parseErrors, err)
+			continue
+		}
+		flows = append(flows, doc.Flows...)
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d file(s) in directory: %s at commit %s", len(parseErrors), dir, r.commit)
+	}
+	return flows, nil
+}
+
+// LoadGroups loads all group documents from the groups directory at the commit.
+func (r *GitSegRepository) LoadGroups() ([]domain.Group, error) {
+	if r.config.GroupsDir == "" {
+		return nil, nil
+	}
+	dir := filepath.Join(r.config.TaxonomyDir, r.config.GroupsDir)
+	files, contents, err := r.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []domain.Group
+	var parseErrors []error
+	for _, name := range files {
+		doc, err := parseGroupsData(r.schemaValidator, contents[name], r.commit[:7]+":"+name)
+		if err != nil {
+			o11y.Log.Printf("Error parsing file %s: %v\n", name, err)
+			parseErrors = append(parseErrors, err)
+			continue
+		}
+		groups = append(groups, doc.Groups...)
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d file(s) in directory: %s at commit %s", len(parseErrors), dir, r.commit)
+	}
+	return groups, nil
+}
+
+// LoadTemplates loads all segment templates from the templates directory at the commit.
+func (r *GitSegRepository) LoadTemplates() ([]domain.Template, error) {
+	if r.config.TemplatesDir == "" {
+		return nil, nil
+	}
+	dir := filepath.Join(r.config.TaxonomyDir, r.config.TemplatesDir)
+	files, contents, err := r.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []domain.Template
+	var parseErrors []error
+	for _, name := range files {
+		template, err := parseTemplateData(r.schemaValidator, contents[name], r.commit[:7]+":"+name)
+		if err != nil {
+			o11y.Log.Printf("Error parsing file %s: %v\n", name, err)
+			parseErrors = append(parseErrors, err)
+			continue
+		}
+		templates = append(templates, template)
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d file(s) in directory: %s at commit %s", len(parseErrors), dir, r.commit)
+	}
+	return templates, nil
+}
+
+// ReadFile returns the contents of the file at p at the commit.
+func (r *GitSegRepository) ReadFile(p string) ([]byte, error) {
+	treePath, err := r.treePath(p)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.git("cat-file", "blob", r.commit+":"+treePath)
+	if err != nil {
+		return nil, fmt.Errorf("file %s not found at commit %s", p, r.commit)
+	}
+	return data, nil
+}
@@ -0,0 +1,138 @@
+package infrastructure
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
+	"github.com/kvql/bunsceal/pkg/domain/testhelpers"
+)
+
+// newGitTaxonomyRepo commits L1 segments to a new git repository and returns its path.
+func newGitTaxonomyRepo(t *testing.T, segs []domain.Seg) string {
+	t.Helper()
+	if !CheckGit() {
+		t.Skip("git not available")
+	}
+	repoDir := t.TempDir()
+	l1Dir := filepath.Join(repoDir, "taxonomy", "l1")
+	if err := os.MkdirAll(l1Dir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	src := NewTestFiles(t).CreateSegFiles(segs)
+	entries, _ := os.ReadDir(src)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(l1Dir, entry.Name()), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "taxonomy"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return repoDir
+}
+
+func TestGitSegRepository(t *testing.T) {
+	repoDir := newGitTaxonomyRepo(t, []domain.Seg{
+		testhelpers.NewSegL1("env-one", "Environment 1", "A", "1", nil),
+		testhelpers.NewSegL1("env-two", "Environment 2", "B", "2", nil),
+	})
+	cfg := ConfigFsReposistory{TaxonomyDir: filepath.Join(repoDir, "taxonomy"), L1Dir: "l1", L2Dir: "l2", FlowsDir: "flows"}
+	validator := schemaValidation.MustCreateValidator(t)
+
+	t.Run("Loads segments from the commit, not the working tree", func(t *testing.T) {
+		if err := os.RemoveAll(filepath.Join(repoDir, "taxonomy")); err != nil {
+			t.Fatal(err)
+		}
+		repository, err := NewGitSegRepository(validator, cfg, repoDir, "HEAD")
+		if err != nil {
+			t.Fatalf("NewGitSegRepository: %v", err)
+		}
+
+		segs, err := repository.LoadLevel("1")
+		if err != nil {
+			t.Fatalf("LoadLevel: unexpected error: %v", err)
+		}
+		if len(segs) != 2 || segs[0].Level != "1" {
+			t.Errorf("Expected 2 L1 segments, got %+v", segs)
+		}
+		if len(repository.Commit()) != 40 {
+			t.Errorf("Expected full commit hash, got %q", repository.Commit())
+		}
+	})
+
+	t.Run("Missing level directory is an error, missing flows are not", func(t *testing.T) {
+		repository, _ := NewGitSegRepository(validator, cfg, repoDir, "HEAD")
+
+		if _, err := repository.LoadLevel("2"); err == nil {
+			t.Error("Expected error for missing L2 directory")
+		}
+		flows, err := repository.LoadFlows()
+		if err != nil || len(flows) != 0 {
+			t.Errorf("Expected no flows, got %v, %v", flows, err)
+		}
+	})
+
+	t.Run("Reads single files from the commit", func(t *testing.T) {
+		repository, _ := NewGitSegRepository(validator, cfg, repoDir, "HEAD")
+
+		data, err := repository.ReadFile(filepath.Join(repoDir, "taxonomy", "l1", "seg-0.yaml"))
+		if err != nil || len(data) == 0 {
+			t.Errorf("Expected seg-0.yaml contents, got %q, %v", data, err)
+		}
+		if _, err := repository.ReadFile(filepath.Join(repoDir, "taxonomy", "missing.yaml")); err == nil {
+			t.Error("Expected error for missing file")
+		}
+	})
+
+	t.Run("Resolves relative paths from a subdirectory", func(t *testing.T) {
+		subDir := filepath.Join(repoDir, "sub")
+		if err := os.MkdirAll(subDir, 0750); err != nil {
+			t.Fatal(err)
+		}
+		originalWd, err := os.Getwd()
+		if err != nil {
+			t.Fatalf("Failed to get working directory: %v", err)
+		}
+		if err := os.Chdir(subDir); err != nil {
+			t.Fatalf("Failed to change to subdirectory: %v", err)
+		}
+		defer os.Chdir(originalWd)
+
+		relCfg := cfg
+		relCfg.TaxonomyDir = filepath.Join("..", "taxonomy")
+		repository, err := NewGitSegRepository(validator, relCfg, ".", "HEAD")
+		if err != nil {
+			t.Fatalf("NewGitSegRepository: %v", err)
+		}
+
+		if !repository.HasLevel("1") {
+			t.Error("Expected L1 to be found from a subdirectory")
+		}
+		if segs, err := repository.LoadLevel("1"); err != nil || len(segs) != 2 {
+			t.Errorf("Expected 2 L1 segments, got %d, %v", len(segs), err)
+		}
+		if data, err := repository.ReadFile(filepath.Join("..", "taxonomy", "l1", "seg-0.yaml")); err != nil || len(data) == 0 {
+			t.Errorf("Expected seg-0.yaml contents, got %q, %v", data, err)
+		}
+	})
+
+	t.Run("Rejects unknown refs", func(t *testing.T) {
+		if _, err := NewGitSegRepository(validator, cfg, repoDir, "no-such-branch"); err == nil {
+			t.Error("Expected error for unknown ref")
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package infrastructure
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
+	"github.com/kvql/bunsceal/pkg/o11y"
+	"gopkg.in/yaml.v3"
+)
+
+// Document kinds of a single-file taxonomy, besides the segment levels L1, L2, L3...
+const (
+	KindFlows    = "Flows"
+	KindGroups   = "Groups"
+	KindTemplate = "Template"
+)
+
+// singleFileDocument is a document of a single-file taxonomy with its kind key removed.
+type singleFileDocument struct {
+	location string
+	data     []byte
+}
+
+// SingleFileSegRepository implements taxonomy.SegRepository for a single multi-document YAML stream.
+// Every document declares its kind (L1, L2, ..., Flows, Groups or Template) and is otherwise the same as a
+// file of that kind in a FileSegRepository, validated against the same schema.
+type SingleFileSegRepository struct {
+	schemaValidator *schemaValidation.SchemaValidator
+	name            string
+	docs            map[string][]singleFileDocument
+}
+
+// NewSingleFileSegRepository creates a repository reading the file at cfg.Path, or stdin for StdinPath.
+// depth is the number of segment levels, documents of deeper levels are rejected.
+func NewSingleFileSegRepository(schemaValidator *schemaValidation.SchemaValidator, cfg ConfigRepository, depth int) (*SingleFileSegRepository, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("repository.path is required for the file repository")
+	}
+	var data []byte
+	var err error
+	name := cfg.Path
+	if cfg.Path == StdinPath {
+		name = "stdin"
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		// #nosec G304 -- path comes from the config file
+		data, err = os.ReadFile(cfg.Path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return NewSingleFileSegRepositoryFromData(schemaValidator, data, name, depth)
+}
+
+// NewSingleFileSegRepositoryFromData creates a repository from a YAML stream; name is only used in errors.
+func NewSingleFileSegRepositoryFromData(schemaValidator *schemaValidation.SchemaValidator, data []byte, name string, depth int) (*SingleFileSegRepository, error) {
+	r := &SingleFileSegRepository{
+		schemaValidator: schemaValidator,
+		name:            name,
+		docs:            make(map[string][]singleFileDocument),
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var parseErrors []error
+	for index := 1; ; index++ {
+		var node yaml.Node
+		if err := decoder.Decode(&node); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse %s document %d: %w", name, index, err)
+		}
+		if len(node.Content) == 0 {
+			continue // empty document, e.g. a leading ---
+		}
+		location := fmt.Sprintf("%s document %d (line %d)", name, index, node.Content[0].Line)
+		kind, doc, err := splitKind(node.Content[0], depth)
+		if err == nil {
+			var docData []byte
+			docData, err = yaml.Marshal(doc)
+			r.docs[kind] = append(r.docs[kind], singleFileDocument{location: location, data: docData})
+		}
+		if err != nil {
+			o11y.Log.Printf("Error parsing %s: %v\n", location, err)
+			parseErrors = append(parseErrors, fmt.Errorf("%s: %w", location, err))
+		}
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d document(s) in %s", len(parseErrors), name)
+	}
+	return r, nil
+}
+
+// splitKind returns the kind of a document and the document without its kind key.
+func splitKind(doc *yaml.Node, depth int) (string, *yaml.Node, error) {
+	if doc.Kind != yaml.MappingNode {
+		return "", nil, errors.New("document is not a mapping")
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != "kind" {
+			continue
+		}
+		kind := doc.Content[i+1].Value
+		if !validKind(kind, depth) {
+			return "", nil, fmt.Errorf("unknown kind %q, expected L1 to L%d, %s, %s or %s", kind, depth, KindFlows, KindGroups, KindTemplate)
+		}
+		rest := *doc
+		rest.Content = append(append([]*yaml.Node{}, doc.Content[:i]...), doc.Content[i+2:]...)
+		return kind, &rest, nil
+	}
+	return "", nil, errors.New("missing kind")
+}
+
+func validKind(kind string, depth int) bool {
+	switch kind {
+	case KindFlows, KindGroups, KindTemplate:
+		return true
+	}
+	if !strings.HasPrefix(kind, "L") {
+		return false
+	}
+	level, err := strconv.Atoi(kind[1:])
+	return err == nil && level >= 1 && level <= depth && kind[1:] == strconv.Itoa(level)
+}
+
+// HasLevel reports whether the stream has documents of the level.
+func (r *SingleFileSegRepository) HasLevel(level string) bool {
+	return len(r.docs["L"+level]) > 0
+}
+
+func (r *SingleFileSegRepository) LoadLevel(level string) ([]domain.Seg, error) {
+	var segList []domain.Seg
+	var parseErrors []error
+	for _, doc := range r.docs["L"+level] {
+		seg, err := parseSegData(r.schemaValidator, doc.data, doc.location, level)
+		if err != nil {
+			o11y.Log.Printf("Error parsing %s: %v\n", doc.location, err)
+			parseErrors = append(parseErrors, err)
+			continue
+		}
+		segList = append(segList, seg)
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d L%s document(s) in %s", len(parseErrors), level, r.name)
+	}
+	return segList, nil
+}
+
+// LoadFlows loads the flows of every Flows document.
+func (r *SingleFileSegRepository) LoadFlows() ([]domain.Flow, error) {
+	var flows []domain.Flow
+	var parseErrors []error
+	for _, doc := range r.docs[KindFlows] {
+		flowsDoc, err := parseFlowsData(r.schemaValidator, doc.data, doc.location)
+		if err != nil {
+			o11y.Log.Printf("Error parsing %s: %v\n", doc.location, err)
+			parseErrors = append(parseErrors, err)
+			continue
+		}
+		flows = append(flows, flowsDoc.Flows...)
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d %s document(s) in %s", len(parseErrors), KindFlows, r.name)
+	}
+	return flows, nil
+}
+
+// LoadGroups loads the groups of every Groups document.
+func (r *SingleFileSegRepository) LoadGroups() ([]domain.Group, error) {
+	var groups []domain.Group
+	var parseErrors []error
+	for _, doc := range r.docs[KindGroups] {
+		groupsDoc, err := parseGroupsData(r.schemaValidator, doc.data, doc.location)
+		if err != nil {
+			o11y.Log.Printf("Error parsing %s: %v\n", doc.location, err)
+			parseErrors = append(parseErrors, err)
+			continue
+		}
+		groups = append(groups, groupsDoc.Groups...)
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d %s document(s) in %s", len(parseErrors), KindGroups, r.name)
+	}
+	return groups, nil
+}
+
+// LoadTemplates loads every Template document, one template per document.
+func (r *SingleFileSegRepository) LoadTemplates() ([]domain.Template, error) {
+	var templates []domain.Template
+	var parseErrors []error
+	for _, doc := range r.docs[KindTemplate] {
+		template, err := parseTemplateData(r.schemaValidator, doc.data, doc.location)
+		if err != nil {
+			o11y.Log.Printf("Error parsing %s: %v\n", doc.location, err)
+			parseErrors = append(parseErrors, err)
+			continue
+		}
+		templates = append(templates, template)
+	}
+	if len(parseErrors) > 0 {
+		return nil, fmt.Errorf("failed to parse %d %s document(s) in %s", len(parseErrors), KindTemplate, r.name)
+	}
+	return templates, nil
+}
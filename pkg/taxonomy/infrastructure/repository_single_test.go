@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
+)
+
+const testDescription = "Description long enough for the schema, which asks for at least seventy characters."
+
+var singleFileTaxonomy = `---
+kind: L1
+name: Production
+id: prod
+description: ` + testDescription + `
+---
+kind: L1
+name: Staging
+id: staging
+description: ` + testDescription + `
+---
+kind: L2
+name: App
+id: app
+description: ` + testDescription + `
+l1_parents: [prod, staging]
+---
+kind: Flows
+flows:
+  - id: staging-to-prod
+    level: 1
+    source: staging
+    destination: prod
+    protocol: tcp
+    ports: ["443"]
+    direction: one-way
+    rationale: "Deployments promote builds from staging."
+---
+kind: Groups
+groups:
+  - id: eu
+    name: Europe
+---
+kind: Template
+id: base
+labels: ["ns/key:value"]
+`
+
+func TestSingleFileSegRepository(t *testing.T) {
+	validator := schemaValidation.MustCreateValidator(t)
+
+	t.Run("Loads every kind of document", func(t *testing.T) {
+		repository, err := NewSingleFileSegRepositoryFromData(validator, []byte(singleFileTaxonomy), "taxonomy.yaml", 2)
+		if err != nil {
+			t.Fatalf("NewSingleFileSegRepositoryFromData: unexpected error: %v", err)
+		}
+
+		l1s, err := repository.LoadLevel("1")
+		if err != nil || len(l1s) != 2 || l1s[0].Level != "1" {
+			t.Errorf("Expected 2 L1 segments, got %+v, %v", l1s, err)
+		}
+		l2s, err := repository.LoadLevel("2")
+		if err != nil || len(l2s) != 1 || l2s[0].ID != "app" {
+			t.Errorf("Expected L2 app, got %+v, %v", l2s, err)
+		}
+		flows, err := repository.LoadFlows()
+		if err != nil || len(flows) != 1 {
+			t.Errorf("Expected 1 flow, got %+v, %v", flows, err)
+		}
+		groups, err := repository.LoadGroups()
+		if err != nil || len(groups) != 1 || groups[0].ID != "eu" {
+			t.Errorf("Expected group eu, got %+v, %v", groups, err)
+		}
+		templates, err := repository.LoadTemplates()
+		if err != nil || len(templates) != 1 || templates[0].ID != "base" {
+			t.Errorf("Expected template base, got %+v, %v", templates, err)
+		}
+	})
+
+	t.Run("Rejects documents without a known kind", func(t *testing.T) {
+		for _, doc := range []string{
+			"name: Production\nid: prod\ndescription: Production\n",
+			"kind: L3\nname: Production\nid: prod\ndescription: Production\n",
+			"kind: Segment\nname: Production\nid: prod\ndescription: Production\n",
+			"- kind: L1\n",
+		} {
+			if _, err := NewSingleFileSegRepositoryFromData(validator, []byte(doc), "taxonomy.yaml", 2); err == nil {
+				t.Errorf("Expected error for document %q", doc)
+			}
+		}
+	})
+
+	t.Run("Validates each document against its schema", func(t *testing.T) {
+		repository, err := NewSingleFileSegRepositoryFromData(validator, []byte(singleFileTaxonomy+"---\nkind: L1\nname: Dev\nid: dev\n"), "taxonomy.yaml", 2)
+		if err != nil {
+			t.Fatalf("NewSingleFileSegRepositoryFromData: unexpected error: %v", err)
+		}
+		if _, err := repository.LoadLevel("1"); err == nil {
+			t.Error("Expected schema validation error for L1 without description")
+		}
+	})
+
+	t.Run("Reads the configured file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "taxonomy.yaml")
+		if err := os.WriteFile(path, []byte(singleFileTaxonomy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		repository, err := NewSingleFileSegRepository(validator, ConfigRepository{Type: RepositoryTypeFile, Path: path}, 2)
+		if err != nil {
+			t.Fatalf("NewSingleFileSegRepository: unexpected error: %v", err)
+		}
+		if segs, err := repository.LoadLevel("2"); err != nil || len(segs) != 1 {
+			t.Errorf("Expected 1 L2 segment, got %+v, %v", segs, err)
+		}
+		if _, err := NewSingleFileSegRepository(validator, ConfigRepository{Type: RepositoryTypeFile}, 2); err == nil {
+			t.Error("Expected error without a path")
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
+)
+
+// maxTarballFileSize limits the size of each file extracted from a taxonomy tarball.
+const maxTarballFileSize = 10 << 20
+
+// NewTarballSegRepository extracts the taxonomy directory archived at archivePath, a .tar or .tar.gz file,
+// into a temporary directory and returns a FileSegRepository reading it. cleanup removes the directory.
+func NewTarballSegRepository(schemaValidator *schemaValidation.SchemaValidator, cfg ConfigFsReposistory, archivePath string) (repository *FileSegRepository, cleanup func(), err error) {
+	dir, err := os.MkdirTemp("", "bunsceal-tarball-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() { _ = os.RemoveAll(dir) }
+	if err = extractTarball(archivePath, dir); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to extract %s: %w", archivePath, err)
+	}
+	cfg.TaxonomyDir = dir
+	return NewFileSegRepository(schemaValidator, cfg), cleanup, nil
+}
+
+// extractTarball extracts the directories and regular files of a tar archive, gzip compressed or not, into dir.
+func extractTarball(archivePath, dir string) error {
+	// #nosec G304 -- archivePath comes from the config file
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var stream io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	archive := tar.NewReader(stream)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue // pax_global_header written by git archive, holds no file
+		}
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %s is outside the archive root", header.Name)
+		}
+		target := filepath.Join(dir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, 0750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if header.Size > maxTarballFileSize {
+				return fmt.Errorf("archive entry %s is larger than %d bytes", header.Name, maxTarballFileSize)
+			}
+			if err = os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+				return err
+			}
+			if err = writeTarballFile(target, archive); err != nil {
+				return err
+			}
+		default:
+			return errors.New("archive entry " + header.Name + " is not a regular file or directory")
+		}
+	}
+}
+
+func writeTarballFile(target string, r io.Reader) error {
+	// #nosec G304 -- target is checked to be inside the extraction directory
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.CopyN(out, r, maxTarballFileSize+1); err != nil && err != io.EOF {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
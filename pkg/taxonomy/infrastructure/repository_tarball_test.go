@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/domain/schemaValidation"
+	"github.com/kvql/bunsceal/pkg/domain/testhelpers"
+)
+
+// writeTarball writes an uncompressed tar archive with the given files and returns its path.
+func writeTarball(t *testing.T, files [][2]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "taxonomy.tar")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := tar.NewWriter(file)
+	for _, f := range files {
+		if err := archive.WriteHeader(&tar.Header{Name: f[0], Mode: 0o600, Size: int64(len(f[1])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(f[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewTarballSegRepository(t *testing.T) {
+	validator := schemaValidation.MustCreateValidator(t)
+	cfg := ConfigFsReposistory{L1Dir: "l1", L2Dir: "l2"}
+
+	t.Run("Loads segments from the archive", func(t *testing.T) {
+		archive := writeTarball(t, [][2]string{{"l1/prod.yaml", "name: Production\nid: prod\ndescription: Description long enough for the schema, which asks for at least seventy characters.\n"}})
+		repository, cleanup, err := NewTarballSegRepository(validator, cfg, archive)
+		if err != nil {
+			t.Fatalf("NewTarballSegRepository: unexpected error: %v", err)
+		}
+		defer cleanup()
+
+		if !repository.HasLevel("1") || repository.HasLevel("2") {
+			t.Error("Expected the archive to have L1 only")
+		}
+		segs, err := repository.LoadLevel("1")
+		if err != nil || len(segs) != 1 || segs[0].ID != "prod" {
+			t.Errorf("Expected L1 prod, got %+v, %v", segs, err)
+		}
+	})
+
+	t.Run("Loads git archive tarballs", func(t *testing.T) {
+		if !CheckGit() {
+			t.Skip("git not available")
+		}
+		repoDir := newGitTaxonomyRepo(t, []domain.Seg{
+			testhelpers.NewSegL1("env-one", "Environment 1", "A", "1", nil),
+		})
+		// Archiving a commit, not a tree, writes its ID to a pax global header
+		archive := filepath.Join(t.TempDir(), "taxonomy.tar.gz")
+		if out, err := exec.Command("git", "-C", repoDir, "archive", "--format=tar.gz", "-o", archive, "HEAD").CombinedOutput(); err != nil {
+			t.Fatalf("git archive: %v: %s", err, out)
+		}
+
+		repository, cleanup, err := NewTarballSegRepository(validator, ConfigFsReposistory{L1Dir: "taxonomy/l1", L2Dir: "taxonomy/l2"}, archive)
+		if err != nil {
+			t.Fatalf("NewTarballSegRepository: unexpected error: %v", err)
+		}
+		defer cleanup()
+		if segs, err := repository.LoadLevel("1"); err != nil || len(segs) != 1 {
+			t.Errorf("Expected L1 env-one, got %+v, %v", segs, err)
+		}
+	})
+
+	t.Run("Rejects entries outside the archive root", func(t *testing.T) {
+		archive := writeTarball(t, [][2]string{{"../escape.yaml", "id: x\n"}})
+		if _, _, err := NewTarballSegRepository(validator, cfg, archive); err == nil {
+			t.Error("Expected error for entry outside the archive root")
+		}
+	})
+}
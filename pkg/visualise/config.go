@@ -2,7 +2,8 @@ package visualise
 
 // VisualsDef Config for how taxonomy is visualised
 type VisualsDef struct {
-	L1Layout map[string][]string `yaml:"l1_layout,omitempty"`
+	L1Layout  map[string][]string `yaml:"l1_layout,omitempty"`
+	GroupRows bool                `yaml:"group_rows,omitempty"`
 }
 
 const VisualiseConfigSchema = `{
@@ -27,6 +28,11 @@ const VisualiseConfigSchema = `{
 							}
 						}
 					}
+				},
+				"group_rows": {
+					"type": "boolean",
+					"description": "Lay out one row per L0 group, labelled with the group name, when l1_layout is not set",
+					"default": false
 				}
 			}
 		}
@@ -21,7 +21,8 @@ const unknownGroupKey = "noGroup"
 
 // buildRowsMap creates a rowsMap from the config's L1Layout.
 // Returns map[int][]string to maintain ordering when iterating sequentially.
-// If no L1Layout is configured, defaults to all L1s on row 0.
+// If no L1Layout is configured, defaults to all L1s on row 0, or to one row per group
+// (ungrouped L1s last) when GroupRows is set.
 // If an L1 ID exists in the taxonomy but is not in the config layout,
 // it will be added to the last row to ensure all L1s are included in visualisations.
 // Returns an error if the config references L1 IDs that don't exist in the taxonomy.
@@ -29,6 +30,10 @@ func buildRowsMap(cfg VisualsDef, txy domain.Taxonomy) (map[int][]string, error)
 	result := make(map[int][]string)
 	seenL1s := make(map[string]bool)
 
+	if len(cfg.L1Layout) == 0 && cfg.GroupRows && len(txy.Groups) > 0 {
+		return buildGroupRowsMap(txy), nil
+	}
+
 	// If no L1Layout is configured, default to all L1s on a single row
 	if len(cfg.L1Layout) == 0 {
 		allL1s := []string{}
@@ -84,6 +89,122 @@ func buildRowsMap(cfg VisualsDef, txy domain.Taxonomy) (map[int][]string, error)
 	return result, nil
 }
 
+// buildGroupRowsMap lays out one row per group in group ID order, with ungrouped L1s on a final row.
+func buildGroupRowsMap(txy domain.Taxonomy) map[int][]string {
+	groupIDs := make([]string, 0, len(txy.Groups))
+	for id := range txy.Groups {
+		groupIDs = append(groupIDs, id)
+	}
+	sort.Strings(groupIDs)
+
+	result := make(map[int][]string)
+	for _, id := range groupIDs {
+		if members := txy.GroupMembers(id); len(members) > 0 {
+			result[len(result)] = members
+		}
+	}
+	if ungrouped := txy.GroupMembers(""); len(ungrouped) > 0 {
+		result[len(result)] = ungrouped
+	}
+	return result
+}
+
+// rowGroupLabel returns the name of the group shared by every L1 in a row when group rows are
+// enabled, or an empty string when the row is not a single group.
+func rowGroupLabel(cfg VisualsDef, txy domain.Taxonomy, l1IDs []string) string {
+	if !cfg.GroupRows || len(l1IDs) == 0 {
+		return ""
+	}
+	groupID := txy.SegL1s[l1IDs[0]].Group
+	for _, l1ID := range l1IDs[1:] {
+		if txy.SegL1s[l1ID].Group != groupID {
+			return ""
+		}
+	}
+	group, ok := txy.Groups[groupID]
+	if !ok {
+		return ""
+	}
+	return group.Name
+}
+
+// applyPresenters returns a copy of the taxonomy and layout adjusted by plugins implementing
+// plugins.SegmentPresenter: hidden segments are removed (hiding an L1 also detaches it from its
+// children) and display suffixes are appended to segment names. The input taxonomy is not modified.
+func applyPresenters(txy domain.Taxonomy, cfg VisualsDef, pluginMap plugins.Plugins) (domain.Taxonomy, VisualsDef) {
+	var presenters []plugins.SegmentPresenter
+	for _, plugin := range pluginMap {
+		if presenter, ok := plugin.(plugins.SegmentPresenter); ok {
+			presenters = append(presenters, presenter)
+		}
+	}
+	if len(presenters) == 0 {
+		return txy, cfg
+	}
+
+	visible := func(seg domain.Seg, parentID string) bool {
+		for _, presenter := range presenters {
+			if !presenter.Visible(seg, parentID) {
+				return false
+			}
+		}
+		return true
+	}
+	suffix := func(seg domain.Seg) string {
+		result := ""
+		for _, presenter := range presenters {
+			result += presenter.DisplaySuffix(seg)
+		}
+		return result
+	}
+
+	result := domain.Taxonomy{
+		ApiVersion: txy.ApiVersion,
+		SegL1s:     make(map[string]domain.Seg),
+		SegsL2s:    make(map[string]domain.Seg),
+		Groups:     txy.Groups,
+	}
+	for id, seg := range txy.SegL1s {
+		if !visible(seg, "") {
+			continue
+		}
+		seg.Name += suffix(seg)
+		result.SegL1s[id] = seg
+	}
+	for id, seg := range txy.SegsL2s {
+		parents := []string{}
+		for _, l1ID := range seg.L1Parents {
+			if _, shown := result.SegL1s[l1ID]; shown && visible(seg, l1ID) {
+				parents = append(parents, l1ID)
+			}
+		}
+		if len(parents) == 0 {
+			continue
+		}
+		seg.L1Parents = parents
+		seg.Name += suffix(seg)
+		result.SegsL2s[id] = seg
+	}
+
+	layout := cfg
+	if len(cfg.L1Layout) > 0 {
+		layout.L1Layout = make(map[string][]string)
+		for row, l1IDs := range cfg.L1Layout {
+			shown := []string{}
+			for _, l1ID := range l1IDs {
+				_, known := txy.SegL1s[l1ID]
+				_, kept := result.SegL1s[l1ID]
+				// Unknown IDs are kept so buildRowsMap still reports them
+				if kept || !known {
+					shown = append(shown, l1ID)
+				}
+			}
+			layout.L1Layout[row] = shown
+		}
+	}
+	return result, layout
+}
+
 type L2GroupingData struct {
 	SortedSegs         []string
 	PresentGroupValues map[string]bool
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/kvql/bunsceal/pkg/domain"
+	"github.com/kvql/bunsceal/pkg/taxonomy/application/plugins"
 )
 
 func TestBuildRowsMap(t *testing.T) {
@@ -117,6 +118,38 @@ func TestBuildRowsMap(t *testing.T) {
 		}
 	})
 
+	t.Run("Lays out one labelled row per group when GroupRows is set", func(t *testing.T) {
+		vis := VisualsDef{GroupRows: true}
+
+		txy := domain.Taxonomy{
+			Groups: map[string]domain.Group{
+				"eu": {ID: "eu", Name: "Europe"},
+				"us": {ID: "us", Name: "United States"},
+			},
+			SegL1s: map[string]domain.Seg{
+				"prod-us": {ID: "prod-us", Group: "us"},
+				"prod-eu": {ID: "prod-eu", Group: "eu"},
+				"dev-eu":  {ID: "dev-eu", Group: "eu"},
+				"shared":  {ID: "shared"},
+			},
+		}
+
+		result, err := buildRowsMap(vis, txy)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(result) != 3 || len(result[0]) != 2 || result[1][0] != "prod-us" || result[2][0] != "shared" {
+			t.Errorf("Expected rows [eu, us, ungrouped], got %v", result)
+		}
+		if label := rowGroupLabel(vis, txy, result[0]); label != "Europe" {
+			t.Errorf("Expected row 0 to be labelled Europe, got %q", label)
+		}
+		if label := rowGroupLabel(vis, txy, result[2]); label != "" {
+			t.Errorf("Expected ungrouped row to have no label, got %q", label)
+		}
+	})
+
 	t.Run("Defaults to single row when L1Layout is nil", func(t *testing.T) {
 		vis := VisualsDef{
 			L1Layout: nil,
@@ -213,3 +246,47 @@ func TestBuildRowsMap(t *testing.T) {
 		}
 	})
 }
+
+func TestApplyPresenters(t *testing.T) {
+	lifecycle := plugins.NewLifecyclePlugin(&plugins.LifecycleConfig{HideRetired: true}, plugins.NsPrefix)
+	pluginMap := plugins.Plugins{"lifecycle": lifecycle}
+
+	newSeg := func(id string, parents []string, labels []string) domain.Seg {
+		seg := domain.Seg{ID: id, Name: id, L1Parents: parents, Labels: labels}
+		seg.ParseLabels()
+		return seg
+	}
+	txy := domain.Taxonomy{
+		SegL1s: map[string]domain.Seg{
+			"prod":   newSeg("prod", nil, nil),
+			"legacy": newSeg("legacy", nil, []string{"bunsceal.plugin.lifecycle/state:retired"}),
+		},
+		SegsL2s: map[string]domain.Seg{
+			"app": newSeg("app", []string{"prod", "legacy"}, []string{"bunsceal.plugin.lifecycle/state:deprecated"}),
+			"old": newSeg("old", []string{"legacy"}, nil),
+		},
+	}
+	cfg := VisualsDef{L1Layout: map[string][]string{"0": {"prod", "legacy"}}}
+
+	result, layout := applyPresenters(txy, cfg, pluginMap)
+
+	if _, ok := result.SegL1s["legacy"]; ok {
+		t.Error("Expected retired L1 to be removed")
+	}
+	if _, ok := result.SegsL2s["old"]; ok {
+		t.Error("Expected L2 with only retired parents to be removed")
+	}
+	app := result.SegsL2s["app"]
+	if len(app.L1Parents) != 1 || app.L1Parents[0] != "prod" {
+		t.Errorf("Expected app parents [prod], got %v", app.L1Parents)
+	}
+	if app.Name != "app [deprecated]" {
+		t.Errorf("Expected deprecated suffix on name, got %q", app.Name)
+	}
+	if len(layout.L1Layout["0"]) != 1 || layout.L1Layout["0"][0] != "prod" {
+		t.Errorf("Expected layout row 0 to be [prod], got %v", layout.L1Layout["0"])
+	}
+	if len(txy.SegsL2s["app"].L1Parents) != 2 || txy.SegsL2s["app"].Name != "app" {
+		t.Error("Expected input taxonomy to be unchanged")
+	}
+}
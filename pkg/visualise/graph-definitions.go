@@ -44,8 +44,7 @@ func GraphL1(txy domain.Taxonomy, terms domain.TermConfig, visCfg VisualsDef, al
 	for row := 0; row < len(rowsLayout); row++ {
 		orderNodes := []string{}
 		rowSubGraphName := fmt.Sprintf("\"cluster_row_%d\"", row)
-		rowAtt := CopyInvis()
-		rowAtt["label"] = fmt.Sprintf("\"Invisible Row subgraph: %d\"", row) // help with debugging graph structure
+		rowAtt := rowGraphAtt(row, rowGroupLabel(visCfg, txy, rowsLayout[row]))
 		g.AddSubGraph("top_level_graph", rowSubGraphName, rowAtt)
 		// Environment subgraphs
 		envIds := rowsLayout[row]
@@ -72,6 +71,17 @@ func GraphL1(txy domain.Taxonomy, terms domain.TermConfig, visCfg VisualsDef, al
 	return g, nil
 }
 
+// rowGraphAtt returns the attributes for a row subgraph: a labelled cluster when the row holds a
+// single group, otherwise an invisible one.
+func rowGraphAtt(row int, groupLabel string) map[string]string {
+	if groupLabel != "" {
+		return FormatGraph(fmt.Sprintf("\"%s\"", groupLabel), "")
+	}
+	rowAtt := CopyInvis()
+	rowAtt["label"] = fmt.Sprintf("\"Invisible Row subgraph: %d\"", row) // help with debugging graph structure
+	return rowAtt
+}
+
 // ################################
 // Function to Segment Level 2 Graphs
 // ################################
@@ -108,8 +118,7 @@ func GraphL2Grouped(txy domain.Taxonomy, terms domain.TermConfig, visCfg Visuals
 		// They must also be set at the most granular level, which is the batches for each security domain
 		orderNodes := map[string]map[string][]string{}
 		rowSubGraphName := fmt.Sprintf("\"cluster_row_%d\"", row)
-		rowAtt := CopyInvis()
-		rowAtt["label"] = fmt.Sprintf("\"Invisible Row subgraph: %d\"", row) // help with debugging graph structure
+		rowAtt := rowGraphAtt(row, rowGroupLabel(visCfg, txy, rowsLayout[row]))
 		g.AddSubGraph("top_level_graph", rowSubGraphName, rowAtt)
 
 		// Environment subgraphs
@@ -82,6 +82,9 @@ func renderGraph(g *gographviz.Graph, dir string, name string) error {
 
 // RenderDiagrams generates all the diagrams for the taxonomy
 func RenderDiagrams(tax domain.Taxonomy, dir string, terms domain.TermConfig, visCfg VisualsDef, pluginMap plugins.Plugins) error {
+	// Hide or annotate segments based on plugin state (e.g. retired segments)
+	tax, visCfg = applyPresenters(tax, visCfg, pluginMap)
+
 	// Collect image data from all plugins
 	var groupData []plugins.ImageGroupingData
 	for _, plugin := range pluginMap {